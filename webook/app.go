@@ -1,13 +1,30 @@
 package main
 
 import (
+	"github.com/IBM/sarama"
 	"github.com/gin-gonic/gin"
 	"github.com/robfig/cron/v3"
 	"github.com/wsqigo/basic-go/webook/internal/events"
+	"github.com/wsqigo/basic-go/webook/internal/job"
+	"github.com/wsqigo/basic-go/webook/internal/service/sms/async"
+	"github.com/wsqigo/basic-go/webook/ioc"
 )
 
 type App struct {
 	server    *gin.Engine
 	consumers []events.Consumer
 	cron      *cron.Cron
+	scheduler *job.Scheduler
+	balancer  *job.LoadBalancer
+	// 退出的时候要释放热榜的分布式锁
+	rankingJob *job.RankingJob
+	// 退出的时候要释放 outbox 投递的分布式锁
+	outboxJob *job.OutboxRelayJob
+	lifecycle *ioc.Lifecycle
+	// 退出之前要把缓冲里面的消息发出去
+	asyncProducer sarama.AsyncProducer
+	// 后台异步发送短信
+	asyncSms *async.Service
+	// 内部的短信接口，没有启用的时候是 nil
+	smsServer *ioc.InternalSMSServer
 }
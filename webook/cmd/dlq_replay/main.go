@@ -0,0 +1,51 @@
+// dlq_replay 把某个消费者组的死信重新投递到这个组的重试 topic
+// 在 webook 目录下执行：
+// go run ./cmd/dlq_replay --topic article_read --group history --duration 30s
+package main
+
+import (
+	"context"
+	"fmt"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"github.com/wsqigo/basic-go/webook/ioc"
+	"github.com/wsqigo/basic-go/webook/pkg/saramax"
+	"time"
+)
+
+func main() {
+	cfile := pflag.String("config", "config/dev.yaml", "配置文件路径")
+	topic := pflag.String("topic", "", "原本的 topic，不带 .<group>.dlq 后缀")
+	group := pflag.String("group", "", "处理失败的消费者组")
+	duration := pflag.Duration("duration", 30*time.Second, "重放多久，死信不多的话这段时间内会全部重放完")
+	pflag.Parse()
+	if *topic == "" || *group == "" {
+		panic("必须指定 topic 和 group")
+	}
+	viper.SetConfigType("yaml")
+	viper.SetConfigFile(*cfile)
+	err := viper.ReadInConfig()
+	if err != nil {
+		panic(err)
+	}
+	type Config struct {
+		Addr []string `yaml:"addr"`
+	}
+	var cfg Config
+	err = viper.UnmarshalKey("kafka", &cfg)
+	if err != nil {
+		panic(err)
+	}
+	l := ioc.InitLogger()
+	client := ioc.InitSaramaClient()
+	defer client.Close()
+	producer := ioc.InitSyncProducer(client)
+	replayer := saramax.NewDLQReplayer(cfg.Addr, producer, l)
+	ctx, cancel := context.WithTimeout(context.Background(), *duration)
+	defer cancel()
+	cnt, err := replayer.Replay(ctx, *topic, *group)
+	fmt.Printf("重放了 %d 条死信\n", cnt)
+	if err != nil {
+		panic(err)
+	}
+}
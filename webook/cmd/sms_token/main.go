@@ -0,0 +1,37 @@
+// sms_token 给内部短信接口的调用方签发 token
+// 在 webook 目录下执行：
+// SMS_API_KEY_2026_10=xxx go run ./cmd/sms_token --caller order --expiration 2160h
+package main
+
+import (
+	"fmt"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"github.com/wsqigo/basic-go/webook/ioc"
+	"time"
+)
+
+func main() {
+	cfile := pflag.String("config", "config/dev.yaml", "配置文件路径")
+	caller := pflag.String("caller", "", "调用方，要和 sms.api.callers 里面的 name 对上")
+	expiration := pflag.Duration("expiration", 90*24*time.Hour, "token 的有效期")
+	pflag.Parse()
+	if *caller == "" {
+		panic("必须指定 caller")
+	}
+	viper.SetConfigType("yaml")
+	viper.SetConfigFile(*cfile)
+	err := viper.ReadInConfig()
+	if err != nil {
+		panic(err)
+	}
+	tokens, err := ioc.LoadSMSTokenManager()
+	if err != nil {
+		panic(err)
+	}
+	token, err := tokens.Issue(*caller, *expiration)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(token)
+}
@@ -0,0 +1,12 @@
+package domain
+
+// CodeSendRequest 发送验证码的请求，防刷的时候要用到来源
+type CodeSendRequest struct {
+	Biz   string
+	Phone string
+	IP    string
+	// 客户端上报的设备 ID，可能为空
+	DeviceId string
+	// 人机校验之后拿到的凭证
+	HumanToken string
+}
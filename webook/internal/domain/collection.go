@@ -0,0 +1,23 @@
+package domain
+
+import "time"
+
+// Collection 收藏夹
+type Collection struct {
+	Id   int64
+	Uid  int64
+	Name string
+	// 收藏夹里面有多少个东西
+	ItemCnt int64
+	Ctime   time.Time
+	Utime   time.Time
+}
+
+// CollectionItem 收藏夹里面的一个收藏
+type CollectionItem struct {
+	Cid   int64
+	Uid   int64
+	Biz   string
+	BizId int64
+	Ctime time.Time
+}
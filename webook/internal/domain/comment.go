@@ -0,0 +1,27 @@
+package domain
+
+import "time"
+
+type Comment struct {
+	Id int64
+	// 评论者
+	Commentator User
+	// 评论的对象，比如说 <article, 123>
+	Biz   string
+	BizId int64
+	// 评论的内容
+	Content string
+	// 根评论，为 nil 说明自己就是根评论
+	RootComment *Comment
+	// 父评论，也就是你直接回复的那一条评论
+	ParentComment *Comment
+	// 下面的回复，只有根评论才会带上
+	Children []Comment
+	Ctime    time.Time
+	Utime    time.Time
+}
+
+// IsRoot 是不是根评论
+func (c Comment) IsRoot() bool {
+	return c.RootComment == nil
+}
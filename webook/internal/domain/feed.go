@@ -0,0 +1,10 @@
+package domain
+
+import "time"
+
+// FeedItem feed 流里面的一条
+type FeedItem struct {
+	Article Article
+	// 进入 feed 流的时间，也是翻页用的游标
+	Ctime time.Time
+}
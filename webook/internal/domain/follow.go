@@ -0,0 +1,21 @@
+package domain
+
+import "time"
+
+// FollowRelation 关注关系，Follower 关注了 Followee
+type FollowRelation struct {
+	// 用来做游标翻页
+	Id       int64
+	Follower int64
+	Followee int64
+	Ctime    time.Time
+}
+
+// FollowStatics 一个用户的关注数据
+type FollowStatics struct {
+	Uid int64
+	// 有多少粉丝
+	Followers int64
+	// 关注了多少人
+	Followees int64
+}
@@ -1,7 +1,12 @@
 package domain
 
+import "time"
+
 type HistoryRecord struct {
+	Id    int64
 	BizId int64
 	Biz   string
 	Uid   int64
+	// ReadTime 最近一次阅读的时间
+	ReadTime time.Time
 }
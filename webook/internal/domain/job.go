@@ -1,10 +1,14 @@
 package domain
 
 import (
+	"github.com/ecodeclub/ekit/retry"
 	"github.com/robfig/cron/v3"
 	"time"
 )
 
+var jobParser = cron.NewParser(cron.Second | cron.Minute | cron.Hour |
+	cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+
 type Job struct {
 	Id   int64
 	Name string
@@ -12,12 +16,113 @@ type Job struct {
 	Expression string
 	Executor   string
 	Cfg        string
+	// 执行失败之后重试的配置，RetryMax 为 0 表示不重试
+	RetryMax      int
+	RetryInterval time.Duration
+	Status        JobStatus
+	// Version 每次抢占都会加一，续约和释放的时候用来确认任务还是自己的
+	Version int
+	// NextRunTime 下一次调度的时间
+	NextRunTime time.Time
+	// LastRunTime 最近一次开始执行的时间，没有执行过就是零值
+	LastRunTime time.Time
+	// LastRunErr 最近一次执行的错误信息，空字符串代表成功
+	LastRunErr string
+	Ctime      time.Time
+	Utime      time.Time
 	CancelFunc func() // 分布式锁释放
+	// LeaseLost 续约的时候发现任务被别的节点抢走了，就会被关闭，执行者应该尽快退出
+	LeaseLost <-chan struct{}
+}
+
+// ValidateExpression 校验 cron 表达式，支持秒级
+func (j Job) ValidateExpression() error {
+	_, err := jobParser.Parse(j.Expression)
+	return err
+}
+
+// RetryStrategy 执行失败之后的重试策略，不需要重试的时候返回 nil
+func (j Job) RetryStrategy() retry.Strategy {
+	if j.RetryMax <= 0 || j.RetryInterval <= 0 {
+		return nil
+	}
+	// 参数已经校验过了，不会出错
+	s, _ := retry.NewFixedIntervalRetryStrategy(j.RetryInterval, int32(j.RetryMax))
+	return s
 }
 
 func (j Job) NextTime() time.Time {
-	c := cron.NewParser(cron.Second | cron.Minute | cron.Hour |
-		cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
-	s, _ := c.Parse(j.Expression)
+	s, err := jobParser.Parse(j.Expression)
+	if err != nil {
+		// 写入的时候已经校验过，走到这里说明数据被人手动改坏了
+		return time.Time{}
+	}
 	return s.Next(time.Now())
 }
+
+type JobStatus uint8
+
+func (s JobStatus) ToUint8() uint8 {
+	return uint8(s)
+}
+
+func (s JobStatus) String() string {
+	switch s {
+	case JobStatusWaiting:
+		return "waiting"
+	case JobStatusRunning:
+		return "running"
+	case JobStatusPaused:
+		return "paused"
+	default:
+		return "unknown"
+	}
+}
+
+const (
+	// JobStatusWaiting 等待调度
+	JobStatusWaiting JobStatus = iota
+	// JobStatusRunning 已经被某个节点抢占了
+	JobStatusRunning
+	// JobStatusPaused 暂停了，不再调度
+	JobStatusPaused
+)
+
+// JobExecution 任务的一次执行
+type JobExecution struct {
+	Id       int64
+	Jid      int64
+	Executor string
+	NodeId   string
+	// EndTime 还没执行完就是零值
+	StartTime time.Time
+	EndTime   time.Time
+	Status    JobExecutionStatus
+	ErrMsg    string
+}
+
+type JobExecutionStatus uint8
+
+func (s JobExecutionStatus) ToUint8() uint8 {
+	return uint8(s)
+}
+
+func (s JobExecutionStatus) String() string {
+	switch s {
+	case JobExecutionStatusRunning:
+		return "running"
+	case JobExecutionStatusSuccess:
+		return "success"
+	case JobExecutionStatusFailed:
+		return "failed"
+	default:
+		return "unknown"
+	}
+}
+
+const (
+	JobExecutionStatusUnknown JobExecutionStatus = iota
+	JobExecutionStatusRunning
+	JobExecutionStatusSuccess
+	JobExecutionStatusFailed
+)
@@ -0,0 +1,29 @@
+package domain
+
+import (
+	"strconv"
+	"time"
+)
+
+const (
+	OutboxBizArticle = "article"
+
+	OutboxTypeArticlePublished = "published"
+	OutboxTypeArticleWithdrawn = "withdrawn"
+)
+
+// OutboxEvent 和业务数据一起写入的事件，等待投递到消息队列
+type OutboxEvent struct {
+	Id    int64
+	Biz   string
+	BizId int64
+	Type  string
+	Uid   int64
+	// 事件发生的时间
+	Ctime time.Time
+}
+
+// IdempotencyKey 同一个事件重复投递的时候 key 不变，消费者据此去重
+func (e OutboxEvent) IdempotencyKey() string {
+	return "outbox_" + strconv.FormatInt(e.Id, 10)
+}
@@ -0,0 +1,29 @@
+package domain
+
+import "time"
+
+// SearchArticle 搜索结果里面的文章。
+// Title 和 Abstract 是高亮之后的 HTML 片段
+type SearchArticle struct {
+	Id       int64
+	AuthorId int64
+	Title    string
+	Abstract string
+	Utime    time.Time
+}
+
+type SearchUser struct {
+	Id       int64
+	Nickname string
+	AboutMe  string
+}
+
+type ArticleSearchResult struct {
+	Total    int
+	Articles []SearchArticle
+}
+
+type UserSearchResult struct {
+	Total int
+	Users []SearchUser
+}
@@ -16,3 +16,23 @@ const (
 	// ArticleInvalidInput 文章模块的统一的错误码
 	ArticleInvalidInput = 402001
 )
+
+// 短信验证码相关
+const (
+	// SMSPhoneBlocked 手机号码在黑名单号段里面
+	SMSPhoneBlocked = 403001
+	// SMSHumanCheckFailed 人机校验没有通过
+	SMSHumanCheckFailed = 403002
+	// SMSPhoneTooMany 同一个手机号码一天发送太多
+	SMSPhoneTooMany = 403003
+	// SMSIPTooMany 同一个 IP 一小时发送太多
+	SMSIPTooMany = 403004
+	// SMSDeviceTooMany 同一个设备一天发送太多
+	SMSDeviceTooMany = 403005
+	// SMSAPIInvalidInput 内部短信接口的参数不对
+	SMSAPIInvalidInput = 403006
+	// SMSAPITemplateNotAllowed 调用方不能使用这个模板
+	SMSAPITemplateNotAllowed = 403007
+	// SMSAPIQuotaExceeded 调用方的配额用完了
+	SMSAPIQuotaExceeded = 403008
+)
@@ -0,0 +1,153 @@
+package article
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/IBM/sarama"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/wsqigo/basic-go/webook/pkg/logger"
+	"sync/atomic"
+)
+
+var ErrProducerBufferFull = errors.New("发送中的消息太多，丢弃消息")
+
+// DeliveryCallback 消息发送有结果之后回调，err 为 nil 就是发送成功
+type DeliveryCallback func(msg *sarama.ProducerMessage, err error)
+
+// SaramaAsyncProducer 异步发送，不会阻塞业务
+// 发送中的消息占用的内存有上限，超过上限之后阅读事件直接丢弃，
+// 发表和撤回事件数量少又比较重要，不受这个限制
+type SaramaAsyncProducer struct {
+	producer sarama.AsyncProducer
+	l        logger.LoggerV1
+	// 发送中（还没有结果）的消息体最多占用多少字节
+	maxBytes int64
+	bytes    atomic.Int64
+	callback DeliveryCallback
+	counter  *prometheus.CounterVec
+}
+
+// NewSaramaAsyncProducer producer 要打开 Producer.Return.Successes
+// callback 可以为 nil，producer 关闭之后回调也就结束了
+func NewSaramaAsyncProducer(producer sarama.AsyncProducer, maxBytes int64,
+	callback DeliveryCallback, l logger.LoggerV1) Producer {
+	p := &SaramaAsyncProducer{
+		producer: producer,
+		l:        l,
+		maxBytes: maxBytes,
+		callback: callback,
+		counter:  newProduceCounter(),
+	}
+	go p.handleSuccesses()
+	go p.handleErrors()
+	return p
+}
+
+func newProduceCounter() *prometheus.CounterVec {
+	vector := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "geekbang_daming",
+		Subsystem: "webook",
+		Name:      "kafka_async_produce",
+		Help:      "异步发送消息的结果，result 是 success, error 或者 dropped",
+	}, []string{"topic", "result"})
+	err := prometheus.Register(vector)
+	if are, ok := err.(prometheus.AlreadyRegisteredError); ok {
+		return are.ExistingCollector.(*prometheus.CounterVec)
+	}
+	return vector
+}
+
+func (s *SaramaAsyncProducer) ProduceReadEvent(evt ReadEvent) error {
+	val, err := json.Marshal(evt)
+	if err != nil {
+		return err
+	}
+	return s.send(&sarama.ProducerMessage{
+		Topic: TopicReadEvent,
+		Value: sarama.StringEncoder(val),
+	}, true)
+}
+
+func (s *SaramaAsyncProducer) ProduceBatchReadEvent(evt BatchReadEvent) error {
+	msgs, err := batchReadMessages(evt)
+	if err != nil {
+		return err
+	}
+	for _, msg := range msgs {
+		err = s.send(msg, true)
+		if err != nil {
+			// 后面的也放不下了
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *SaramaAsyncProducer) ProducePublishEvent(evt PublishEvent) error {
+	val, err := json.Marshal(evt)
+	if err != nil {
+		return err
+	}
+	return s.send(&sarama.ProducerMessage{
+		Topic: TopicPublishEvent,
+		Value: sarama.StringEncoder(val),
+	}, false)
+}
+
+func (s *SaramaAsyncProducer) ProduceWithdrawEvent(evt WithdrawEvent) error {
+	val, err := json.Marshal(evt)
+	if err != nil {
+		return err
+	}
+	return s.send(&sarama.ProducerMessage{
+		Topic: TopicWithdrawEvent,
+		Value: sarama.StringEncoder(val),
+	}, false)
+}
+
+// send droppable 为 true 的时候，超过内存上限或者 sarama 的缓冲满了就直接丢弃
+func (s *SaramaAsyncProducer) send(msg *sarama.ProducerMessage, droppable bool) error {
+	size := int64(msg.Value.Length())
+	if !droppable {
+		s.bytes.Add(size)
+		s.producer.Input() <- msg
+		return nil
+	}
+	if s.bytes.Add(size) > s.maxBytes {
+		s.bytes.Add(-size)
+		s.counter.WithLabelValues(msg.Topic, "dropped").Inc()
+		return ErrProducerBufferFull
+	}
+	select {
+	case s.producer.Input() <- msg:
+		return nil
+	default:
+		s.bytes.Add(-size)
+		s.counter.WithLabelValues(msg.Topic, "dropped").Inc()
+		return ErrProducerBufferFull
+	}
+}
+
+func (s *SaramaAsyncProducer) handleSuccesses() {
+	for msg := range s.producer.Successes() {
+		s.bytes.Add(-int64(msg.Value.Length()))
+		s.counter.WithLabelValues(msg.Topic, "success").Inc()
+		if s.callback != nil {
+			s.callback(msg, nil)
+		}
+	}
+}
+
+func (s *SaramaAsyncProducer) handleErrors() {
+	for perr := range s.producer.Errors() {
+		msg := perr.Msg
+		s.bytes.Add(-int64(msg.Value.Length()))
+		s.counter.WithLabelValues(msg.Topic, "error").Inc()
+		s.l.Error("异步发送消息失败",
+			logger.String("topic", msg.Topic),
+			logger.Error(perr.Err))
+		if s.callback != nil {
+			s.callback(msg, perr.Err)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package article
+
+import (
+	"github.com/IBM/sarama"
+	"github.com/IBM/sarama/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/wsqigo/basic-go/webook/pkg/logger"
+	"sync"
+	"testing"
+)
+
+func TestSaramaAsyncProducer_ProduceReadEvent(t *testing.T) {
+	cfg := sarama.NewConfig()
+	cfg.Producer.Return.Successes = true
+	mp := mocks.NewAsyncProducer(t, cfg)
+	mp.ExpectInputAndSucceed()
+	mp.ExpectInputAndFail(sarama.ErrOutOfBrokers)
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	var lock sync.Mutex
+	var results []error
+	// 一条 ReadEvent 大概 17 个字节，只放得下两条
+	p := NewSaramaAsyncProducer(mp, 40, func(msg *sarama.ProducerMessage, err error) {
+		lock.Lock()
+		results = append(results, err)
+		lock.Unlock()
+		wg.Done()
+	}, logger.NewNopLogger())
+
+	err := p.ProduceBatchReadEvent(BatchReadEvent{
+		Aids: []int64{1, 2, 3},
+		Uids: []int64{11, 12, 13},
+	})
+	// 第三条超过了内存上限
+	assert.Equal(t, ErrProducerBufferFull, err)
+	wg.Wait()
+	assert.ElementsMatch(t, []error{nil, sarama.ErrOutOfBrokers}, results)
+
+	// 前面的都有结果了，内存释放掉，又可以发送了
+	assert.Equal(t, int64(0), p.(*SaramaAsyncProducer).bytes.Load())
+	assert.NoError(t, mp.Close())
+}
+
+func TestBatchReadMessages(t *testing.T) {
+	_, err := batchReadMessages(BatchReadEvent{Aids: []int64{1}, Uids: []int64{}})
+	assert.Error(t, err)
+	msgs, err := batchReadMessages(BatchReadEvent{Aids: []int64{1, 2}, Uids: []int64{11, 12}})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(msgs))
+	val, _ := msgs[1].Value.Encode()
+	assert.Equal(t, `{"Aid":2,"Uid":12}`, string(val))
+}
@@ -13,27 +13,43 @@ type InteractiveReadEventConsumer struct {
 	repo   repository.InteractiveRepository
 	client sarama.Client
 	l      logger.LoggerV1
+	cg     *saramax.ConsumerGroup
+	// 处理失败的消息转发到重试 topic 或者死信 topic
+	failure *saramax.FailureHandler
+	// 批量消费的参数，用来调整吞吐量
+	batchCfg saramax.BatchConfig
+	// rebalance 之后会重复消费，阅读数不能重复计算
+	dedup saramax.Deduper
 }
 
 func NewInteractiveReadEventConsumer(repo repository.InteractiveRepository,
-	client sarama.Client, l logger.LoggerV1) *InteractiveReadEventConsumer {
-	return &InteractiveReadEventConsumer{repo: repo, client: client, l: l}
+	client sarama.Client, failure *saramax.FailureHandler,
+	batchCfg saramax.BatchConfig, dedup saramax.Deduper,
+	l logger.LoggerV1) *InteractiveReadEventConsumer {
+	return &InteractiveReadEventConsumer{repo: repo, client: client,
+		failure: failure, batchCfg: batchCfg, dedup: dedup, l: l}
 }
 
 func (i *InteractiveReadEventConsumer) Start() error {
-	cg, err := sarama.NewConsumerGroupFromClient("interactive", i.client)
+	const group = "interactive"
+	cg, err := saramax.StartConsumerGroup(i.client, group,
+		[]string{TopicReadEvent, saramax.RetryTopic(TopicReadEvent, group)},
+		saramax.NewBatchHandler[ReadEvent](i.l,
+			saramax.DedupBatch(i.dedup, time.Second, i.BatchConsume)).
+			WithRetry(consumerRetry(i.failure.ForGroup(group))).
+			WithBatchConfig(i.batchCfg), i.l)
 	if err != nil {
 		return err
 	}
-	go func() {
-		er := cg.Consume(context.Background(),
-			[]string{TopicReadEvent},
-			saramax.NewBatchHandler[ReadEvent](i.l, i.BatchConsume))
-		if er != nil {
-			i.l.Error("退出消费", logger.Error(er))
-		}
-	}()
-	return err
+	i.cg = cg
+	return nil
+}
+
+func (i *InteractiveReadEventConsumer) Close() error {
+	if i.cg == nil {
+		return nil
+	}
+	return i.cg.Close()
 }
 
 func (i *InteractiveReadEventConsumer) StartV1() error {
@@ -70,3 +86,12 @@ func (i *InteractiveReadEventConsumer) Consume(msg *sarama.ConsumerMessage, even
 	defer cancel()
 	return i.repo.IncrReadCnt(ctx, "article", event.Aid)
 }
+
+// consumerRetry 先在本地快速重试几次，还是失败就交给 failure 转发
+func consumerRetry(failure *saramax.FailureHandler) saramax.RetryConfig {
+	return saramax.RetryConfig{
+		MaxRetries: 3,
+		Interval:   100 * time.Millisecond,
+		Failure:    failure,
+	}
+}
@@ -14,31 +14,44 @@ type HistoryRecordConsumer struct {
 	repo   repository.HistoryRecordRepository
 	client sarama.Client
 	l      logger.LoggerV1
+	cg     *saramax.ConsumerGroup
+	// 处理失败的消息转发到重试 topic 或者死信 topic
+	failure *saramax.FailureHandler
+}
+
+func NewHistoryRecordConsumer(repo repository.HistoryRecordRepository,
+	client sarama.Client, failure *saramax.FailureHandler, l logger.LoggerV1) *HistoryRecordConsumer {
+	return &HistoryRecordConsumer{repo: repo, client: client, failure: failure, l: l}
 }
 
 func (i *HistoryRecordConsumer) Start() error {
-	cg, err := sarama.NewConsumerGroupFromClient("interactive", i.client)
+	// 不能和阅读计数共用 group，不然两边各自只能拿到一部分消息
+	const group = "history"
+	cg, err := saramax.StartConsumerGroup(i.client, group,
+		[]string{TopicReadEvent, saramax.RetryTopic(TopicReadEvent, group)},
+		saramax.NewHandler[ReadEvent](i.l, i.Consume).
+			WithRetry(consumerRetry(i.failure.ForGroup(group))), i.l)
 	if err != nil {
 		return err
 	}
+	i.cg = cg
+	return nil
+}
 
-	go func() {
-		er := cg.Consume(context.Background(),
-			[]string{TopicReadEvent},
-			saramax.NewHandler[ReadEvent](i.l, i.Consume))
-		if er != nil {
-			i.l.Error("退出消费", logger.Error(er))
-		}
-	}()
-	return err
+func (i *HistoryRecordConsumer) Close() error {
+	if i.cg == nil {
+		return nil
+	}
+	return i.cg.Close()
 }
 
 func (i *HistoryRecordConsumer) Consume(msg *sarama.ConsumerMessage, event ReadEvent) error {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
 	defer cancel()
 	return i.repo.AddRecord(ctx, domain.HistoryRecord{
-		BizId: event.Aid,
-		Biz:   "article",
-		Uid:   event.Uid,
+		BizId:    event.Aid,
+		Biz:      "article",
+		Uid:      event.Uid,
+		ReadTime: time.Now(),
 	})
 }
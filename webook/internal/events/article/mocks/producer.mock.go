@@ -0,0 +1,96 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./producer.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./producer.go -package=evtmocks -destination=./mocks/producer.mock.go Producer
+//
+
+// Package evtmocks is a generated GoMock package.
+package evtmocks
+
+import (
+	reflect "reflect"
+
+	article "github.com/wsqigo/basic-go/webook/internal/events/article"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockProducer is a mock of Producer interface.
+type MockProducer struct {
+	ctrl     *gomock.Controller
+	recorder *MockProducerMockRecorder
+}
+
+// MockProducerMockRecorder is the mock recorder for MockProducer.
+type MockProducerMockRecorder struct {
+	mock *MockProducer
+}
+
+// NewMockProducer creates a new mock instance.
+func NewMockProducer(ctrl *gomock.Controller) *MockProducer {
+	mock := &MockProducer{ctrl: ctrl}
+	mock.recorder = &MockProducerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProducer) EXPECT() *MockProducerMockRecorder {
+	return m.recorder
+}
+
+// ProduceBatchReadEvent mocks base method.
+func (m *MockProducer) ProduceBatchReadEvent(evt article.BatchReadEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProduceBatchReadEvent", evt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ProduceBatchReadEvent indicates an expected call of ProduceBatchReadEvent.
+func (mr *MockProducerMockRecorder) ProduceBatchReadEvent(evt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProduceBatchReadEvent", reflect.TypeOf((*MockProducer)(nil).ProduceBatchReadEvent), evt)
+}
+
+// ProducePublishEvent mocks base method.
+func (m *MockProducer) ProducePublishEvent(evt article.PublishEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProducePublishEvent", evt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ProducePublishEvent indicates an expected call of ProducePublishEvent.
+func (mr *MockProducerMockRecorder) ProducePublishEvent(evt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProducePublishEvent", reflect.TypeOf((*MockProducer)(nil).ProducePublishEvent), evt)
+}
+
+// ProduceReadEvent mocks base method.
+func (m *MockProducer) ProduceReadEvent(evt article.ReadEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProduceReadEvent", evt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ProduceReadEvent indicates an expected call of ProduceReadEvent.
+func (mr *MockProducerMockRecorder) ProduceReadEvent(evt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProduceReadEvent", reflect.TypeOf((*MockProducer)(nil).ProduceReadEvent), evt)
+}
+
+// ProduceWithdrawEvent mocks base method.
+func (m *MockProducer) ProduceWithdrawEvent(evt article.WithdrawEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProduceWithdrawEvent", evt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ProduceWithdrawEvent indicates an expected call of ProduceWithdrawEvent.
+func (mr *MockProducerMockRecorder) ProduceWithdrawEvent(evt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProduceWithdrawEvent", reflect.TypeOf((*MockProducer)(nil).ProduceWithdrawEvent), evt)
+}
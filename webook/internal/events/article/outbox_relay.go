@@ -0,0 +1,109 @@
+package article
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/IBM/sarama"
+	"github.com/wsqigo/basic-go/webook/internal/domain"
+	"github.com/wsqigo/basic-go/webook/internal/repository"
+	"github.com/wsqigo/basic-go/webook/pkg/logger"
+	"github.com/wsqigo/basic-go/webook/pkg/saramax"
+	"strconv"
+	"time"
+)
+
+// OutboxRelay 把 outbox 里面的事件投递到 Kafka
+// 先发送再标记，标记失败的话下一轮会重复发送，所以是至少一次，消费者要根据幂等键去重
+type OutboxRelay struct {
+	repo     repository.OutboxRepository
+	producer sarama.SyncProducer
+	l        logger.LoggerV1
+}
+
+func NewOutboxRelay(repo repository.OutboxRepository,
+	producer sarama.SyncProducer, l logger.LoggerV1) *OutboxRelay {
+	return &OutboxRelay{repo: repo, producer: producer, l: l}
+}
+
+// Relay 投递一批，返回处理了多少条
+func (r *OutboxRelay) Relay(ctx context.Context, limit int) (int, error) {
+	evts, err := r.repo.FindPending(ctx, limit)
+	if err != nil || len(evts) == 0 {
+		return 0, err
+	}
+	msgs := make([]*sarama.ProducerMessage, 0, len(evts))
+	ids := make([]int64, 0, len(evts))
+	for _, evt := range evts {
+		ids = append(ids, evt.Id)
+		msg, er := r.toMessage(evt)
+		if er != nil {
+			// 不认识的事件，重试也没用，标记掉避免卡住后面的事件
+			r.l.Error("无法投递的 outbox 事件",
+				logger.Int64("id", evt.Id),
+				logger.String("biz", evt.Biz),
+				logger.String("type", evt.Type),
+				logger.Error(er))
+			continue
+		}
+		msgs = append(msgs, msg)
+	}
+	if len(msgs) > 0 {
+		// 部分失败也整批重发，反正消费者要去重
+		err = r.producer.SendMessages(msgs)
+		if err != nil {
+			return 0, err
+		}
+	}
+	err = r.repo.MarkSent(ctx, ids)
+	if err != nil {
+		return 0, err
+	}
+	return len(ids), nil
+}
+
+// Clean 删除 before 之前就已经投递的事件，返回删除了多少条
+func (r *OutboxRelay) Clean(ctx context.Context, before time.Time) (int64, error) {
+	const batchSize = 1000
+	var total int64
+	for {
+		cnt, err := r.repo.DeleteSentBefore(ctx, before, batchSize)
+		total += cnt
+		if err != nil || cnt < batchSize {
+			return total, err
+		}
+	}
+}
+
+func (r *OutboxRelay) toMessage(evt domain.OutboxEvent) (*sarama.ProducerMessage, error) {
+	if evt.Biz != domain.OutboxBizArticle {
+		return nil, fmt.Errorf("未知的业务 %s", evt.Biz)
+	}
+	var (
+		topic string
+		val   any
+	)
+	switch evt.Type {
+	case domain.OutboxTypeArticlePublished:
+		topic = TopicPublishEvent
+		val = PublishEvent{Aid: evt.BizId, Uid: evt.Uid, Ctime: evt.Ctime.UnixMilli()}
+	case domain.OutboxTypeArticleWithdrawn:
+		topic = TopicWithdrawEvent
+		val = WithdrawEvent{Aid: evt.BizId, Uid: evt.Uid}
+	default:
+		return nil, fmt.Errorf("未知的事件类型 %s", evt.Type)
+	}
+	data, err := json.Marshal(val)
+	if err != nil {
+		return nil, err
+	}
+	return &sarama.ProducerMessage{
+		Topic: topic,
+		// 同一篇文章的事件进同一个分区，保证顺序
+		Key:   sarama.StringEncoder(strconv.FormatInt(evt.BizId, 10)),
+		Value: sarama.ByteEncoder(data),
+		Headers: []sarama.RecordHeader{
+			{Key: []byte(saramax.HeaderIdempotencyKey), Value: []byte(evt.IdempotencyKey())},
+		},
+	}, nil
+}
@@ -0,0 +1,138 @@
+package article
+
+import (
+	"context"
+	"errors"
+	"github.com/IBM/sarama"
+	"github.com/IBM/sarama/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wsqigo/basic-go/webook/internal/domain"
+	"github.com/wsqigo/basic-go/webook/internal/repository"
+	repomocks "github.com/wsqigo/basic-go/webook/internal/repository/mocks"
+	"github.com/wsqigo/basic-go/webook/pkg/logger"
+	"github.com/wsqigo/basic-go/webook/pkg/saramax"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+)
+
+func TestOutboxRelay_Relay(t *testing.T) {
+	ctime := time.UnixMilli(1000)
+	evts := []domain.OutboxEvent{
+		{Id: 1, Biz: domain.OutboxBizArticle, BizId: 11,
+			Type: domain.OutboxTypeArticlePublished, Uid: 21, Ctime: ctime},
+		{Id: 2, Biz: domain.OutboxBizArticle, BizId: 12,
+			Type: domain.OutboxTypeArticleWithdrawn, Uid: 22, Ctime: ctime},
+	}
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller, p *mocks.SyncProducer) repository.OutboxRepository
+
+		wantCnt int
+		wantErr error
+	}{
+		{
+			name: "投递成功",
+			mock: func(ctrl *gomock.Controller, p *mocks.SyncProducer) repository.OutboxRepository {
+				repo := repomocks.NewMockOutboxRepository(ctrl)
+				repo.EXPECT().FindPending(gomock.Any(), 10).Return(evts, nil)
+				p.ExpectSendMessageWithMessageCheckerFunctionAndSucceed(checkMessage(t,
+					TopicPublishEvent, "11", `{"Aid":11,"Uid":21,"Ctime":1000}`, "outbox_1"))
+				p.ExpectSendMessageWithMessageCheckerFunctionAndSucceed(checkMessage(t,
+					TopicWithdrawEvent, "12", `{"Aid":12,"Uid":22}`, "outbox_2"))
+				repo.EXPECT().MarkSent(gomock.Any(), []int64{1, 2}).Return(nil)
+				return repo
+			},
+			wantCnt: 2,
+		},
+		{
+			name: "没有待投递的事件",
+			mock: func(ctrl *gomock.Controller, p *mocks.SyncProducer) repository.OutboxRepository {
+				repo := repomocks.NewMockOutboxRepository(ctrl)
+				repo.EXPECT().FindPending(gomock.Any(), 10).Return(nil, nil)
+				return repo
+			},
+		},
+		{
+			name: "未知的事件直接标记",
+			mock: func(ctrl *gomock.Controller, p *mocks.SyncProducer) repository.OutboxRepository {
+				repo := repomocks.NewMockOutboxRepository(ctrl)
+				repo.EXPECT().FindPending(gomock.Any(), 10).Return([]domain.OutboxEvent{
+					{Id: 3, Biz: "unknown", BizId: 13},
+				}, nil)
+				repo.EXPECT().MarkSent(gomock.Any(), []int64{3}).Return(nil)
+				return repo
+			},
+			wantCnt: 1,
+		},
+		{
+			name: "发送失败不标记",
+			mock: func(ctrl *gomock.Controller, p *mocks.SyncProducer) repository.OutboxRepository {
+				repo := repomocks.NewMockOutboxRepository(ctrl)
+				repo.EXPECT().FindPending(gomock.Any(), 10).Return(evts, nil)
+				p.ExpectSendMessageAndFail(sarama.ErrOutOfBrokers)
+				p.ExpectSendMessageAndFail(sarama.ErrOutOfBrokers)
+				return repo
+			},
+			wantErr: sarama.ErrOutOfBrokers,
+		},
+		{
+			name: "标记失败",
+			mock: func(ctrl *gomock.Controller, p *mocks.SyncProducer) repository.OutboxRepository {
+				repo := repomocks.NewMockOutboxRepository(ctrl)
+				repo.EXPECT().FindPending(gomock.Any(), 10).Return(evts[:1], nil)
+				p.ExpectSendMessageAndSucceed()
+				repo.EXPECT().MarkSent(gomock.Any(), []int64{1}).Return(errors.New("mock db error"))
+				return repo
+			},
+			wantErr: errors.New("mock db error"),
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			p := mocks.NewSyncProducer(t, nil)
+			relay := NewOutboxRelay(tc.mock(ctrl, p), p, logger.NewNopLogger())
+			cnt, err := relay.Relay(context.Background(), 10)
+			if tc.wantErr != nil {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.wantErr.Error())
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tc.wantCnt, cnt)
+			assert.NoError(t, p.Close())
+		})
+	}
+}
+
+func TestOutboxRelay_Clean(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	before := time.Now()
+	repo := repomocks.NewMockOutboxRepository(ctrl)
+	// 一批删满了就继续删
+	repo.EXPECT().DeleteSentBefore(gomock.Any(), before, 1000).Return(int64(1000), nil)
+	repo.EXPECT().DeleteSentBefore(gomock.Any(), before, 1000).Return(int64(10), nil)
+	relay := NewOutboxRelay(repo, nil, logger.NewNopLogger())
+	cnt, err := relay.Clean(context.Background(), before)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1010), cnt)
+}
+
+func checkMessage(t *testing.T, topic, key, val, idempotencyKey string) mocks.MessageChecker {
+	return func(msg *sarama.ProducerMessage) error {
+		assert.Equal(t, topic, msg.Topic)
+		k, _ := msg.Key.Encode()
+		assert.Equal(t, key, string(k))
+		v, _ := msg.Value.Encode()
+		assert.JSONEq(t, val, string(v))
+		require.Len(t, msg.Headers, 1)
+		assert.Equal(t, saramax.HeaderIdempotencyKey, string(msg.Headers[0].Key))
+		assert.Equal(t, idempotencyKey, string(msg.Headers[0].Value))
+		return nil
+	}
+}
@@ -2,13 +2,23 @@ package article
 
 import (
 	"encoding/json"
+	"errors"
 	"github.com/IBM/sarama"
 )
 
-const TopicReadEvent = "article_read"
+const (
+	TopicReadEvent     = "article_read"
+	TopicPublishEvent  = "article_published"
+	TopicWithdrawEvent = "article_withdrawn"
+)
 
+//go:generate mockgen -source=./producer.go -package=evtmocks -destination=./mocks/producer.mock.go Producer
 type Producer interface {
 	ProduceReadEvent(evt ReadEvent) error
+	// ProduceBatchReadEvent 拆成一个个 ReadEvent 发送，消费者不需要改动
+	ProduceBatchReadEvent(evt BatchReadEvent) error
+	ProducePublishEvent(evt PublishEvent) error
+	ProduceWithdrawEvent(evt WithdrawEvent) error
 }
 
 type ReadEvent struct {
@@ -16,11 +26,45 @@ type ReadEvent struct {
 	Uid int64
 }
 
+// PublishEvent 文章发表了（包括修改之后重新发表）
+type PublishEvent struct {
+	Aid int64
+	// 作者
+	Uid int64
+	// 发表时间，毫秒数
+	Ctime int64
+}
+
+// WithdrawEvent 文章被作者撤回，变成仅自己可见
+type WithdrawEvent struct {
+	Aid int64
+	Uid int64
+}
+
+// BatchReadEvent Aids 和 Uids 一一对应
 type BatchReadEvent struct {
 	Aids []int64
 	Uids []int64
 }
 
+func batchReadMessages(evt BatchReadEvent) ([]*sarama.ProducerMessage, error) {
+	if len(evt.Aids) != len(evt.Uids) {
+		return nil, errors.New("Aids 和 Uids 的长度不一致")
+	}
+	msgs := make([]*sarama.ProducerMessage, 0, len(evt.Aids))
+	for i := range evt.Aids {
+		val, err := json.Marshal(ReadEvent{Aid: evt.Aids[i], Uid: evt.Uids[i]})
+		if err != nil {
+			return nil, err
+		}
+		msgs = append(msgs, &sarama.ProducerMessage{
+			Topic: TopicReadEvent,
+			Value: sarama.StringEncoder(val),
+		})
+	}
+	return msgs, nil
+}
+
 type SaramaSyncProducer struct {
 	producer sarama.SyncProducer
 }
@@ -40,3 +84,35 @@ func (s *SaramaSyncProducer) ProduceReadEvent(evt ReadEvent) error {
 	})
 	return err
 }
+
+func (s *SaramaSyncProducer) ProduceBatchReadEvent(evt BatchReadEvent) error {
+	msgs, err := batchReadMessages(evt)
+	if err != nil {
+		return err
+	}
+	return s.producer.SendMessages(msgs)
+}
+
+func (s *SaramaSyncProducer) ProducePublishEvent(evt PublishEvent) error {
+	val, err := json.Marshal(evt)
+	if err != nil {
+		return err
+	}
+	_, _, err = s.producer.SendMessage(&sarama.ProducerMessage{
+		Topic: TopicPublishEvent,
+		Value: sarama.StringEncoder(val),
+	})
+	return err
+}
+
+func (s *SaramaSyncProducer) ProduceWithdrawEvent(evt WithdrawEvent) error {
+	val, err := json.Marshal(evt)
+	if err != nil {
+		return err
+	}
+	_, _, err = s.producer.SendMessage(&sarama.ProducerMessage{
+		Topic: TopicWithdrawEvent,
+		Value: sarama.StringEncoder(val),
+	})
+	return err
+}
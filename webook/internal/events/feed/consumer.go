@@ -0,0 +1,62 @@
+package feed
+
+import (
+	"context"
+	"github.com/IBM/sarama"
+	"github.com/wsqigo/basic-go/webook/internal/events/article"
+	"github.com/wsqigo/basic-go/webook/internal/service"
+	"github.com/wsqigo/basic-go/webook/pkg/logger"
+	"github.com/wsqigo/basic-go/webook/pkg/saramax"
+	"time"
+)
+
+// ArticlePublishEventConsumer 文章发表之后推送 feed
+type ArticlePublishEventConsumer struct {
+	svc    service.FeedService
+	client sarama.Client
+	l      logger.LoggerV1
+	cg     *saramax.ConsumerGroup
+	// 处理失败的消息转发到重试 topic 或者死信 topic
+	failure *saramax.FailureHandler
+	// outbox 是至少一次投递，同一个事件可能收到好几次，不去重的话粉丝会收到重复的 feed
+	dedup saramax.Deduper
+}
+
+func NewArticlePublishEventConsumer(svc service.FeedService,
+	client sarama.Client, failure *saramax.FailureHandler,
+	dedup saramax.Deduper, l logger.LoggerV1) *ArticlePublishEventConsumer {
+	return &ArticlePublishEventConsumer{svc: svc, client: client, failure: failure, dedup: dedup, l: l}
+}
+
+func (a *ArticlePublishEventConsumer) Start() error {
+	const group = "feed"
+	cg, err := saramax.StartConsumerGroup(a.client, group,
+		[]string{article.TopicPublishEvent, saramax.RetryTopic(article.TopicPublishEvent, group)},
+		saramax.NewHandler[article.PublishEvent](a.l,
+			saramax.Dedup(a.dedup, time.Second, a.Consume)).
+			WithRetry(saramax.RetryConfig{
+				MaxRetries: 3,
+				Interval:   100 * time.Millisecond,
+				Failure:    a.failure.ForGroup(group),
+			}), a.l)
+	if err != nil {
+		return err
+	}
+	a.cg = cg
+	return nil
+}
+
+func (a *ArticlePublishEventConsumer) Close() error {
+	if a.cg == nil {
+		return nil
+	}
+	return a.cg.Close()
+}
+
+func (a *ArticlePublishEventConsumer) Consume(msg *sarama.ConsumerMessage,
+	evt article.PublishEvent) error {
+	// 粉丝多的时候要写很多个收件箱，所以超时时间长一点
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	return a.svc.PushFeed(ctx, evt.Aid, evt.Uid, time.UnixMilli(evt.Ctime))
+}
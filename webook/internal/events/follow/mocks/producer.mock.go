@@ -0,0 +1,54 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./producer.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./producer.go -package=evtmocks -destination=./mocks/producer.mock.go Producer
+//
+
+// Package evtmocks is a generated GoMock package.
+package evtmocks
+
+import (
+	reflect "reflect"
+
+	follow "github.com/wsqigo/basic-go/webook/internal/events/follow"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockProducer is a mock of Producer interface.
+type MockProducer struct {
+	ctrl     *gomock.Controller
+	recorder *MockProducerMockRecorder
+}
+
+// MockProducerMockRecorder is the mock recorder for MockProducer.
+type MockProducerMockRecorder struct {
+	mock *MockProducer
+}
+
+// NewMockProducer creates a new mock instance.
+func NewMockProducer(ctrl *gomock.Controller) *MockProducer {
+	mock := &MockProducer{ctrl: ctrl}
+	mock.recorder = &MockProducerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProducer) EXPECT() *MockProducerMockRecorder {
+	return m.recorder
+}
+
+// ProduceFollowEvent mocks base method.
+func (m *MockProducer) ProduceFollowEvent(evt follow.FollowEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProduceFollowEvent", evt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ProduceFollowEvent indicates an expected call of ProduceFollowEvent.
+func (mr *MockProducerMockRecorder) ProduceFollowEvent(evt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProduceFollowEvent", reflect.TypeOf((*MockProducer)(nil).ProduceFollowEvent), evt)
+}
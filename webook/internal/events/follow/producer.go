@@ -0,0 +1,46 @@
+package follow
+
+import (
+	"encoding/json"
+	"github.com/IBM/sarama"
+	"strconv"
+)
+
+const TopicFollowEvent = "follow_event"
+
+//go:generate mockgen -source=./producer.go -package=evtmocks -destination=./mocks/producer.mock.go Producer
+type Producer interface {
+	ProduceFollowEvent(evt FollowEvent) error
+}
+
+// FollowEvent 关注关系发生了变化
+type FollowEvent struct {
+	Follower int64
+	Followee int64
+	// true 是关注，false 是取消关注
+	Followed bool
+	// 毫秒数
+	Ctime int64
+}
+
+type SaramaSyncProducer struct {
+	producer sarama.SyncProducer
+}
+
+func NewSaramaSyncProducer(producer sarama.SyncProducer) Producer {
+	return &SaramaSyncProducer{producer: producer}
+}
+
+func (s *SaramaSyncProducer) ProduceFollowEvent(evt FollowEvent) error {
+	val, err := json.Marshal(evt)
+	if err != nil {
+		return err
+	}
+	_, _, err = s.producer.SendMessage(&sarama.ProducerMessage{
+		Topic: TopicFollowEvent,
+		// 同一个人的关注事件放在同一个分区，保证顺序
+		Key:   sarama.StringEncoder(strconv.FormatInt(evt.Follower, 10)),
+		Value: sarama.StringEncoder(val),
+	})
+	return err
+}
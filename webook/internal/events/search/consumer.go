@@ -0,0 +1,89 @@
+package search
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/IBM/sarama"
+	"github.com/wsqigo/basic-go/webook/internal/events/article"
+	"github.com/wsqigo/basic-go/webook/internal/events/user"
+	"github.com/wsqigo/basic-go/webook/internal/service"
+	"github.com/wsqigo/basic-go/webook/pkg/logger"
+	"github.com/wsqigo/basic-go/webook/pkg/saramax"
+	"time"
+)
+
+// SyncConsumer 把文章和用户的变更同步到搜索索引
+type SyncConsumer struct {
+	svc    service.SearchService
+	client sarama.Client
+	l      logger.LoggerV1
+	cg     *saramax.ConsumerGroup
+	// 索引在进程内，每一个实例都要收到全部的消息，所以每个实例用自己的消费者组。
+	// 组名要固定下来，重启之后接着之前的位移消费，也不会留下一堆没人用的消费者组
+	groupId string
+}
+
+func NewSyncConsumer(svc service.SearchService,
+	client sarama.Client, groupId string, l logger.LoggerV1) *SyncConsumer {
+	return &SyncConsumer{svc: svc, client: client, groupId: groupId, l: l}
+}
+
+func (s *SyncConsumer) Start() error {
+	cg, err := saramax.StartConsumerGroup(s.client, s.groupId,
+		[]string{article.TopicPublishEvent, article.TopicWithdrawEvent, user.TopicProfileEvent},
+		saramax.NewHandler[json.RawMessage](s.l, s.Consume).
+			// 每个实例一个消费者组，不能共用重试 topic，所以只在本地重试
+			WithRetry(saramax.RetryConfig{
+				MaxRetries: 3,
+				Interval:   100 * time.Millisecond,
+			}), s.l)
+	if err != nil {
+		return err
+	}
+	s.cg = cg
+	// 重启之后索引是空的，先把已有的数据灌进去。
+	// 和增量同步同时进行，索引按照更新时间丢弃旧的数据，不会覆盖掉新的
+	go func() {
+		er := s.svc.Rebuild(context.Background())
+		if er != nil {
+			s.l.Error("重建搜索索引失败", logger.Error(er))
+		}
+	}()
+	return nil
+}
+
+func (s *SyncConsumer) Close() error {
+	if s.cg == nil {
+		return nil
+	}
+	return s.cg.Close()
+}
+
+func (s *SyncConsumer) Consume(msg *sarama.ConsumerMessage, val json.RawMessage) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	switch msg.Topic {
+	case article.TopicPublishEvent:
+		var evt article.PublishEvent
+		if err := json.Unmarshal(val, &evt); err != nil {
+			return err
+		}
+		return s.svc.SyncArticle(ctx, evt.Aid)
+	case article.TopicWithdrawEvent:
+		var evt article.WithdrawEvent
+		if err := json.Unmarshal(val, &evt); err != nil {
+			return err
+		}
+		// 撤回之后可能又重新发表了，按照数据库里面的最新状态来
+		return s.svc.SyncArticle(ctx, evt.Aid)
+	case user.TopicProfileEvent:
+		var evt user.ProfileEvent
+		if err := json.Unmarshal(val, &evt); err != nil {
+			return err
+		}
+		return s.svc.SyncUser(ctx, evt.Uid)
+	default:
+		return fmt.Errorf("未知的 topic %s", msg.Topic)
+	}
+}
@@ -2,4 +2,6 @@ package events
 
 type Consumer interface {
 	Start() error
+	// Close 停止消费，并且提交已经处理的消息的偏移量
+	Close() error
 }
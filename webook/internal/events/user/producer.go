@@ -0,0 +1,38 @@
+package user
+
+import (
+	"encoding/json"
+	"github.com/IBM/sarama"
+)
+
+const TopicProfileEvent = "user_profile_updated"
+
+//go:generate mockgen -source=./producer.go -package=evtmocks -destination=./mocks/producer.mock.go Producer
+type Producer interface {
+	ProduceProfileEvent(evt ProfileEvent) error
+}
+
+// ProfileEvent 用户修改了个人资料，只带 ID，需要的话自己去查最新的数据
+type ProfileEvent struct {
+	Uid int64
+}
+
+type SaramaSyncProducer struct {
+	producer sarama.SyncProducer
+}
+
+func NewSaramaSyncProducer(producer sarama.SyncProducer) Producer {
+	return &SaramaSyncProducer{producer: producer}
+}
+
+func (s *SaramaSyncProducer) ProduceProfileEvent(evt ProfileEvent) error {
+	val, err := json.Marshal(evt)
+	if err != nil {
+		return err
+	}
+	_, _, err = s.producer.SendMessage(&sarama.ProducerMessage{
+		Topic: TopicProfileEvent,
+		Value: sarama.StringEncoder(val),
+	})
+	return err
+}
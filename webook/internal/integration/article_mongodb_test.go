@@ -7,6 +7,7 @@ import (
 	"github.com/bwmarrin/snowflake"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 	"github.com/wsqigo/basic-go/webook/internal/integration/startup"
 	"github.com/wsqigo/basic-go/webook/internal/repository/dao"
@@ -33,7 +34,7 @@ func (s *ArticleMongoDBHandlerSuite) SetupSuite() {
 	s.liveCol = s.mdb.Collection("published_articles")
 	node, err := snowflake.NewNode(1)
 	assert.NoError(s.T(), err)
-	hdl := startup.InitArticleHandler(dao.NewArticleMongoDBDAO(s.mdb, node))
+	hdl := startup.InitArticleHandler(dao.NewArticleMongoDBDAO(s.mdb, node, dao.NewGORMOutboxDAO(startup.InitDB())))
 	server := gin.Default()
 	server.Use(func(ctx *gin.Context) {
 		ctx.Set("user", jwt2.UserClaims{
@@ -531,6 +532,58 @@ func (s *ArticleMongoDBHandlerSuite) TestPublish() {
 	}
 }
 
+func (s *ArticleMongoDBHandlerSuite) TestReadPaths() {
+	t := s.T()
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	err := dao.InitCollection(s.mdb)
+	require.NoError(t, err)
+	node, err := snowflake.NewNode(1)
+	require.NoError(t, err)
+	d := dao.NewArticleMongoDBDAO(s.mdb, node, dao.NewGORMOutboxDAO(startup.InitDB()))
+
+	// 准备数据，123 有三篇，其中一篇没发表
+	arts := []dao.Article{
+		{Id: 1, Title: "标题1", AuthorId: 123, Status: 2, Ctime: 100, Utime: 100},
+		{Id: 2, Title: "标题2", AuthorId: 123, Status: 2, Ctime: 200, Utime: 200},
+		{Id: 3, Title: "标题3", AuthorId: 123, Status: 1, Ctime: 300, Utime: 300},
+		{Id: 4, Title: "标题4", AuthorId: 234, Status: 2, Ctime: 400, Utime: 400},
+	}
+	for _, art := range arts {
+		_, err = s.col.InsertOne(ctx, art)
+		require.NoError(t, err)
+		_, err = s.liveCol.InsertOne(ctx, dao.PublishedArticle(art))
+		require.NoError(t, err)
+	}
+
+	art, err := d.GetById(ctx, 2)
+	require.NoError(t, err)
+	assert.Equal(t, arts[1], art)
+
+	pubArt, err := d.GetPubById(ctx, 4)
+	require.NoError(t, err)
+	assert.Equal(t, dao.PublishedArticle(arts[3]), pubArt)
+
+	_, err = d.GetPubById(ctx, 100)
+	assert.Equal(t, dao.ErrRecordNotFound, err)
+
+	// 按照更新时间倒序
+	authorArts, err := d.GetByAuthor(ctx, 123, 0, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []dao.Article{arts[2], arts[1]}, authorArts)
+	authorArts, err = d.GetByAuthor(ctx, 123, 2, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []dao.Article{arts[0]}, authorArts)
+
+	// 没发表的不会出现，utime 要小于 start
+	pubArts, err := d.ListPub(ctx, time.UnixMilli(400), 0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []dao.PublishedArticle{
+		dao.PublishedArticle(arts[1]),
+		dao.PublishedArticle(arts[0]),
+	}, pubArts)
+}
+
 func TestArticleMongoDBHandler(t *testing.T) {
 	suite.Run(t, &ArticleMongoDBHandlerSuite{})
 }
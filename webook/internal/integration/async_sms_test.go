@@ -0,0 +1,196 @@
+package integration
+
+import (
+	"context"
+	"errors"
+	"github.com/ecodeclub/ekit/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"github.com/wsqigo/basic-go/webook/internal/integration/startup"
+	"github.com/wsqigo/basic-go/webook/internal/repository"
+	"github.com/wsqigo/basic-go/webook/internal/repository/dao"
+	"github.com/wsqigo/basic-go/webook/internal/service/sms/async"
+	"gorm.io/gorm"
+	"sync"
+	"testing"
+	"time"
+)
+
+// 和 dao 里面的状态保持一致
+const (
+	asyncSmsWaiting = 0
+	asyncSmsFailed  = 1
+	asyncSmsSuccess = 2
+)
+
+type AsyncSmsTestSuite struct {
+	suite.Suite
+	db *gorm.DB
+}
+
+func (s *AsyncSmsTestSuite) SetupSuite() {
+	s.db = startup.InitDB()
+}
+
+func (s *AsyncSmsTestSuite) TearDownTest() {
+	err := s.db.Exec("TRUNCATE TABLE `async_sms`").Error
+	assert.NoError(s.T(), err)
+}
+
+func (s *AsyncSmsTestSuite) newService(provider *fakeSmsProvider) *async.Service {
+	cfg := async.DefaultConfig()
+	// 两次就能判断出来不健康
+	cfg.Health.WindowSize = 2
+	return async.NewService(provider,
+		repository.NewDBAsyncSmsRepository(dao.NewGORMAsyncSmsDAO(s.db)),
+		cfg, startup.InitLogger())
+}
+
+// TestSend 服务商不健康之后，请求存到数据库里面
+func (s *AsyncSmsTestSuite) TestSend() {
+	t := s.T()
+	provider := &fakeSmsProvider{err: errors.New("mock provider error")}
+	svc := s.newService(provider)
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		err := svc.Send(ctx, "tpl", []string{"123456"}, "15212345678")
+		assert.Equal(t, provider.err, err)
+	}
+	// 已经判断为不健康，这一次转异步
+	err := svc.Send(ctx, "tpl", []string{"654321"}, "15212345678")
+	require.NoError(t, err)
+	assert.Equal(t, 2, provider.cnt())
+
+	var as dao.AsyncSms
+	err = s.db.First(&as).Error
+	require.NoError(t, err)
+	assert.Equal(t, dao.SmsConfig{
+		TplId:   "tpl",
+		Args:    []string{"654321"},
+		Numbers: []string{"15212345678"},
+	}, as.Config.Val)
+	assert.Equal(t, 3, as.RetryMax)
+	assert.Equal(t, 0, as.RetryCnt)
+	assert.Equal(t, asyncSmsWaiting, as.Status)
+	assert.True(t, as.Ctime > 0)
+}
+
+func (s *AsyncSmsTestSuite) TestAsyncSend() {
+	testCases := []struct {
+		name     string
+		retryCnt int
+		err      error
+
+		wantStatus   int
+		wantRetryCnt int
+	}{
+		{
+			name:         "发送成功",
+			wantStatus:   asyncSmsSuccess,
+			wantRetryCnt: 1,
+		},
+		{
+			name:         "发送失败，还能重试",
+			err:          errors.New("mock provider error"),
+			wantStatus:   asyncSmsWaiting,
+			wantRetryCnt: 1,
+		},
+		{
+			name:         "发送失败，重试次数用完了",
+			retryCnt:     2,
+			err:          errors.New("mock provider error"),
+			wantStatus:   asyncSmsFailed,
+			wantRetryCnt: 3,
+		},
+	}
+	for _, tc := range testCases {
+		s.T().Run(tc.name, func(t *testing.T) {
+			defer s.TearDownTest()
+			// 一分钟之前的才会被抢占
+			s.insert(t, 1, tc.retryCnt, asyncSmsWaiting, time.Now().Add(-2*time.Minute))
+			provider := &fakeSmsProvider{err: tc.err}
+			svc := s.newService(provider)
+			svc.AsyncSend(context.Background())
+			assert.Equal(t, 1, provider.cnt())
+
+			var as dao.AsyncSms
+			err := s.db.Where("id = ?", 1).First(&as).Error
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantStatus, as.Status)
+			assert.Equal(t, tc.wantRetryCnt, as.RetryCnt)
+
+			// 刚刚抢占过，一分钟之内不会再被抢占
+			svc.AsyncSend(context.Background())
+			assert.Equal(t, 1, provider.cnt())
+		})
+	}
+}
+
+// TestDeleteFinished 只删除已经结束的
+func (s *AsyncSmsTestSuite) TestDeleteFinished() {
+	t := s.T()
+	old := time.Now().Add(-48 * time.Hour)
+	s.insert(t, 1, 1, asyncSmsSuccess, old)
+	s.insert(t, 2, 3, asyncSmsFailed, old)
+	// 等待重试的
+	s.insert(t, 3, 1, asyncSmsWaiting, old)
+	// 重试次数用完了，但是没来得及标记失败的
+	s.insert(t, 4, 3, asyncSmsWaiting, old)
+	// 刚刚结束的
+	s.insert(t, 5, 1, asyncSmsSuccess, time.Now())
+
+	svc := s.newService(&fakeSmsProvider{})
+	cnt, err := svc.DeleteFinished(context.Background(), time.Now().Add(-24*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), cnt)
+
+	var ids []int64
+	err = s.db.Model(&dao.AsyncSms{}).Order("id").Pluck("id", &ids).Error
+	require.NoError(t, err)
+	assert.Equal(t, []int64{3, 5}, ids)
+}
+
+func (s *AsyncSmsTestSuite) insert(t *testing.T, id int64, retryCnt int, status int, utime time.Time) {
+	err := s.db.Create(&dao.AsyncSms{
+		Id: id,
+		Config: sqlx.JsonColumn[dao.SmsConfig]{
+			Val: dao.SmsConfig{
+				TplId:   "tpl",
+				Args:    []string{"123456"},
+				Numbers: []string{"15212345678"},
+			},
+			Valid: true,
+		},
+		RetryCnt: retryCnt,
+		RetryMax: 3,
+		Status:   status,
+		Ctime:    utime.UnixMilli(),
+		Utime:    utime.UnixMilli(),
+	}).Error
+	require.NoError(t, err)
+}
+
+func TestAsyncSms(t *testing.T) {
+	suite.Run(t, new(AsyncSmsTestSuite))
+}
+
+// fakeSmsProvider 本地的短信服务商，记录发送了多少次
+type fakeSmsProvider struct {
+	lock  sync.Mutex
+	err   error
+	sends int
+}
+
+func (f *fakeSmsProvider) Send(ctx context.Context, tplId string, args []string, numbers ...string) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.sends++
+	return f.err
+}
+
+func (f *fakeSmsProvider) cnt() int {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	return f.sends
+}
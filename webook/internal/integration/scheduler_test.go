@@ -27,6 +27,8 @@ func (s *SchedulerTestSuite) SetupSuite() {
 func (s *SchedulerTestSuite) TearDownSuite() {
 	err := s.db.Exec("TRUNCATE TABLE `jobs`").Error
 	assert.NoError(s.T(), err)
+	err = s.db.Exec("TRUNCATE TABLE `job_executions`").Error
+	assert.NoError(s.T(), err)
 }
 
 // TestSchedule 测试调度
@@ -43,6 +45,8 @@ func (s *SchedulerTestSuite) TestSchedule() {
 		interval time.Duration
 		wantErr  error
 		wantJob  *testJob
+		// 注册的本地方法，默认是 test_job
+		jobName string
 	}{
 		{
 			name: "测试JOB",
@@ -73,6 +77,8 @@ func (s *SchedulerTestSuite) TestSchedule() {
 				j.Ctime = 0
 				assert.True(t, j.Utime > 0)
 				j.Utime = 0
+				assert.True(t, j.LastRunTime > 0)
+				j.LastRunTime = 0
 				assert.Equal(t, dao.Job{
 					Id:       1,
 					Name:     "test_job",
@@ -92,6 +98,41 @@ func (s *SchedulerTestSuite) TestSchedule() {
 			// 开始调度一秒钟
 			interval: time.Second,
 		},
+		{
+			name: "执行的节点崩溃了，别的节点接手",
+			before: func(t *testing.T) {
+				// 模拟一个节点抢占之后崩溃了，状态一直是运行中，也没有续约
+				j := dao.Job{
+					Id:         2,
+					Name:       "crashed_job",
+					Executor:   "local",
+					Expression: "*/5 * * * * ?",
+					Status:     1,
+					Version:    3,
+					NextTime:   time.Now().UnixMilli(),
+					Ctime:      123,
+					Utime:      time.Now().Add(-10 * time.Minute).UnixMilli(),
+				}
+				err := s.db.Create(&j).Error
+				assert.NoError(t, err)
+			},
+			after: func(t *testing.T) {
+				var j dao.Job
+				err := s.db.Where("id=?", 2).First(&j).Error
+				assert.NoError(t, err)
+				assert.True(t, j.NextTime > time.Now().UnixMilli())
+				// 执行完了之后释放掉
+				assert.Equal(t, 0, j.Status)
+				// 抢占了续约超时的任务，版本也会升高
+				assert.Equal(t, 4, j.Version)
+				assert.True(t, j.LastRunTime > 0)
+				assert.Equal(t, "", j.LastRunErr)
+			},
+			wantErr:  context.DeadlineExceeded,
+			wantJob:  &testJob{cnt: 1},
+			interval: time.Second,
+			jobName:  "crashed_job",
+		},
 	}
 
 	for _, tc := range testCases {
@@ -100,7 +141,11 @@ func (s *SchedulerTestSuite) TestSchedule() {
 			defer tc.after(t)
 			exec := job.NewLocalFuncExecutor()
 			j := &testJob{}
-			exec.RegisterFunc("test_job", j.Do)
+			jobName := tc.jobName
+			if jobName == "" {
+				jobName = "test_job"
+			}
+			exec.RegisterFunc(jobName, j.Do)
 			s.scheduler.RegisterExecutor(exec)
 			ctx, cancel := context.WithTimeout(context.Background(), tc.interval)
 			defer cancel()
@@ -6,6 +6,8 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/google/wire"
 	"github.com/wsqigo/basic-go/webook/internal/events/article"
+	"github.com/wsqigo/basic-go/webook/internal/events/follow"
+	"github.com/wsqigo/basic-go/webook/internal/events/user"
 	"github.com/wsqigo/basic-go/webook/internal/job"
 	"github.com/wsqigo/basic-go/webook/internal/repository"
 	"github.com/wsqigo/basic-go/webook/internal/repository/cache"
@@ -26,12 +28,15 @@ var thirdPartySet = wire.NewSet(
 var jobProviderSet = wire.NewSet(
 	service.NewCronJobService,
 	repository.NewPreemptJobRepository,
-	dao.NewGORMJobDAO)
+	repository.NewDBJobExecutionRepository,
+	dao.NewGORMJobDAO,
+	dao.NewGORMJobExecutionDAO)
 
 var userSvcProvider = wire.NewSet(
 	dao.NewUserDao,
 	cache.NewUserCache,
 	repository.NewUserRepository,
+	user.NewSaramaSyncProducer,
 	service.NewUserService)
 
 var articleSvcProvider = wire.NewSet(
@@ -46,12 +51,50 @@ var interactiveSvcSet = wire.NewSet(
 	repository.NewCachedInteractiveRepository,
 	service.NewInteractiveService)
 
+var commentSvcSet = wire.NewSet(
+	dao.NewGORMCommentDAO,
+	repository.NewCachedCommentRepository,
+	service.NewCommentService)
+
+var collectionSvcSet = wire.NewSet(
+	dao.NewGORMCollectionDAO,
+	repository.NewCachedCollectionRepository,
+	service.NewCollectionService)
+
+var followSvcSet = wire.NewSet(
+	dao.NewGORMFollowRelationDAO,
+	cache.NewFollowRedisCache,
+	repository.NewCachedFollowRepository,
+	follow.NewSaramaSyncProducer,
+	service.NewFollowService)
+
+var feedSvcSet = wire.NewSet(
+	cache.NewFeedRedisCache,
+	repository.NewCachedFeedRepository,
+	service.NewFeedService)
+
+var searchSvcSet = wire.NewSet(
+	repository.NewMemorySearchRepository,
+	service.NewSearchService)
+
+var historySvcSet = wire.NewSet(
+	dao.NewGORMHistoryRecordDAO,
+	repository.NewDBHistoryRecordRepository,
+	service.NewHistoryRecordService)
+
 func InitWebServer() *gin.Engine {
 	wire.Build(
 		thirdPartySet,
 		userSvcProvider,
 		articleSvcProvider,
 		interactiveSvcSet,
+		commentSvcSet,
+		collectionSvcSet,
+		followSvcSet,
+		feedSvcSet,
+		searchSvcSet,
+		historySvcSet,
+		jobProviderSet,
 
 		// cache 部分
 		cache.NewCodeCache,
@@ -64,6 +107,7 @@ func InitWebServer() *gin.Engine {
 		// Service 部分
 		ioc.InitSMSService,
 		service.NewCodeService,
+		ioc.InitCodeSendGuard,
 		InitDingDingService,
 
 		// handler 部分
@@ -71,6 +115,13 @@ func InitWebServer() *gin.Engine {
 		jwt2.NewRedisJWTHandler,
 		web.NewOAuth2DingDingHandler,
 		web.NewArticleHandler,
+		web.NewCommentHandler,
+		web.NewCollectionHandler,
+		web.NewFollowHandler,
+		web.NewFeedHandler,
+		web.NewSearchHandler,
+		web.NewHistoryHandler,
+		ioc.InitCronJobHandler,
 
 		ioc.InitGinMiddlewares,
 		ioc.InitWebServer,
@@ -98,6 +149,7 @@ func InitInteractiveService() service.InteractiveService {
 }
 
 func InitJobScheduler() *job.Scheduler {
-	wire.Build(jobProviderSet, thirdPartySet, job.NewScheduler)
+	wire.Build(jobProviderSet, thirdPartySet,
+		cache.NewNodeLoadRedisCache, ioc.InitLoadBalancer, job.NewScheduler)
 	return &job.Scheduler{}
 }
@@ -10,6 +10,8 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/google/wire"
 	"github.com/wsqigo/basic-go/webook/internal/events/article"
+	"github.com/wsqigo/basic-go/webook/internal/events/follow"
+	"github.com/wsqigo/basic-go/webook/internal/events/user"
 	"github.com/wsqigo/basic-go/webook/internal/job"
 	"github.com/wsqigo/basic-go/webook/internal/repository"
 	"github.com/wsqigo/basic-go/webook/internal/repository/cache"
@@ -31,19 +33,21 @@ func InitWebServer() *gin.Engine {
 	userDAO := dao.NewUserDao(db)
 	userCache := cache.NewUserCache(cmdable)
 	userRepository := repository.NewUserRepository(userDAO, userCache)
-	userService := service.NewUserService(userRepository)
+	client := InitSaramaClient()
+	syncProducer := InitSyncProducer(client)
+	producer := user.NewSaramaSyncProducer(syncProducer)
+	userService := service.NewUserService(userRepository, producer)
 	codeCache := cache.NewCodeCache(cmdable)
 	codeRepository := repository.NewCodeRepository(codeCache)
-	smsService := ioc.InitSMSService()
+	smsService := ioc.InitSMSService(loggerV1)
 	codeService := service.NewCodeService(codeRepository, smsService)
-	userHandler := web.NewUserHandler(userService, handler, codeService)
+	codeSendGuard := ioc.InitCodeSendGuard(cmdable)
+	userHandler := web.NewUserHandler(userService, handler, codeService, codeSendGuard)
 	articleDAO := dao.NewArticleGORMDAO(db)
 	articleCache := cache.NewArticleRedisCache(cmdable)
 	articleRepository := repository.NewCachedArticleRepository(articleDAO, userRepository, articleCache, loggerV1)
-	client := InitSaramaClient()
-	syncProducer := InitSyncProducer(client)
-	producer := article.NewSaramaSyncProducer(syncProducer)
-	articleService := service.NewArticleService(articleRepository, producer)
+	articleProducer := article.NewSaramaSyncProducer(syncProducer)
+	articleService := service.NewArticleService(articleRepository, articleProducer, loggerV1)
 	interactiveDAO := dao.NewGORMInteractiveDAO(db)
 	interactiveCache := cache.NewInteractiveRedisCache(cmdable)
 	interactiveRepository := repository.NewCachedInteractiveRepository(interactiveDAO, loggerV1, interactiveCache)
@@ -51,7 +55,38 @@ func InitWebServer() *gin.Engine {
 	articleHandler := web.NewArticleHandler(loggerV1, articleService, interactiveService)
 	dingdingService := InitDingDingService(loggerV1)
 	oAuth2DingDingHandler := web.NewOAuth2DingDingHandler(dingdingService, handler, userService)
-	engine := ioc.InitWebServer(v, userHandler, articleHandler, oAuth2DingDingHandler)
+	commentDAO := dao.NewGORMCommentDAO(db)
+	commentRepository := repository.NewCachedCommentRepository(commentDAO, interactiveCache, loggerV1)
+	commentService := service.NewCommentService(commentRepository, articleRepository)
+	commentHandler := web.NewCommentHandler(commentService, loggerV1)
+	collectionDAO := dao.NewGORMCollectionDAO(db)
+	collectionRepository := repository.NewCachedCollectionRepository(collectionDAO, interactiveCache, loggerV1)
+	collectionService := service.NewCollectionService(collectionRepository)
+	collectionHandler := web.NewCollectionHandler(collectionService, loggerV1)
+	followRelationDAO := dao.NewGORMFollowRelationDAO(db)
+	followCache := cache.NewFollowRedisCache(cmdable)
+	followRepository := repository.NewCachedFollowRepository(followRelationDAO, followCache, loggerV1)
+	followProducer := follow.NewSaramaSyncProducer(syncProducer)
+	followService := service.NewFollowService(followRepository, followProducer, loggerV1)
+	followHandler := web.NewFollowHandler(followService, loggerV1)
+	feedCache := cache.NewFeedRedisCache(cmdable)
+	feedRepository := repository.NewCachedFeedRepository(feedCache)
+	feedService := service.NewFeedService(feedRepository, followRepository, articleRepository)
+	feedHandler := web.NewFeedHandler(feedService, loggerV1)
+	searchRepository := repository.NewMemorySearchRepository()
+	searchService := service.NewSearchService(searchRepository, articleRepository, userRepository)
+	searchHandler := web.NewSearchHandler(searchService, loggerV1)
+	historyRecordDAO := dao.NewGORMHistoryRecordDAO(db)
+	historyRecordRepository := repository.NewDBHistoryRecordRepository(historyRecordDAO)
+	historyRecordService := service.NewHistoryRecordService(historyRecordRepository)
+	historyHandler := web.NewHistoryHandler(historyRecordService, loggerV1)
+	jobDAO := dao.NewGORMJobDAO(db)
+	cronJobRepository := repository.NewPreemptJobRepository(jobDAO)
+	jobExecutionDAO := dao.NewGORMJobExecutionDAO(db)
+	jobExecutionRepository := repository.NewDBJobExecutionRepository(jobExecutionDAO)
+	cronJobService := service.NewCronJobService(cronJobRepository, jobExecutionRepository, loggerV1)
+	cronJobHandler := ioc.InitCronJobHandler(cronJobService, loggerV1)
+	engine := ioc.InitWebServer(v, userHandler, articleHandler, oAuth2DingDingHandler, commentHandler, collectionHandler, followHandler, feedHandler, searchHandler, historyHandler, cronJobHandler)
 	return engine
 }
 
@@ -67,7 +102,7 @@ func InitArticleHandler(dao2 dao.ArticleDAO) *web.ArticleHandler {
 	client := InitSaramaClient()
 	syncProducer := InitSyncProducer(client)
 	producer := article.NewSaramaSyncProducer(syncProducer)
-	articleService := service.NewArticleService(articleRepository, producer)
+	articleService := service.NewArticleService(articleRepository, producer, loggerV1)
 	interactiveDAO := dao.NewGORMInteractiveDAO(db)
 	interactiveCache := cache.NewInteractiveRedisCache(cmdable)
 	interactiveRepository := repository.NewCachedInteractiveRepository(interactiveDAO, loggerV1, interactiveCache)
@@ -91,9 +126,14 @@ func InitJobScheduler() *job.Scheduler {
 	db := InitDB()
 	jobDAO := dao.NewGORMJobDAO(db)
 	cronJobRepository := repository.NewPreemptJobRepository(jobDAO)
+	jobExecutionDAO := dao.NewGORMJobExecutionDAO(db)
+	jobExecutionRepository := repository.NewDBJobExecutionRepository(jobExecutionDAO)
 	loggerV1 := InitLogger()
-	cronJobService := service.NewCronJobService(cronJobRepository, loggerV1)
-	scheduler := job.NewScheduler(cronJobService, loggerV1)
+	cronJobService := service.NewCronJobService(cronJobRepository, jobExecutionRepository, loggerV1)
+	cmdable := InitRedis()
+	nodeLoadCache := cache.NewNodeLoadRedisCache(cmdable)
+	loadBalancer := ioc.InitLoadBalancer(nodeLoadCache, loggerV1)
+	scheduler := job.NewScheduler(cronJobService, loadBalancer, loggerV1)
 	return scheduler
 }
 
@@ -106,10 +146,22 @@ var thirdPartySet = wire.NewSet(
 	InitSyncProducer,
 	InitLogger)
 
-var jobProviderSet = wire.NewSet(service.NewCronJobService, repository.NewPreemptJobRepository, dao.NewGORMJobDAO)
+var jobProviderSet = wire.NewSet(service.NewCronJobService, repository.NewPreemptJobRepository, repository.NewDBJobExecutionRepository, dao.NewGORMJobDAO, dao.NewGORMJobExecutionDAO)
 
-var userSvcProvider = wire.NewSet(dao.NewUserDao, cache.NewUserCache, repository.NewUserRepository, service.NewUserService)
+var userSvcProvider = wire.NewSet(dao.NewUserDao, cache.NewUserCache, repository.NewUserRepository, user.NewSaramaSyncProducer, service.NewUserService)
 
 var articleSvcProvider = wire.NewSet(repository.NewCachedArticleRepository, cache.NewArticleRedisCache, dao.NewArticleGORMDAO, service.NewArticleService)
 
 var interactiveSvcSet = wire.NewSet(dao.NewGORMInteractiveDAO, cache.NewInteractiveRedisCache, repository.NewCachedInteractiveRepository, service.NewInteractiveService)
+
+var commentSvcSet = wire.NewSet(dao.NewGORMCommentDAO, repository.NewCachedCommentRepository, service.NewCommentService)
+
+var collectionSvcSet = wire.NewSet(dao.NewGORMCollectionDAO, repository.NewCachedCollectionRepository, service.NewCollectionService)
+
+var followSvcSet = wire.NewSet(dao.NewGORMFollowRelationDAO, cache.NewFollowRedisCache, repository.NewCachedFollowRepository, follow.NewSaramaSyncProducer, service.NewFollowService)
+
+var feedSvcSet = wire.NewSet(cache.NewFeedRedisCache, repository.NewCachedFeedRepository, service.NewFeedService)
+
+var searchSvcSet = wire.NewSet(repository.NewMemorySearchRepository, service.NewSearchService)
+
+var historySvcSet = wire.NewSet(dao.NewGORMHistoryRecordDAO, repository.NewDBHistoryRecordRepository, service.NewHistoryRecordService)
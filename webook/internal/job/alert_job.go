@@ -0,0 +1,50 @@
+package job
+
+import (
+	"context"
+	"sync"
+)
+
+// AlertFunc 告警，cnt 是连续失败的次数，err 是最近一次失败的原因
+type AlertFunc func(ctx context.Context, name string, cnt int, err error)
+
+// AlertJob 连续失败 threshold 次之后告警
+// 一直失败的话，每连续失败 threshold 次就再告警一次，成功一次就重新计数
+type AlertJob struct {
+	job       Job
+	threshold int
+	alert     AlertFunc
+
+	lock sync.Mutex
+	// 连续失败的次数
+	failCnt int
+}
+
+// NewAlertJob threshold 小于 1 的时候按照 1 处理，也就是每次失败都告警
+func NewAlertJob(job Job, threshold int, alert AlertFunc) *AlertJob {
+	if threshold < 1 {
+		threshold = 1
+	}
+	return &AlertJob{job: job, threshold: threshold, alert: alert}
+}
+
+func (a *AlertJob) Name() string {
+	return a.job.Name()
+}
+
+func (a *AlertJob) Run(ctx context.Context) error {
+	err := a.job.Run(ctx)
+	a.lock.Lock()
+	if err == nil {
+		a.failCnt = 0
+		a.lock.Unlock()
+		return nil
+	}
+	a.failCnt++
+	cnt := a.failCnt
+	a.lock.Unlock()
+	if cnt%a.threshold == 0 {
+		a.alert(ctx, a.job.Name(), cnt, err)
+	}
+	return err
+}
@@ -0,0 +1,33 @@
+package job
+
+import (
+	"context"
+	"github.com/wsqigo/basic-go/webook/internal/service/sms/async"
+	"github.com/wsqigo/basic-go/webook/pkg/logger"
+	"time"
+)
+
+// AsyncSmsCleanJob 删除已经发送成功或者彻底失败的异步短信
+type AsyncSmsCleanJob struct {
+	svc *async.Service
+	l   logger.LoggerV1
+	// 结束之后保留多久，方便排查问题
+	retention time.Duration
+}
+
+func NewAsyncSmsCleanJob(svc *async.Service, l logger.LoggerV1, retention time.Duration) *AsyncSmsCleanJob {
+	return &AsyncSmsCleanJob{svc: svc, l: l, retention: retention}
+}
+
+func (a *AsyncSmsCleanJob) Name() string {
+	return "async_sms_clean"
+}
+
+func (a *AsyncSmsCleanJob) Run(ctx context.Context) error {
+	before := time.Now().Add(-a.retention)
+	cnt, err := a.svc.DeleteFinished(ctx, before)
+	a.l.Info("清理已经结束的异步短信",
+		logger.Int64("cnt", cnt),
+		logger.String("before", before.Format(time.DateTime)))
+	return err
+}
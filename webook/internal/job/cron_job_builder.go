@@ -1,6 +1,7 @@
 package job
 
 import (
+	"context"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/robfig/cron/v3"
 	"github.com/wsqigo/basic-go/webook/pkg/logger"
@@ -28,7 +29,7 @@ func (b *CronJobBuilder) Build(job Job) cron.Job {
 		start := time.Now()
 		b.l.Debug("开始运行", logger.String("name", name))
 
-		err := job.Run()
+		err := job.Run(context.Background())
 		if err != nil {
 			b.l.Error("执行失败",
 				logger.Error(err),
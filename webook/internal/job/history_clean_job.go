@@ -0,0 +1,44 @@
+package job
+
+import (
+	"context"
+	"github.com/wsqigo/basic-go/webook/internal/service"
+	"github.com/wsqigo/basic-go/webook/pkg/logger"
+	"time"
+)
+
+// HistoryCleanJob 删除超过保留期限的阅读记录
+// 删除本身是幂等的，多个节点同时跑也只是浪费一点资源，所以这里没有用分布式锁
+type HistoryCleanJob struct {
+	svc service.HistoryRecordService
+	l   logger.LoggerV1
+	// 阅读记录保留多久
+	retention time.Duration
+	// 一次运行超时的时间
+	timeout time.Duration
+}
+
+func NewHistoryCleanJob(svc service.HistoryRecordService, l logger.LoggerV1,
+	retention time.Duration, timeout time.Duration) *HistoryCleanJob {
+	return &HistoryCleanJob{
+		svc:       svc,
+		l:         l,
+		retention: retention,
+		timeout:   timeout,
+	}
+}
+
+func (h *HistoryCleanJob) Name() string {
+	return "history_clean"
+}
+
+func (h *HistoryCleanJob) Run(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+	before := time.Now().Add(-h.retention)
+	cnt, err := h.svc.DeleteExpired(ctx, before)
+	h.l.Info("清理过期阅读记录",
+		logger.Int64("cnt", cnt),
+		logger.String("before", before.Format(time.DateTime)))
+	return err
+}
@@ -0,0 +1,67 @@
+package job
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// HttpJobClient 用 HTTP POST 把任务发给远程服务
+// 远程服务返回 2xx，并且响应是 RemoteJobResponse 且 Code 为 0 才算成功，响应体为空也算成功
+type HttpJobClient struct {
+	client   *http.Client
+	endpoint string
+}
+
+func NewHttpJobClient(client *http.Client, endpoint string) *HttpJobClient {
+	return &HttpJobClient{
+		client:   client,
+		endpoint: endpoint,
+	}
+}
+
+// NewHttpExecutor 创建一个把任务 POST 到 endpoint 的执行器
+func NewHttpExecutor(name string, endpoint string, timeout time.Duration) *RemoteExecutor {
+	return NewRemoteExecutor(name, NewHttpJobClient(http.DefaultClient, endpoint), timeout)
+}
+
+func (h *HttpJobClient) Run(ctx context.Context, req RemoteJobRequest) error {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, h.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	resp, err := h.client.Do(httpReq)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	// 响应不会很大，限制一下避免对方返回了奇怪的东西
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("远程执行任务失败，状态码 %d，响应 %s", resp.StatusCode, data)
+	}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil
+	}
+	var res RemoteJobResponse
+	err = json.Unmarshal(data, &res)
+	if err != nil {
+		return fmt.Errorf("无法解析远程执行任务的响应 %s: %w", data, err)
+	}
+	if res.Code != 0 {
+		return fmt.Errorf("远程执行任务失败，code %d，msg %s", res.Code, res.Msg)
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package job
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wsqigo/basic-go/webook/internal/domain"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHttpExecutor_Exec(t *testing.T) {
+	testCases := []struct {
+		name    string
+		handler http.HandlerFunc
+		timeout time.Duration
+
+		wantErr bool
+	}{
+		{
+			name: "执行成功",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				var req RemoteJobRequest
+				err := json.NewDecoder(r.Body).Decode(&req)
+				require.NoError(t, err)
+				assert.Equal(t, http.MethodPost, r.Method)
+				assert.Equal(t, int64(1), req.JobId)
+				assert.Equal(t, "ranking", req.Name)
+				assert.Equal(t, `{"topN":100}`, req.Cfg)
+				_, _ = w.Write([]byte(`{"code":0,"msg":"OK"}`))
+			},
+			timeout: time.Second,
+		},
+		{
+			name: "响应体为空也算成功",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNoContent)
+			},
+			timeout: time.Second,
+		},
+		{
+			name: "业务失败",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(`{"code":5,"msg":"系统错误"}`))
+			},
+			timeout: time.Second,
+			wantErr: true,
+		},
+		{
+			name: "状态码不对",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusInternalServerError)
+			},
+			timeout: time.Second,
+			wantErr: true,
+		},
+		{
+			name: "超时",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				select {
+				case <-r.Context().Done():
+				case <-time.After(time.Second):
+				}
+			},
+			timeout: 50 * time.Millisecond,
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(tc.handler)
+			defer server.Close()
+			exec := NewHttpExecutor("http", server.URL, tc.timeout)
+			err := exec.Exec(context.Background(), domain.Job{
+				Id:   1,
+				Name: "ranking",
+				Cfg:  `{"topN":100}`,
+			})
+			assert.Equal(t, tc.wantErr, err != nil)
+		})
+	}
+}
@@ -1,9 +1,12 @@
 package job
 
+import "context"
+
 // Job 为了便于控制（方便扩展），我们使用自己的接口
 // 在这个基础上，
 // 你可以考虑引入重试，监控和告警等扩展实现（都是装饰器）
+// 超时控制和链路追踪都依赖 ctx，所以 Run 要接收 ctx
 type Job interface {
 	Name() string
-	Run() error
+	Run(ctx context.Context) error
 }
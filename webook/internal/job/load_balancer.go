@@ -0,0 +1,106 @@
+package job
+
+import (
+	"context"
+	"fmt"
+	"github.com/wsqigo/basic-go/webook/internal/repository/cache"
+	"github.com/wsqigo/basic-go/webook/pkg/logger"
+	"os"
+	"runtime"
+	"sync/atomic"
+	"time"
+)
+
+// LoadFunc 计算本节点的负载，值越大负载越高
+type LoadFunc func() int64
+
+// GoroutineLoad 用 goroutine 数量作为负载
+func GoroutineLoad() int64 {
+	return int64(runtime.NumGoroutine())
+}
+
+// LoadBalancer 定时把本节点的负载上报到 Redis，
+// 并且判断本节点是不是负载最低的 topK 个节点之一。
+// 只有负载足够低的节点，才去抢占任务或者持有分布式锁
+type LoadBalancer struct {
+	cache  cache.NodeLoadCache
+	nodeId string
+	topK   int64
+	// 多久上报一次负载
+	interval time.Duration
+	timeout  time.Duration
+	loadFunc LoadFunc
+	l        logger.LoggerV1
+
+	available atomic.Bool
+}
+
+func NewLoadBalancer(cache cache.NodeLoadCache, topK int64,
+	interval time.Duration, loadFunc LoadFunc, l logger.LoggerV1) *LoadBalancer {
+	hostname, _ := os.Hostname()
+	b := &LoadBalancer{
+		cache:    cache,
+		nodeId:   fmt.Sprintf("%s_%d", hostname, os.Getpid()),
+		topK:     topK,
+		interval: interval,
+		timeout:  time.Second,
+		loadFunc: loadFunc,
+		l:        l,
+	}
+	// 还没有上报过负载的时候，不能让所有的节点都停下来
+	b.available.Store(true)
+	return b
+}
+
+// Available 本节点的负载是不是在 topK 以内。
+// 没有配置负载均衡（nil）的时候，所有节点都可以调度
+func (b *LoadBalancer) Available() bool {
+	if b == nil {
+		return true
+	}
+	return b.available.Load()
+}
+
+// Start 会阻塞，直到 ctx 被取消
+func (b *LoadBalancer) Start(ctx context.Context) {
+	ticker := time.NewTicker(b.interval)
+	defer ticker.Stop()
+	for {
+		b.refresh(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (b *LoadBalancer) refresh(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, b.timeout)
+	defer cancel()
+	load := b.loadFunc()
+	err := b.cache.Report(ctx, b.nodeId, load)
+	if err != nil {
+		// Redis 出了问题，维持上一次的判断
+		b.l.Error("上报节点负载失败",
+			logger.String("node", b.nodeId),
+			logger.Error(err))
+		return
+	}
+	rank, err := b.cache.Rank(ctx, b.nodeId)
+	if err != nil {
+		b.l.Error("查询节点负载排名失败",
+			logger.String("node", b.nodeId),
+			logger.Error(err))
+		return
+	}
+	// 刚刚上报过，理论上不会是 -1，保险起见也认为可以执行
+	available := rank < b.topK
+	if b.available.Swap(available) != available {
+		b.l.Info("节点负载排名变化",
+			logger.String("node", b.nodeId),
+			logger.Int64("load", load),
+			logger.Int64("rank", rank),
+			logger.Bool("available", available))
+	}
+}
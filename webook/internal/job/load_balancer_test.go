@@ -0,0 +1,87 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	cachemocks "github.com/wsqigo/basic-go/webook/internal/repository/cache/mocks"
+	"github.com/wsqigo/basic-go/webook/pkg/logger"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+)
+
+func TestLoadBalancer_refresh(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) *cachemocks.MockNodeLoadCache
+		// 刷新之前的状态
+		before bool
+
+		wantAvailable bool
+	}{
+		{
+			name: "在 topK 以内",
+			mock: func(ctrl *gomock.Controller) *cachemocks.MockNodeLoadCache {
+				c := cachemocks.NewMockNodeLoadCache(ctrl)
+				c.EXPECT().Report(gomock.Any(), gomock.Any(), int64(10)).Return(nil)
+				c.EXPECT().Rank(gomock.Any(), gomock.Any()).Return(int64(2), nil)
+				return c
+			},
+			before:        false,
+			wantAvailable: true,
+		},
+		{
+			name: "负载太高，不在 topK 以内",
+			mock: func(ctrl *gomock.Controller) *cachemocks.MockNodeLoadCache {
+				c := cachemocks.NewMockNodeLoadCache(ctrl)
+				c.EXPECT().Report(gomock.Any(), gomock.Any(), int64(10)).Return(nil)
+				c.EXPECT().Rank(gomock.Any(), gomock.Any()).Return(int64(3), nil)
+				return c
+			},
+			before:        true,
+			wantAvailable: false,
+		},
+		{
+			name: "上报失败，维持原来的判断",
+			mock: func(ctrl *gomock.Controller) *cachemocks.MockNodeLoadCache {
+				c := cachemocks.NewMockNodeLoadCache(ctrl)
+				c.EXPECT().Report(gomock.Any(), gomock.Any(), int64(10)).
+					Return(errors.New("redis 错误"))
+				return c
+			},
+			before:        false,
+			wantAvailable: false,
+		},
+		{
+			name: "查询排名失败，维持原来的判断",
+			mock: func(ctrl *gomock.Controller) *cachemocks.MockNodeLoadCache {
+				c := cachemocks.NewMockNodeLoadCache(ctrl)
+				c.EXPECT().Report(gomock.Any(), gomock.Any(), int64(10)).Return(nil)
+				c.EXPECT().Rank(gomock.Any(), gomock.Any()).
+					Return(int64(0), errors.New("redis 错误"))
+				return c
+			},
+			before:        true,
+			wantAvailable: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			b := NewLoadBalancer(tc.mock(ctrl), 3, time.Second, func() int64 {
+				return 10
+			}, logger.NewNopLogger())
+			b.available.Store(tc.before)
+			b.refresh(context.Background())
+			assert.Equal(t, tc.wantAvailable, b.Available())
+		})
+	}
+}
+
+func TestLoadBalancer_AvailableNil(t *testing.T) {
+	var b *LoadBalancer
+	assert.True(t, b.Available())
+}
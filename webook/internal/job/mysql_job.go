@@ -3,10 +3,14 @@ package job
 import (
 	"context"
 	"fmt"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/wsqigo/basic-go/webook/internal/domain"
 	"github.com/wsqigo/basic-go/webook/internal/service"
 	"github.com/wsqigo/basic-go/webook/pkg/logger"
 	"golang.org/x/sync/semaphore"
+	"os"
+	"strconv"
+	"sync"
 	"time"
 )
 
@@ -44,34 +48,74 @@ func (l *LocalFuncExecutor) Exec(ctx context.Context, j domain.Job) error {
 
 type Scheduler struct {
 	dbTimeout time.Duration
+	// 没有抢占到任务的时候，隔多久再试
+	pollInterval time.Duration
 
 	svc       service.CronJobService
 	executors map[string]Executor
-	l         logger.LoggerV1
+	// 本节点负载太高的时候，不去抢占任务
+	balancer *LoadBalancer
+	l        logger.LoggerV1
+	// 执行记录里面标记是哪个节点执行的
+	nodeId string
 
 	limiter *semaphore.Weighted
+	// 正在执行的任务，退出调度的时候要等它们结束
+	wg sync.WaitGroup
+	// 按照执行器统计成功和失败的次数
+	counter *prometheus.CounterVec
 }
 
-func NewScheduler(svc service.CronJobService, l logger.LoggerV1) *Scheduler {
+func NewScheduler(svc service.CronJobService, balancer *LoadBalancer, l logger.LoggerV1) *Scheduler {
+	hostname, _ := os.Hostname()
 	return &Scheduler{
-		dbTimeout: time.Second,
-		svc:       svc,
-		limiter:   semaphore.NewWeighted(100),
-		l:         l,
-		executors: map[string]Executor{},
+		dbTimeout:    time.Second,
+		pollInterval: 100 * time.Millisecond,
+		svc:          svc,
+		balancer:     balancer,
+		limiter:      semaphore.NewWeighted(100),
+		l:            l,
+		executors:    map[string]Executor{},
+		nodeId:       fmt.Sprintf("%s_%d", hostname, os.Getpid()),
+		counter:      newExecutionCounter(),
 	}
 }
 
+func newExecutionCounter() *prometheus.CounterVec {
+	vector := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "geekbang_daming",
+		Subsystem: "webook",
+		Name:      "job_execution",
+		Help:      "统计任务执行的成功和失败次数",
+	}, []string{"executor", "success"})
+	// 测试里面可能会创建多个 Scheduler，重复注册的时候复用已有的
+	err := prometheus.Register(vector)
+	if are, ok := err.(prometheus.AlreadyRegisteredError); ok {
+		return are.ExistingCollector.(*prometheus.CounterVec)
+	}
+	return vector
+}
+
 func (s *Scheduler) RegisterExecutor(exec Executor) {
 	s.executors[exec.Name()] = exec
 }
 
 func (s *Scheduler) Schedule(ctx context.Context) error {
+	// 正在执行的任务会因为 ctx 取消而中断，等它们释放掉抢占的任务再返回
+	defer s.wg.Wait()
 	for {
 		// 放弃调度了
 		if ctx.Err() != nil {
 			return ctx.Err()
 		}
+		if !s.balancer.Available() {
+			// 本节点负载太高了，让负载低的节点去抢占
+			select {
+			case <-ctx.Done():
+			case <-time.After(s.pollInterval):
+			}
+			continue
+		}
 		err := s.limiter.Acquire(ctx, 1)
 		if err != nil {
 			return err
@@ -82,8 +126,13 @@ func (s *Scheduler) Schedule(ctx context.Context) error {
 		j, err := s.svc.Preempt(dbCtx)
 		cancel()
 		if err != nil {
-			// 有 Error
-			// 最简单的做法就是直接下一轮，也可以睡一段时间
+			// 有 Error，大多数时候是没有可以调度的任务
+			// 睡一段时间再进入下一轮，避免一直查数据库
+			s.limiter.Release(1)
+			select {
+			case <-ctx.Done():
+			case <-time.After(s.pollInterval):
+			}
 			continue
 		}
 
@@ -94,23 +143,41 @@ func (s *Scheduler) Schedule(ctx context.Context) error {
 			s.l.Error("找不到执行器",
 				logger.Int64("jid", j.Id),
 				logger.String("executor", j.Executor))
+			s.limiter.Release(1)
+			j.CancelFunc()
 			continue
 		}
 
 		// 要单独开一个 goroutine 来执行，这样我们就可以进入下一个调度
+		s.wg.Add(1)
 		go func() {
+			// 任务被别的节点抢走了，就中断执行
+			execCtx, cancel := context.WithCancel(ctx)
 			defer func() {
+				defer s.wg.Done()
+				cancel()
 				s.limiter.Release(1)
 				// 这边要释放掉
 				j.CancelFunc()
 			}()
-			err1 := exec.Exec(ctx, j)
+			go func() {
+				select {
+				case <-j.LeaseLost:
+					cancel()
+				case <-execCtx.Done():
+				}
+			}()
+			err1 := s.execWithRetry(execCtx, exec, j)
 			if err1 != nil {
 				s.l.Error("执行任务失败",
 					logger.Int64("jid", j.Id),
 					logger.Error(err1))
+			}
+			if execCtx.Err() != nil {
+				// 被别的节点抢走了，或者放弃调度了，下一次调度的时间交给别人
 				return
 			}
+			// 重试都失败了也要等下一次调度，不然释放之后马上又会被抢占执行
 			err1 = s.svc.ResetNextTime(ctx, j)
 			if err1 != nil {
 				s.l.Error("重置下次执行时间失败",
@@ -120,3 +187,54 @@ func (s *Scheduler) Schedule(ctx context.Context) error {
 		}()
 	}
 }
+
+// execWithRetry 按照任务的重试策略执行，每一次执行都会记录下来
+func (s *Scheduler) execWithRetry(ctx context.Context, exec Executor, j domain.Job) error {
+	strategy := j.RetryStrategy()
+	for {
+		e := s.startExecution(j)
+		err := exec.Exec(ctx, j)
+		s.finishExecution(e, err)
+		if err == nil || strategy == nil {
+			return err
+		}
+		interval, ok := strategy.Next()
+		if !ok {
+			return err
+		}
+		s.l.Warn("执行任务失败，准备重试",
+			logger.Int64("jid", j.Id),
+			logger.Error(err))
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+func (s *Scheduler) startExecution(j domain.Job) domain.JobExecution {
+	ctx, cancel := context.WithTimeout(context.Background(), s.dbTimeout)
+	defer cancel()
+	e, err := s.svc.StartExecution(ctx, j, s.nodeId)
+	if err != nil {
+		// 执行记录只是为了审计，记录失败也要继续执行
+		s.l.Error("记录任务开始执行失败",
+			logger.Int64("jid", j.Id),
+			logger.Error(err))
+	}
+	return e
+}
+
+func (s *Scheduler) finishExecution(e domain.JobExecution, runErr error) {
+	s.counter.WithLabelValues(e.Executor, strconv.FormatBool(runErr == nil)).Inc()
+	ctx, cancel := context.WithTimeout(context.Background(), s.dbTimeout)
+	defer cancel()
+	err := s.svc.FinishExecution(ctx, e, runErr)
+	if err != nil {
+		s.l.Error("记录任务执行结果失败",
+			logger.Int64("jid", e.Jid),
+			logger.Int64("eid", e.Id),
+			logger.Error(err))
+	}
+}
@@ -0,0 +1,78 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/wsqigo/basic-go/webook/internal/domain"
+	svcmocks "github.com/wsqigo/basic-go/webook/internal/service/mocks"
+	"github.com/wsqigo/basic-go/webook/pkg/logger"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+)
+
+type mockRemoteJobClient struct {
+	errs []error
+	cnt  int
+}
+
+func (m *mockRemoteJobClient) Run(ctx context.Context, req RemoteJobRequest) error {
+	err := m.errs[m.cnt]
+	m.cnt++
+	return err
+}
+
+func TestScheduler_execWithRetry(t *testing.T) {
+	testCases := []struct {
+		name string
+		errs []error
+		job  domain.Job
+
+		wantCnt int
+		wantErr error
+	}{
+		{
+			name:    "不重试",
+			errs:    []error{errors.New("mock error")},
+			job:     domain.Job{Id: 1},
+			wantCnt: 1,
+			wantErr: errors.New("mock error"),
+		},
+		{
+			name: "重试之后成功",
+			errs: []error{errors.New("mock error"), nil},
+			job: domain.Job{Id: 1, RetryMax: 3,
+				RetryInterval: time.Millisecond},
+			wantCnt: 2,
+		},
+		{
+			name: "重试次数用完",
+			errs: []error{errors.New("mock error 1"),
+				errors.New("mock error 2"), errors.New("mock error 3")},
+			job: domain.Job{Id: 1, RetryMax: 2,
+				RetryInterval: time.Millisecond},
+			wantCnt: 3,
+			wantErr: errors.New("mock error 3"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := &mockRemoteJobClient{errs: tc.errs}
+			exec := NewRemoteExecutor("remote", client, time.Second)
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc := svcmocks.NewMockCronJobService(ctrl)
+			// 每一次执行都要记录下来
+			svc.EXPECT().StartExecution(gomock.Any(), tc.job, gomock.Any()).
+				Return(domain.JobExecution{Jid: tc.job.Id}, nil).Times(tc.wantCnt)
+			svc.EXPECT().FinishExecution(gomock.Any(), gomock.Any(), gomock.Any()).
+				Return(nil).Times(tc.wantCnt)
+			s := NewScheduler(svc, nil, logger.NewNopLogger())
+			err := s.execWithRetry(context.Background(), exec, tc.job)
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantCnt, client.cnt)
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package job
+
+import (
+	"context"
+	"github.com/gotomicro/redis-lock"
+	"github.com/wsqigo/basic-go/webook/internal/events/article"
+	"github.com/wsqigo/basic-go/webook/pkg/logger"
+	"sync"
+	"time"
+)
+
+// OutboxRelayJob 把 outbox 里面待投递的事件全部投递出去
+// 只有拿到分布式锁的节点才投递，不然多个节点会重复投递，
+// 同一篇文章的发表和撤回事件也可能被不同的节点乱序发出去
+type OutboxRelayJob struct {
+	relay     *article.OutboxRelay
+	l         logger.LoggerV1
+	batchSize int
+	// 分布式锁，拿到之后一直续约，节点挂了之后锁过期，别的节点接手
+	client     *rlock.Client
+	key        string
+	expiration time.Duration
+
+	localLock *sync.Mutex
+	lock      *rlock.Lock
+}
+
+func NewOutboxRelayJob(relay *article.OutboxRelay, client *rlock.Client,
+	l logger.LoggerV1, batchSize int) *OutboxRelayJob {
+	return &OutboxRelayJob{
+		relay:      relay,
+		l:          l,
+		batchSize:  batchSize,
+		client:     client,
+		key:        "job:outbox_relay",
+		expiration: 10 * time.Second,
+		localLock:  &sync.Mutex{},
+	}
+}
+
+func (o *OutboxRelayJob) Name() string {
+	return "outbox_relay"
+}
+
+func (o *OutboxRelayJob) Run(ctx context.Context) error {
+	if !o.acquire(ctx) {
+		// 别的节点在投递
+		return nil
+	}
+	for ctx.Err() == nil {
+		cnt, err := o.relay.Relay(ctx, o.batchSize)
+		if err != nil {
+			return err
+		}
+		if cnt < o.batchSize {
+			return nil
+		}
+	}
+	return ctx.Err()
+}
+
+// acquire 已经拿到了分布式锁就直接返回，不然试一次，拿不到就算了，下一秒再试
+func (o *OutboxRelayJob) acquire(ctx context.Context) bool {
+	o.localLock.Lock()
+	defer o.localLock.Unlock()
+	if o.lock != nil {
+		return true
+	}
+	lock, err := o.client.TryLock(ctx, o.key, o.expiration)
+	if err != nil {
+		if err != rlock.ErrFailedToPreemptLock {
+			o.l.Warn("获取 outbox 投递的分布式锁失败", logger.Error(err))
+		}
+		return false
+	}
+	o.lock = lock
+	go func() {
+		er := lock.AutoRefresh(o.expiration/2, time.Second)
+		if er != nil {
+			o.l.Warn("outbox 投递的分布式锁续约失败", logger.Error(er))
+			o.localLock.Lock()
+			// 可能已经换成了新的锁，不能把新的锁清掉
+			if o.lock == lock {
+				o.lock = nil
+			}
+			o.localLock.Unlock()
+		}
+	}()
+	return true
+}
+
+// Close 退出的时候释放分布式锁，别的节点马上就能接手
+func (o *OutboxRelayJob) Close() error {
+	o.localLock.Lock()
+	lock := o.lock
+	o.lock = nil
+	o.localLock.Unlock()
+	if lock == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	return lock.Unlock(ctx)
+}
+
+// OutboxCleanJob 删除已经投递了一段时间的事件
+type OutboxCleanJob struct {
+	relay *article.OutboxRelay
+	l     logger.LoggerV1
+	// 投递之后保留多久，方便排查问题
+	retention time.Duration
+}
+
+func NewOutboxCleanJob(relay *article.OutboxRelay, l logger.LoggerV1, retention time.Duration) *OutboxCleanJob {
+	return &OutboxCleanJob{relay: relay, l: l, retention: retention}
+}
+
+func (o *OutboxCleanJob) Name() string {
+	return "outbox_clean"
+}
+
+func (o *OutboxCleanJob) Run(ctx context.Context) error {
+	before := time.Now().Add(-o.retention)
+	cnt, err := o.relay.Clean(ctx, before)
+	o.l.Info("清理已经投递的 outbox 事件",
+		logger.Int64("cnt", cnt),
+		logger.String("before", before.Format(time.DateTime)))
+	return err
+}
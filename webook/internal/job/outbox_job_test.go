@@ -0,0 +1,55 @@
+package job
+
+import (
+	"context"
+	"github.com/gotomicro/redis-lock"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/wsqigo/basic-go/webook/internal/domain"
+	"github.com/wsqigo/basic-go/webook/internal/events/article"
+	"github.com/wsqigo/basic-go/webook/internal/repository/cache/redismocks"
+	repomocks "github.com/wsqigo/basic-go/webook/internal/repository/mocks"
+	"github.com/wsqigo/basic-go/webook/pkg/logger"
+	"go.uber.org/mock/gomock"
+	"testing"
+)
+
+func TestOutboxRelayJob_Run(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) *OutboxRelayJob
+	}{
+		{
+			name: "别的节点拿着锁，不投递",
+			mock: func(ctrl *gomock.Controller) *OutboxRelayJob {
+				cmd := redismocks.NewMockCmdable(ctrl)
+				cmd.EXPECT().SetNX(gomock.Any(), "job:outbox_relay", gomock.Any(), gomock.Any()).
+					Return(redis.NewBoolResult(false, nil))
+				repo := repomocks.NewMockOutboxRepository(ctrl)
+				return NewOutboxRelayJob(article.NewOutboxRelay(repo, nil, logger.NewNopLogger()),
+					rlock.NewClient(cmd), logger.NewNopLogger(), 100)
+			},
+		},
+		{
+			name: "已经拿到了锁，直接投递",
+			mock: func(ctrl *gomock.Controller) *OutboxRelayJob {
+				repo := repomocks.NewMockOutboxRepository(ctrl)
+				repo.EXPECT().FindPending(gomock.Any(), 100).Return([]domain.OutboxEvent{}, nil)
+				j := NewOutboxRelayJob(article.NewOutboxRelay(repo, nil, logger.NewNopLogger()),
+					nil, logger.NewNopLogger(), 100)
+				j.lock = &rlock.Lock{}
+				return j
+			},
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			j := tc.mock(ctrl)
+			err := j.Run(context.Background())
+			assert.NoError(t, err)
+		})
+	}
+}
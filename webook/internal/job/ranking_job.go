@@ -21,21 +21,22 @@ type RankingJob struct {
 	localLock *sync.Mutex
 	lock      *rlock.Lock
 
-	// 作业提示
-	// 随机生成一个，就代表负载，你可以每隔一分钟生成一个
-	load int32
+	// 负载太高的时候，主动放弃分布式锁
+	balancer *LoadBalancer
 }
 
 func NewRankingJob(
 	svc service.RankingService,
 	l logger.LoggerV1,
 	client *rlock.Client,
+	balancer *LoadBalancer,
 	timeout time.Duration) *RankingJob {
 	return &RankingJob{
 		svc:       svc,
 		key:       "job:ranking",
 		l:         l,
 		client:    client,
+		balancer:  balancer,
 		localLock: &sync.Mutex{},
 		timeout:   timeout,
 	}
@@ -47,17 +48,31 @@ func (r *RankingJob) Name() string {
 
 // go fun() { r.Run()}
 
-func (r *RankingJob) Run() error {
+func (r *RankingJob) Run(ctx context.Context) error {
 	r.localLock.Lock()
 	lock := r.lock
+	if !r.balancer.Available() {
+		// 本节点负载太高了，不去抢锁，已经拿到的锁也让出来，让负载低的节点去计算
+		r.lock = nil
+		r.localLock.Unlock()
+		if lock != nil {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+			err := lock.Unlock(ctx)
+			if err != nil {
+				r.l.Warn("让出分布式锁失败", logger.Error(err))
+			}
+		}
+		return nil
+	}
 	if lock == nil {
 		// 抢分布式锁
-		ctx, cancel := context.WithTimeout(context.Background(), 4*time.Second)
+		lockCtx, cancel := context.WithTimeout(ctx, 4*time.Second)
 		defer cancel()
 		// 加锁本身，我们使用一个ctx
 		// 本身我们这里设计的就是要在 r.timeout 内计算完成
 		// 刚好也做成分布式锁的超时时间
-		lock, err := r.client.Lock(ctx, r.key, r.timeout, // 锁的过期时间
+		lock, err := r.client.Lock(lockCtx, r.key, r.timeout, // 锁的过期时间
 			&rlock.FixIntervalRetry{
 				// 每隔 100 ms 重试一次，每次重试的超时时间是 1s
 				Interval: 100 * time.Millisecond,
@@ -80,14 +95,20 @@ func (r *RankingJob) Run() error {
 				// 续约失败了
 				// 你也没办法中断当下正在调度的热榜计算（如果有）
 				r.localLock.Lock()
-				r.lock = nil
+				// 可能已经换成了新的锁，不能把新的锁清掉
+				if r.lock == lock {
+					r.lock = nil
+				}
 				//lock.Unlock()
 				r.localLock.Unlock()
 			}
 		}()
+	} else {
+		// 之前已经拿到了分布式锁，直接计算
+		r.localLock.Unlock()
 	}
 	// 这边就是你拿到了锁
-	ctx, cancel := context.WithTimeout(context.Background(), r.timeout)
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
 	defer cancel()
 
 	return r.svc.TopN(ctx)
@@ -96,7 +117,11 @@ func (r *RankingJob) Run() error {
 func (r *RankingJob) Close() error {
 	r.localLock.Lock()
 	lock := r.lock
+	r.lock = nil
 	r.localLock.Unlock()
+	if lock == nil {
+		return nil
+	}
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
 	defer cancel()
 	return lock.Unlock(ctx)
@@ -0,0 +1,44 @@
+package job
+
+import (
+	"context"
+	"github.com/gotomicro/redis-lock"
+	"github.com/stretchr/testify/assert"
+	"github.com/wsqigo/basic-go/webook/internal/domain"
+	"github.com/wsqigo/basic-go/webook/pkg/logger"
+	"testing"
+	"time"
+)
+
+type mockRankingService struct {
+	cnt int
+}
+
+func (m *mockRankingService) TopN(ctx context.Context) error {
+	m.cnt++
+	return nil
+}
+
+func (m *mockRankingService) GetTopN(ctx context.Context) ([]domain.Article, error) {
+	return nil, nil
+}
+
+// TestRankingJob_RunWithLock 已经拿到分布式锁之后，每一次运行都要释放本地锁，不然下一次就卡住了
+func TestRankingJob_RunWithLock(t *testing.T) {
+	svc := &mockRankingService{}
+	j := NewRankingJob(svc, logger.NewNopLogger(), nil, nil, time.Minute)
+	j.lock = &rlock.Lock{}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 3; i++ {
+			assert.NoError(t, j.Run(context.Background()))
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("本地锁没有释放")
+	}
+	assert.Equal(t, 3, svc.cnt)
+}
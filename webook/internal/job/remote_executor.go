@@ -0,0 +1,62 @@
+package job
+
+import (
+	"context"
+	"github.com/wsqigo/basic-go/webook/internal/domain"
+	"time"
+)
+
+// RemoteJobRequest 调度远程任务的时候发过去的内容
+// 其它服务实现远程执行器的时候，按照这个结构来解析
+type RemoteJobRequest struct {
+	JobId int64  `json:"jobId"`
+	Name  string `json:"name"`
+	// 任务的配置，原样透传给远程服务
+	Cfg string `json:"cfg"`
+	// 调度的时间，毫秒数
+	ScheduleTime int64 `json:"scheduleTime"`
+}
+
+// RemoteJobResponse 远程服务执行完之后的响应，Code 为 0 代表成功
+type RemoteJobResponse struct {
+	Code int    `json:"code"`
+	Msg  string `json:"msg"`
+}
+
+// RemoteJobClient 调用远程服务执行任务
+// HTTP 的实现是 HttpJobClient，gRPC 之类的协议实现这个接口就可以接入调度
+type RemoteJobClient interface {
+	// Run 返回 nil 代表远程服务执行成功了
+	Run(ctx context.Context, req RemoteJobRequest) error
+}
+
+// RemoteExecutor 把任务交给别的服务执行
+type RemoteExecutor struct {
+	name   string
+	client RemoteJobClient
+	// 一次调用的超时时间
+	timeout time.Duration
+}
+
+func NewRemoteExecutor(name string, client RemoteJobClient, timeout time.Duration) *RemoteExecutor {
+	return &RemoteExecutor{
+		name:    name,
+		client:  client,
+		timeout: timeout,
+	}
+}
+
+func (r *RemoteExecutor) Name() string {
+	return r.name
+}
+
+func (r *RemoteExecutor) Exec(ctx context.Context, j domain.Job) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+	return r.client.Run(ctx, RemoteJobRequest{
+		JobId:        j.Id,
+		Name:         j.Name,
+		Cfg:          j.Cfg,
+		ScheduleTime: time.Now().UnixMilli(),
+	})
+}
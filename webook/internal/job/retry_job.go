@@ -0,0 +1,60 @@
+package job
+
+import (
+	"context"
+	"github.com/ecodeclub/ekit/retry"
+	"github.com/wsqigo/basic-go/webook/pkg/logger"
+	"time"
+)
+
+// RetryJob 运行失败的时候按照指数退避重试
+type RetryJob struct {
+	job Job
+	l   logger.LoggerV1
+	// 第一次重试的间隔，之后每次翻倍，直到 maxInterval
+	initialInterval time.Duration
+	maxInterval     time.Duration
+	maxRetries      int32
+}
+
+func NewRetryJob(job Job, l logger.LoggerV1,
+	initialInterval, maxInterval time.Duration, maxRetries int32) *RetryJob {
+	return &RetryJob{
+		job:             job,
+		l:               l,
+		initialInterval: initialInterval,
+		maxInterval:     maxInterval,
+		maxRetries:      maxRetries,
+	}
+}
+
+func (r *RetryJob) Name() string {
+	return r.job.Name()
+}
+
+func (r *RetryJob) Run(ctx context.Context) error {
+	// 重试策略是有状态的，每一次运行都要重新创建
+	strategy, err := retry.NewExponentialBackoffRetryStrategy(r.initialInterval,
+		r.maxInterval, r.maxRetries)
+	if err != nil {
+		return err
+	}
+	for {
+		err = r.job.Run(ctx)
+		if err == nil {
+			return nil
+		}
+		interval, ok := strategy.Next()
+		if !ok {
+			return err
+		}
+		r.l.Warn("运行失败，准备重试",
+			logger.String("name", r.job.Name()),
+			logger.Error(err))
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
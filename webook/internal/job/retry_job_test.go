@@ -0,0 +1,90 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/wsqigo/basic-go/webook/pkg/logger"
+	"testing"
+	"time"
+)
+
+// mockJob 按照顺序返回 errs 里面的错误
+type mockJob struct {
+	errs []error
+	cnt  int
+}
+
+func (m *mockJob) Name() string {
+	return "mock"
+}
+
+func (m *mockJob) Run(ctx context.Context) error {
+	err := m.errs[m.cnt]
+	m.cnt++
+	return err
+}
+
+func TestRetryJob_Run(t *testing.T) {
+	testCases := []struct {
+		name string
+		errs []error
+
+		wantErr error
+		wantCnt int
+	}{
+		{
+			name:    "一次成功",
+			errs:    []error{nil},
+			wantCnt: 1,
+		},
+		{
+			name:    "重试之后成功",
+			errs:    []error{errors.New("失败"), errors.New("失败"), nil},
+			wantCnt: 3,
+		},
+		{
+			name: "重试次数耗尽",
+			errs: []error{errors.New("失败"), errors.New("失败"),
+				errors.New("失败"), errors.New("最后一次失败")},
+			wantErr: errors.New("最后一次失败"),
+			wantCnt: 4,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			j := &mockJob{errs: tc.errs}
+			rj := NewRetryJob(j, logger.NewNopLogger(),
+				time.Millisecond, 4*time.Millisecond, 3)
+			err := rj.Run(context.Background())
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantCnt, j.cnt)
+		})
+	}
+}
+
+func TestAlertJob_Run(t *testing.T) {
+	fail := errors.New("失败")
+	j := &mockJob{errs: []error{fail, fail, nil, fail, fail, fail, fail, fail, fail}}
+	var alerts []int
+	aj := NewAlertJob(j, 3, func(ctx context.Context, name string, cnt int, err error) {
+		alerts = append(alerts, cnt)
+	})
+	for i := 0; i < len(j.errs); i++ {
+		_ = aj.Run(context.Background())
+	}
+	// 中间成功了一次，重新计数，之后每连续失败三次告警一次
+	assert.Equal(t, []int{3, 6}, alerts)
+
+	// threshold 配成 0 也不能 panic，每次失败都告警
+	j = &mockJob{errs: []error{fail, nil, fail}}
+	alerts = nil
+	aj = NewAlertJob(j, 0, func(ctx context.Context, name string, cnt int, err error) {
+		alerts = append(alerts, cnt)
+	})
+	for i := 0; i < len(j.errs); i++ {
+		_ = aj.Run(context.Background())
+	}
+	assert.Equal(t, []int{1, 1}, alerts)
+}
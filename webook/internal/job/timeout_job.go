@@ -0,0 +1,27 @@
+package job
+
+import (
+	"context"
+	"time"
+)
+
+// TimeoutJob 控制每一次运行的超时时间
+// 被装饰的 Job 要正确处理 ctx 超时，不然超时了也停不下来
+type TimeoutJob struct {
+	job     Job
+	timeout time.Duration
+}
+
+func NewTimeoutJob(job Job, timeout time.Duration) *TimeoutJob {
+	return &TimeoutJob{job: job, timeout: timeout}
+}
+
+func (t *TimeoutJob) Name() string {
+	return t.job.Name()
+}
+
+func (t *TimeoutJob) Run(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, t.timeout)
+	defer cancel()
+	return t.job.Run(ctx)
+}
@@ -0,0 +1,35 @@
+package job
+
+import (
+	"context"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TraceJob 每一次运行都创建一个 span
+type TraceJob struct {
+	job    Job
+	tracer trace.Tracer
+}
+
+func NewTraceJob(job Job, tracer trace.Tracer) *TraceJob {
+	return &TraceJob{job: job, tracer: tracer}
+}
+
+func (t *TraceJob) Name() string {
+	return t.job.Name()
+}
+
+func (t *TraceJob) Run(ctx context.Context) error {
+	name := t.job.Name()
+	ctx, span := t.tracer.Start(ctx, "cron_job:"+name)
+	defer span.End()
+	span.SetAttributes(attribute.String("job", name))
+	err := t.job.Run(ctx)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return err
+}
@@ -11,6 +11,8 @@ import (
 	"time"
 )
 
+var ErrArticleNotFound = dao.ErrRecordNotFound
+
 //go:generate mockgen -destination=./mocks/article.mock.go -package=repomocks -source=./article.go ArticleRepository
 type ArticleRepository interface {
 	Create(ctx context.Context, art domain.Article) (int64, error)
@@ -21,6 +23,7 @@ type ArticleRepository interface {
 	GetByID(ctx context.Context, id int64) (domain.Article, error)
 	GetPubByID(ctx context.Context, id int64) (domain.Article, error)
 	ListPub(ctx context.Context, start time.Time, offset int, limit int) ([]domain.Article, error)
+	ListPubByAuthor(ctx context.Context, uid int64, start time.Time, limit int) ([]domain.Article, error)
 }
 
 type CachedArticleRepository struct {
@@ -81,6 +84,18 @@ func (c *CachedArticleRepository) ListPub(ctx context.Context,
 		}), nil
 }
 
+func (c *CachedArticleRepository) ListPubByAuthor(ctx context.Context,
+	uid int64, start time.Time, limit int) ([]domain.Article, error) {
+	arts, err := c.dao.ListPubByAuthor(ctx, uid, start, limit)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map[dao.PublishedArticle, domain.Article](arts,
+		func(idx int, src dao.PublishedArticle) domain.Article {
+			return c.toDomain(dao.Article(src))
+		}), nil
+}
+
 func (c *CachedArticleRepository) GetByAuthor(ctx context.Context, uid int64, offset int, limit int) ([]domain.Article, error) {
 	// 首先第一步，判定要不要查询缓存
 	// 事实上，limit <= 100 都可以查询缓存
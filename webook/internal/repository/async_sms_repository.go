@@ -2,11 +2,13 @@ package repository
 
 import (
 	"context"
+	"github.com/ecodeclub/ekit/sqlx"
 	"github.com/wsqigo/basic-go/webook/internal/domain"
-	"gorm.io/gorm"
+	"github.com/wsqigo/basic-go/webook/internal/repository/dao"
+	"time"
 )
 
-var ErrWaitingSMSNotFound = gorm.ErrRecordNotFound
+var ErrWaitingSMSNotFound = dao.ErrWaitingSMSNotFound
 
 //go:generate mockgen -source=./async_sms_repository.go -package=repomocks -destination=mocks/async_sms_repository.mock.go AsyncSmsRepository
 type AsyncSmsRepository interface {
@@ -15,4 +17,53 @@ type AsyncSmsRepository interface {
 	Add(ctx context.Context, s domain.AsyncSms) error
 	PreemptWaitingSMS(ctx context.Context) (domain.AsyncSms, error)
 	ReportScheduleResult(ctx context.Context, id int64, res bool) error
+	// DeleteFinished 删除 before 之前就已经结束的记录，一次最多删除 limit 条
+	DeleteFinished(ctx context.Context, before time.Time, limit int) (int64, error)
+}
+
+type DBAsyncSmsRepository struct {
+	dao dao.AsyncSmsDAO
+}
+
+func NewDBAsyncSmsRepository(dao dao.AsyncSmsDAO) AsyncSmsRepository {
+	return &DBAsyncSmsRepository{dao: dao}
+}
+
+func (a *DBAsyncSmsRepository) Add(ctx context.Context, s domain.AsyncSms) error {
+	return a.dao.Insert(ctx, dao.AsyncSms{
+		Config: sqlx.JsonColumn[dao.SmsConfig]{
+			Val: dao.SmsConfig{
+				TplId:   s.TplId,
+				Args:    s.Args,
+				Numbers: s.Numbers,
+			},
+			Valid: true,
+		},
+		RetryMax: s.RetryMax,
+	})
+}
+
+func (a *DBAsyncSmsRepository) PreemptWaitingSMS(ctx context.Context) (domain.AsyncSms, error) {
+	as, err := a.dao.GetWaitingSMS(ctx)
+	if err != nil {
+		return domain.AsyncSms{}, err
+	}
+	return domain.AsyncSms{
+		Id:       as.Id,
+		TplId:    as.Config.Val.TplId,
+		Args:     as.Config.Val.Args,
+		Numbers:  as.Config.Val.Numbers,
+		RetryMax: as.RetryMax,
+	}, nil
+}
+
+func (a *DBAsyncSmsRepository) ReportScheduleResult(ctx context.Context, id int64, res bool) error {
+	if res {
+		return a.dao.MarkSuccess(ctx, id)
+	}
+	return a.dao.MarkFailed(ctx, id)
+}
+
+func (a *DBAsyncSmsRepository) DeleteFinished(ctx context.Context, before time.Time, limit int) (int64, error) {
+	return a.dao.DeleteFinishedBefore(ctx, before.UnixMilli(), limit)
 }
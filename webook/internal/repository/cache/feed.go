@@ -0,0 +1,110 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"github.com/redis/go-redis/v9"
+	"github.com/wsqigo/basic-go/webook/internal/domain"
+	"strconv"
+	"time"
+)
+
+//go:generate mockgen -destination=./mocks/feed.mock.go -package=cachemocks -source=./feed.go FeedCache
+type FeedCache interface {
+	// AddToInboxes 推模型，写到每一个粉丝的收件箱里面
+	AddToInboxes(ctx context.Context, uids []int64, aid int64, ctime time.Time) error
+	// Inbox 收件箱里面 maxTime 之前的数据，按照时间倒序
+	Inbox(ctx context.Context, uid int64, maxTime time.Time, limit int) ([]domain.FeedItem, error)
+	// MarkPullAuthor 标记作者的文章走拉模型
+	MarkPullAuthor(ctx context.Context, uid int64) error
+	// FilterPullAuthors 找出 uids 里面走拉模型的作者
+	FilterPullAuthors(ctx context.Context, uids []int64) ([]int64, error)
+}
+
+type FeedRedisCache struct {
+	client redis.Cmdable
+	// 收件箱最多保留多少条，再往前翻就没有了
+	inboxSize  int64
+	expiration time.Duration
+}
+
+func NewFeedRedisCache(client redis.Cmdable) FeedCache {
+	return &FeedRedisCache{
+		client:     client,
+		inboxSize:  1000,
+		expiration: 7 * 24 * time.Hour,
+	}
+}
+
+func (r *FeedRedisCache) AddToInboxes(ctx context.Context, uids []int64,
+	aid int64, ctime time.Time) error {
+	pipe := r.client.Pipeline()
+	member := redis.Z{
+		Score:  float64(ctime.UnixMilli()),
+		Member: strconv.FormatInt(aid, 10),
+	}
+	for _, uid := range uids {
+		key := r.inboxKey(uid)
+		pipe.ZAdd(ctx, key, member)
+		// 只保留最新的 inboxSize 条
+		pipe.ZRemRangeByRank(ctx, key, 0, -r.inboxSize-1)
+		pipe.Expire(ctx, key, r.expiration)
+	}
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func (r *FeedRedisCache) Inbox(ctx context.Context, uid int64,
+	maxTime time.Time, limit int) ([]domain.FeedItem, error) {
+	vals, err := r.client.ZRevRangeByScoreWithScores(ctx, r.inboxKey(uid), &redis.ZRangeBy{
+		// 不包含 maxTime 本身
+		Max:   fmt.Sprintf("(%d", maxTime.UnixMilli()),
+		Min:   "-inf",
+		Count: int64(limit),
+	}).Result()
+	if err != nil {
+		return nil, err
+	}
+	res := make([]domain.FeedItem, 0, len(vals))
+	for _, val := range vals {
+		aid, _ := strconv.ParseInt(val.Member.(string), 10, 64)
+		res = append(res, domain.FeedItem{
+			Article: domain.Article{Id: aid},
+			Ctime:   time.UnixMilli(int64(val.Score)),
+		})
+	}
+	return res, nil
+}
+
+func (r *FeedRedisCache) MarkPullAuthor(ctx context.Context, uid int64) error {
+	return r.client.SAdd(ctx, r.pullAuthorsKey(), uid).Err()
+}
+
+func (r *FeedRedisCache) FilterPullAuthors(ctx context.Context, uids []int64) ([]int64, error) {
+	if len(uids) == 0 {
+		return nil, nil
+	}
+	members := make([]any, 0, len(uids))
+	for _, uid := range uids {
+		members = append(members, uid)
+	}
+	exists, err := r.client.SMIsMember(ctx, r.pullAuthorsKey(), members...).Result()
+	if err != nil {
+		return nil, err
+	}
+	res := make([]int64, 0, len(uids))
+	for i, exist := range exists {
+		if exist {
+			res = append(res, uids[i])
+		}
+	}
+	return res, nil
+}
+
+func (r *FeedRedisCache) inboxKey(uid int64) string {
+	return fmt.Sprintf("feed:inbox:%d", uid)
+}
+
+func (r *FeedRedisCache) pullAuthorsKey() string {
+	return "feed:pull_authors"
+}
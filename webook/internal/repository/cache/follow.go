@@ -0,0 +1,97 @@
+package cache
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"github.com/redis/go-redis/v9"
+	"github.com/wsqigo/basic-go/webook/internal/domain"
+	"strconv"
+	"time"
+)
+
+var (
+	//go:embed lua/incr_cnt_if_exist.lua
+	luaIncrCntIfExist string
+)
+
+const (
+	fieldFollowerCnt = "follower_cnt"
+	fieldFolloweeCnt = "followee_cnt"
+)
+
+//go:generate mockgen -destination=./mocks/follow.mock.go -package=cachemocks -source=./follow.go FollowCache
+type FollowCache interface {
+	// StaticsInfo 缓存里面没有会返回 ErrKeyNotExist
+	StaticsInfo(ctx context.Context, uid int64) (domain.FollowStatics, error)
+	SetStaticsInfo(ctx context.Context, uid int64, statics domain.FollowStatics) error
+	// Follow follower 关注了 followee，两边的计数都要更新
+	Follow(ctx context.Context, follower, followee int64) error
+	CancelFollow(ctx context.Context, follower, followee int64) error
+}
+
+type FollowRedisCache struct {
+	client     redis.Cmdable
+	expiration time.Duration
+}
+
+func NewFollowRedisCache(client redis.Cmdable) FollowCache {
+	return &FollowRedisCache{
+		client:     client,
+		expiration: 15 * time.Minute,
+	}
+}
+
+func (r *FollowRedisCache) StaticsInfo(ctx context.Context, uid int64) (domain.FollowStatics, error) {
+	res, err := r.client.HGetAll(ctx, r.staticsKey(uid)).Result()
+	if err != nil {
+		return domain.FollowStatics{}, err
+	}
+	if len(res) == 0 {
+		return domain.FollowStatics{}, ErrKeyNotExist
+	}
+	// 理论上来说，这里不可能有 error
+	followers, _ := strconv.ParseInt(res[fieldFollowerCnt], 10, 64)
+	followees, _ := strconv.ParseInt(res[fieldFolloweeCnt], 10, 64)
+	return domain.FollowStatics{
+		Uid:       uid,
+		Followers: followers,
+		Followees: followees,
+	}, nil
+}
+
+func (r *FollowRedisCache) SetStaticsInfo(ctx context.Context, uid int64, statics domain.FollowStatics) error {
+	key := r.staticsKey(uid)
+	err := r.client.HMSet(ctx, key,
+		fieldFollowerCnt, statics.Followers,
+		fieldFolloweeCnt, statics.Followees).Err()
+	if err != nil {
+		return err
+	}
+	return r.client.Expire(ctx, key, r.expiration).Err()
+}
+
+func (r *FollowRedisCache) Follow(ctx context.Context, follower, followee int64) error {
+	return r.updateStaticsInfo(ctx, follower, followee, 1)
+}
+
+func (r *FollowRedisCache) CancelFollow(ctx context.Context, follower, followee int64) error {
+	return r.updateStaticsInfo(ctx, follower, followee, -1)
+}
+
+func (r *FollowRedisCache) updateStaticsInfo(ctx context.Context,
+	follower, followee int64, delta int64) error {
+	// follower 的关注数和 followee 的粉丝数
+	// 两个 key 可能不在同一个 slot 上，所以分开执行
+	err := r.client.Eval(ctx, luaIncrCntIfExist, []string{r.staticsKey(follower)},
+		fieldFolloweeCnt, delta).Err()
+	if err != nil {
+		return err
+	}
+	return r.client.Eval(ctx, luaIncrCntIfExist, []string{r.staticsKey(followee)},
+		fieldFollowerCnt, delta).Err()
+}
+
+func (r *FollowRedisCache) staticsKey(uid int64) string {
+	return fmt.Sprintf("follow:statics:%d", uid)
+}
@@ -27,6 +27,7 @@ const (
 	fieldReadCnt    = "read_cnt"
 	fieldLikeCnt    = "like_cnt"
 	fieldCollectCnt = "collect_cnt"
+	fieldCommentCnt = "comment_cnt"
 )
 
 type InteractiveCache interface {
@@ -35,6 +36,9 @@ type InteractiveCache interface {
 	IncrLikeCntIfPresent(ctx context.Context, biz string, bizId int64) error
 	DecrLikeCntIfPresent(ctx context.Context, biz string, bizId int64) error
 	IncrCollectCntIfPresent(ctx context.Context, biz string, id int64) error
+	DecrCollectCntIfPresent(ctx context.Context, biz string, id int64) error
+	// IncrCommentCntIfPresent delta 可以是负数，删除评论的时候会一次性删掉一批回复
+	IncrCommentCntIfPresent(ctx context.Context, biz string, id int64, delta int64) error
 	// Get 查询缓存中数据
 	Get(ctx context.Context, biz string, id int64) (domain.Interactive, error)
 	Set(ctx context.Context, biz string, bizId int64, res domain.Interactive) error
@@ -77,6 +81,7 @@ func (r *InteractiveRedisCache) Get(ctx context.Context, biz string, id int64) (
 	collectCnt, _ := strconv.ParseInt(res[fieldCollectCnt], 10, 64)
 	likeCnt, _ := strconv.ParseInt(res[fieldLikeCnt], 10, 64)
 	readCnt, _ := strconv.ParseInt(res[fieldReadCnt], 10, 64)
+	commentCnt, _ := strconv.ParseInt(res[fieldCommentCnt], 10, 64)
 
 	return domain.Interactive{
 		// 懒惰的写法
@@ -84,6 +89,7 @@ func (r *InteractiveRedisCache) Get(ctx context.Context, biz string, id int64) (
 		CollectCnt: collectCnt,
 		LikeCnt:    likeCnt,
 		ReadCnt:    readCnt,
+		CommentCnt: commentCnt,
 	}, err
 }
 
@@ -91,7 +97,8 @@ func (r *InteractiveRedisCache) Set(ctx context.Context, biz string, bizId int64
 	key := r.key(biz, bizId)
 	err := r.client.HMSet(ctx, key, fieldCollectCnt, res.CollectCnt,
 		fieldReadCnt, res.ReadCnt,
-		fieldLikeCnt, res.LikeCnt).Err()
+		fieldLikeCnt, res.LikeCnt,
+		fieldCommentCnt, res.CommentCnt).Err()
 	if err != nil {
 		return err
 	}
@@ -103,6 +110,16 @@ func (r *InteractiveRedisCache) IncrCollectCntIfPresent(ctx context.Context, biz
 	return r.client.Eval(ctx, luaIncrCnt, []string{key}, fieldCollectCnt, 1).Err()
 }
 
+func (r *InteractiveRedisCache) DecrCollectCntIfPresent(ctx context.Context, biz string, id int64) error {
+	key := r.key(biz, id)
+	return r.client.Eval(ctx, luaIncrCnt, []string{key}, fieldCollectCnt, -1).Err()
+}
+
+func (r *InteractiveRedisCache) IncrCommentCntIfPresent(ctx context.Context, biz string, id int64, delta int64) error {
+	key := r.key(biz, id)
+	return r.client.Eval(ctx, luaIncrCnt, []string{key}, fieldCommentCnt, delta).Err()
+}
+
 func (r *InteractiveRedisCache) IncrReadCntIfPresent(ctx context.Context,
 	biz string, bizId int64) error {
 	key := r.key(biz, bizId)
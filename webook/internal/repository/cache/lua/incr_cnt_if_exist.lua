@@ -0,0 +1,14 @@
+-- 关注数和粉丝数
+local key = KEYS[1]
+local cntKey = ARGV[1]
+local delta = tonumber(ARGV[2])
+
+local exist = redis.call("EXISTS", key)
+if exist == 1 then
+    redis.call("HINCRBY", key, cntKey, delta)
+    return 1
+else
+    -- 缓存里面没有，不要凭空造出来一个只有部分字段的 key
+    -- 下次查询的时候会从数据库里面加载完整的数据
+    return 0
+end
@@ -0,0 +1,18 @@
+local loadKey = KEYS[1]
+local timeKey = KEYS[2]
+local node = ARGV[1]
+-- 在这个时间之前上报的节点，认为已经下线了
+local expiredBefore = tonumber(ARGV[2])
+
+local expired = redis.call("ZRANGEBYSCORE", timeKey, "-inf", expiredBefore)
+if #expired > 0 then
+    redis.call("ZREM", loadKey, unpack(expired))
+    redis.call("ZREM", timeKey, unpack(expired))
+end
+
+local rank = redis.call("ZRANK", loadKey, node)
+if rank == false then
+    -- 还没有上报过，或者太久没有上报了
+    return -1
+end
+return rank
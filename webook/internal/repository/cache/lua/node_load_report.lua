@@ -0,0 +1,11 @@
+-- 节点负载，分数就是负载
+local loadKey = KEYS[1]
+-- 节点最近一次上报的时间，用来剔除已经下线的节点
+local timeKey = KEYS[2]
+local node = ARGV[1]
+local load = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+
+redis.call("ZADD", loadKey, load, node)
+redis.call("ZADD", timeKey, now, node)
+return 1
@@ -0,0 +1,100 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./feed.go
+//
+// Generated by this command:
+//
+//	mockgen -destination=./mocks/feed.mock.go -package=cachemocks -source=./feed.go FeedCache
+//
+
+// Package cachemocks is a generated GoMock package.
+package cachemocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	domain "github.com/wsqigo/basic-go/webook/internal/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockFeedCache is a mock of FeedCache interface.
+type MockFeedCache struct {
+	ctrl     *gomock.Controller
+	recorder *MockFeedCacheMockRecorder
+}
+
+// MockFeedCacheMockRecorder is the mock recorder for MockFeedCache.
+type MockFeedCacheMockRecorder struct {
+	mock *MockFeedCache
+}
+
+// NewMockFeedCache creates a new mock instance.
+func NewMockFeedCache(ctrl *gomock.Controller) *MockFeedCache {
+	mock := &MockFeedCache{ctrl: ctrl}
+	mock.recorder = &MockFeedCacheMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFeedCache) EXPECT() *MockFeedCacheMockRecorder {
+	return m.recorder
+}
+
+// AddToInboxes mocks base method.
+func (m *MockFeedCache) AddToInboxes(ctx context.Context, uids []int64, aid int64, ctime time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddToInboxes", ctx, uids, aid, ctime)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddToInboxes indicates an expected call of AddToInboxes.
+func (mr *MockFeedCacheMockRecorder) AddToInboxes(ctx, uids, aid, ctime any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddToInboxes", reflect.TypeOf((*MockFeedCache)(nil).AddToInboxes), ctx, uids, aid, ctime)
+}
+
+// FilterPullAuthors mocks base method.
+func (m *MockFeedCache) FilterPullAuthors(ctx context.Context, uids []int64) ([]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FilterPullAuthors", ctx, uids)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FilterPullAuthors indicates an expected call of FilterPullAuthors.
+func (mr *MockFeedCacheMockRecorder) FilterPullAuthors(ctx, uids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FilterPullAuthors", reflect.TypeOf((*MockFeedCache)(nil).FilterPullAuthors), ctx, uids)
+}
+
+// Inbox mocks base method.
+func (m *MockFeedCache) Inbox(ctx context.Context, uid int64, maxTime time.Time, limit int) ([]domain.FeedItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Inbox", ctx, uid, maxTime, limit)
+	ret0, _ := ret[0].([]domain.FeedItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Inbox indicates an expected call of Inbox.
+func (mr *MockFeedCacheMockRecorder) Inbox(ctx, uid, maxTime, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Inbox", reflect.TypeOf((*MockFeedCache)(nil).Inbox), ctx, uid, maxTime, limit)
+}
+
+// MarkPullAuthor mocks base method.
+func (m *MockFeedCache) MarkPullAuthor(ctx context.Context, uid int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkPullAuthor", ctx, uid)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkPullAuthor indicates an expected call of MarkPullAuthor.
+func (mr *MockFeedCacheMockRecorder) MarkPullAuthor(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkPullAuthor", reflect.TypeOf((*MockFeedCache)(nil).MarkPullAuthor), ctx, uid)
+}
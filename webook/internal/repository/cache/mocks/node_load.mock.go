@@ -0,0 +1,69 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./node_load.go
+//
+// Generated by this command:
+//
+//	mockgen -destination=./mocks/node_load.mock.go -package=cachemocks -source=./node_load.go NodeLoadCache
+//
+
+// Package cachemocks is a generated GoMock package.
+package cachemocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockNodeLoadCache is a mock of NodeLoadCache interface.
+type MockNodeLoadCache struct {
+	ctrl     *gomock.Controller
+	recorder *MockNodeLoadCacheMockRecorder
+}
+
+// MockNodeLoadCacheMockRecorder is the mock recorder for MockNodeLoadCache.
+type MockNodeLoadCacheMockRecorder struct {
+	mock *MockNodeLoadCache
+}
+
+// NewMockNodeLoadCache creates a new mock instance.
+func NewMockNodeLoadCache(ctrl *gomock.Controller) *MockNodeLoadCache {
+	mock := &MockNodeLoadCache{ctrl: ctrl}
+	mock.recorder = &MockNodeLoadCacheMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockNodeLoadCache) EXPECT() *MockNodeLoadCacheMockRecorder {
+	return m.recorder
+}
+
+// Rank mocks base method.
+func (m *MockNodeLoadCache) Rank(ctx context.Context, nodeId string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Rank", ctx, nodeId)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Rank indicates an expected call of Rank.
+func (mr *MockNodeLoadCacheMockRecorder) Rank(ctx, nodeId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rank", reflect.TypeOf((*MockNodeLoadCache)(nil).Rank), ctx, nodeId)
+}
+
+// Report mocks base method.
+func (m *MockNodeLoadCache) Report(ctx context.Context, nodeId string, load int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Report", ctx, nodeId, load)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Report indicates an expected call of Report.
+func (mr *MockNodeLoadCacheMockRecorder) Report(ctx, nodeId, load any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Report", reflect.TypeOf((*MockNodeLoadCache)(nil).Report), ctx, nodeId, load)
+}
@@ -0,0 +1,54 @@
+package cache
+
+import (
+	"context"
+	_ "embed"
+	"github.com/redis/go-redis/v9"
+	"time"
+)
+
+var (
+	//go:embed lua/node_load_report.lua
+	luaNodeLoadReport string
+	//go:embed lua/node_load_rank.lua
+	luaNodeLoadRank string
+)
+
+//go:generate mockgen -destination=./mocks/node_load.mock.go -package=cachemocks -source=./node_load.go NodeLoadCache
+type NodeLoadCache interface {
+	// Report 上报节点的负载
+	Report(ctx context.Context, nodeId string, load int64) error
+	// Rank 节点在所有存活节点里面按照负载从低到高的排名，从 0 开始
+	// 节点没有上报过，或者已经过期了，返回 -1
+	Rank(ctx context.Context, nodeId string) (int64, error)
+}
+
+type NodeLoadRedisCache struct {
+	client  redis.Cmdable
+	loadKey string
+	timeKey string
+	// 超过这个时间没有上报的节点，认为已经下线了
+	expiration time.Duration
+}
+
+func NewNodeLoadRedisCache(client redis.Cmdable) NodeLoadCache {
+	return &NodeLoadRedisCache{
+		client:     client,
+		loadKey:    "job:node_load",
+		timeKey:    "job:node_load_time",
+		expiration: time.Minute,
+	}
+}
+
+func (c *NodeLoadRedisCache) Report(ctx context.Context, nodeId string, load int64) error {
+	return c.client.Eval(ctx, luaNodeLoadReport,
+		[]string{c.loadKey, c.timeKey},
+		nodeId, load, time.Now().UnixMilli()).Err()
+}
+
+func (c *NodeLoadRedisCache) Rank(ctx context.Context, nodeId string) (int64, error) {
+	expiredBefore := time.Now().Add(-c.expiration).UnixMilli()
+	return c.client.Eval(ctx, luaNodeLoadRank,
+		[]string{c.loadKey, c.timeKey},
+		nodeId, expiredBefore).Int64()
+}
@@ -15,6 +15,7 @@ var ErrKeyNotExist = redis.Nil
 type UserCache interface {
 	Get(ctx context.Context, uid int64) (domain.User, error)
 	Set(ctx context.Context, du domain.User) error
+	Del(ctx context.Context, uid int64) error
 }
 
 type RedisUserCache struct {
@@ -48,6 +49,10 @@ func (c *RedisUserCache) Set(ctx context.Context, du domain.User) error {
 	return c.cmd.Set(ctx, key, data, c.expiration).Err()
 }
 
+func (c *RedisUserCache) Del(ctx context.Context, uid int64) error {
+	return c.cmd.Del(ctx, c.key(uid)).Err()
+}
+
 func (c *RedisUserCache) key(uid int64) string {
 	// user-info-
 	// user.info.
@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"context"
+	"github.com/ecodeclub/ekit/slice"
+	"github.com/wsqigo/basic-go/webook/internal/domain"
+	"github.com/wsqigo/basic-go/webook/internal/repository/cache"
+	"github.com/wsqigo/basic-go/webook/internal/repository/dao"
+	"github.com/wsqigo/basic-go/webook/pkg/logger"
+	"time"
+)
+
+var (
+	ErrCollectionNotFound     = dao.ErrCollectionNotFound
+	ErrCollectionItemNotFound = dao.ErrRecordNotFound
+)
+
+//go:generate mockgen -source=./collection.go -package=repomocks -destination=./mocks/collection.mock.go CollectionRepository
+type CollectionRepository interface {
+	Create(ctx context.Context, c domain.Collection) (int64, error)
+	Rename(ctx context.Context, uid int64, id int64, name string) error
+	Delete(ctx context.Context, uid int64, id int64) error
+	FindById(ctx context.Context, uid int64, id int64) (domain.Collection, error)
+	// FindByUid 会带上每个收藏夹里面的收藏数量
+	FindByUid(ctx context.Context, uid int64, offset int, limit int) ([]domain.Collection, error)
+	FindItems(ctx context.Context, uid int64, cid int64, offset int, limit int) ([]domain.CollectionItem, error)
+	MoveItem(ctx context.Context, uid int64, biz string, bizId int64, cid int64) error
+}
+
+type CachedCollectionRepository struct {
+	dao dao.CollectionDAO
+	// 删除收藏夹会扣减收藏数，所以要顺便更新一下缓存
+	intrCache cache.InteractiveCache
+	l         logger.LoggerV1
+}
+
+func NewCachedCollectionRepository(dao dao.CollectionDAO,
+	intrCache cache.InteractiveCache,
+	l logger.LoggerV1) CollectionRepository {
+	return &CachedCollectionRepository{
+		dao:       dao,
+		intrCache: intrCache,
+		l:         l,
+	}
+}
+
+func (c *CachedCollectionRepository) Create(ctx context.Context, col domain.Collection) (int64, error) {
+	return c.dao.Insert(ctx, dao.Collection{
+		Uid:  col.Uid,
+		Name: col.Name,
+	})
+}
+
+func (c *CachedCollectionRepository) Rename(ctx context.Context, uid int64, id int64, name string) error {
+	return c.dao.UpdateName(ctx, uid, id, name)
+}
+
+func (c *CachedCollectionRepository) Delete(ctx context.Context, uid int64, id int64) error {
+	items, err := c.dao.Delete(ctx, uid, id)
+	if err != nil {
+		return err
+	}
+	for _, item := range items {
+		er := c.intrCache.DecrCollectCntIfPresent(ctx, item.Biz, item.BizId)
+		if er != nil {
+			c.l.Error("更新收藏数缓存失败",
+				logger.String("biz", item.Biz),
+				logger.Int64("biz_id", item.BizId),
+				logger.Error(er))
+		}
+	}
+	return nil
+}
+
+func (c *CachedCollectionRepository) FindById(ctx context.Context, uid int64, id int64) (domain.Collection, error) {
+	col, err := c.dao.FindById(ctx, uid, id)
+	if err != nil {
+		return domain.Collection{}, err
+	}
+	return c.toDomain(col), nil
+}
+
+func (c *CachedCollectionRepository) FindByUid(ctx context.Context, uid int64,
+	offset int, limit int) ([]domain.Collection, error) {
+	cols, err := c.dao.FindByUid(ctx, uid, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	if len(cols) == 0 {
+		return []domain.Collection{}, nil
+	}
+	cids := slice.Map(cols, func(idx int, src dao.Collection) int64 {
+		return src.Id
+	})
+	cnts, err := c.dao.CountItems(ctx, uid, cids)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(cols, func(idx int, src dao.Collection) domain.Collection {
+		res := c.toDomain(src)
+		res.ItemCnt = cnts[src.Id]
+		return res
+	}), nil
+}
+
+func (c *CachedCollectionRepository) FindItems(ctx context.Context, uid int64,
+	cid int64, offset int, limit int) ([]domain.CollectionItem, error) {
+	items, err := c.dao.FindItems(ctx, uid, cid, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(items, func(idx int, src dao.UserCollectionBiz) domain.CollectionItem {
+		return domain.CollectionItem{
+			Cid:   src.Cid,
+			Uid:   src.Uid,
+			Biz:   src.Biz,
+			BizId: src.BizId,
+			Ctime: time.UnixMilli(src.Ctime),
+		}
+	}), nil
+}
+
+func (c *CachedCollectionRepository) MoveItem(ctx context.Context, uid int64,
+	biz string, bizId int64, cid int64) error {
+	return c.dao.MoveItem(ctx, uid, biz, bizId, cid)
+}
+
+func (c *CachedCollectionRepository) toDomain(col dao.Collection) domain.Collection {
+	return domain.Collection{
+		Id:    col.Id,
+		Uid:   col.Uid,
+		Name:  col.Name,
+		Ctime: time.UnixMilli(col.Ctime),
+		Utime: time.UnixMilli(col.Utime),
+	}
+}
@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"github.com/ecodeclub/ekit/slice"
+	"github.com/wsqigo/basic-go/webook/internal/domain"
+	"github.com/wsqigo/basic-go/webook/internal/repository/cache"
+	"github.com/wsqigo/basic-go/webook/internal/repository/dao"
+	"github.com/wsqigo/basic-go/webook/pkg/logger"
+	"time"
+)
+
+var ErrCommentNotFound = dao.ErrRecordNotFound
+
+//go:generate mockgen -source=./comment.go -package=repomocks -destination=./mocks/comment.mock.go CommentRepository
+type CommentRepository interface {
+	CreateComment(ctx context.Context, c domain.Comment) (int64, error)
+	// FindByBiz 根评论，按照 id 倒序
+	FindByBiz(ctx context.Context, biz string, bizId int64, minId int64, limit int) ([]domain.Comment, error)
+	// GetMoreReplies 某个根评论下的回复，按照 id 升序
+	GetMoreReplies(ctx context.Context, rid int64, maxId int64, limit int) ([]domain.Comment, error)
+	FindById(ctx context.Context, id int64) (domain.Comment, error)
+	DeleteComment(ctx context.Context, c domain.Comment) error
+}
+
+type CachedCommentRepository struct {
+	dao dao.CommentDAO
+	// 评论数是存在 Interactive 里面的，所以要顺便更新一下它的缓存
+	intrCache cache.InteractiveCache
+	l         logger.LoggerV1
+}
+
+func NewCachedCommentRepository(dao dao.CommentDAO,
+	intrCache cache.InteractiveCache,
+	l logger.LoggerV1) CommentRepository {
+	return &CachedCommentRepository{
+		dao:       dao,
+		intrCache: intrCache,
+		l:         l,
+	}
+}
+
+func (c *CachedCommentRepository) CreateComment(ctx context.Context, cmt domain.Comment) (int64, error) {
+	id, err := c.dao.Insert(ctx, c.toEntity(cmt))
+	if err != nil {
+		return 0, err
+	}
+	// 评论数不准确问题不大
+	er := c.intrCache.IncrCommentCntIfPresent(ctx, cmt.Biz, cmt.BizId, 1)
+	if er != nil {
+		c.l.Error("更新评论数缓存失败",
+			logger.String("biz", cmt.Biz),
+			logger.Int64("biz_id", cmt.BizId),
+			logger.Error(er))
+	}
+	return id, nil
+}
+
+func (c *CachedCommentRepository) FindByBiz(ctx context.Context, biz string,
+	bizId int64, minId int64, limit int) ([]domain.Comment, error) {
+	cmts, err := c.dao.FindByBiz(ctx, biz, bizId, minId, limit)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(cmts, func(idx int, src dao.Comment) domain.Comment {
+		return c.toDomain(src)
+	}), nil
+}
+
+func (c *CachedCommentRepository) GetMoreReplies(ctx context.Context,
+	rid int64, maxId int64, limit int) ([]domain.Comment, error) {
+	cmts, err := c.dao.FindRepliesByRid(ctx, rid, maxId, limit)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(cmts, func(idx int, src dao.Comment) domain.Comment {
+		return c.toDomain(src)
+	}), nil
+}
+
+func (c *CachedCommentRepository) FindById(ctx context.Context, id int64) (domain.Comment, error) {
+	cmt, err := c.dao.FindById(ctx, id)
+	if err != nil {
+		return domain.Comment{}, err
+	}
+	return c.toDomain(cmt), nil
+}
+
+func (c *CachedCommentRepository) DeleteComment(ctx context.Context, cmt domain.Comment) error {
+	cnt, err := c.dao.Delete(ctx, c.toEntity(cmt))
+	if err != nil || cnt == 0 {
+		return err
+	}
+	er := c.intrCache.IncrCommentCntIfPresent(ctx, cmt.Biz, cmt.BizId, -cnt)
+	if er != nil {
+		c.l.Error("更新评论数缓存失败",
+			logger.String("biz", cmt.Biz),
+			logger.Int64("biz_id", cmt.BizId),
+			logger.Error(er))
+	}
+	return nil
+}
+
+func (c *CachedCommentRepository) toEntity(cmt domain.Comment) dao.Comment {
+	res := dao.Comment{
+		Id:      cmt.Id,
+		Uid:     cmt.Commentator.Id,
+		Biz:     cmt.Biz,
+		BizId:   cmt.BizId,
+		Content: cmt.Content,
+	}
+	if cmt.RootComment != nil {
+		res.RootId = sql.NullInt64{
+			Int64: cmt.RootComment.Id,
+			Valid: true,
+		}
+	}
+	if cmt.ParentComment != nil {
+		res.PID = sql.NullInt64{
+			Int64: cmt.ParentComment.Id,
+			Valid: true,
+		}
+	}
+	return res
+}
+
+func (c *CachedCommentRepository) toDomain(cmt dao.Comment) domain.Comment {
+	res := domain.Comment{
+		Id: cmt.Id,
+		Commentator: domain.User{
+			Id: cmt.Uid,
+		},
+		Biz:     cmt.Biz,
+		BizId:   cmt.BizId,
+		Content: cmt.Content,
+		Ctime:   time.UnixMilli(cmt.Ctime),
+		Utime:   time.UnixMilli(cmt.Utime),
+	}
+	if cmt.RootId.Valid {
+		res.RootComment = &domain.Comment{
+			Id: cmt.RootId.Int64,
+		}
+	}
+	if cmt.PID.Valid {
+		res.ParentComment = &domain.Comment{
+			Id: cmt.PID.Int64,
+		}
+	}
+	return res
+}
@@ -19,6 +19,8 @@ type ArticleDAO interface {
 	GetById(ctx context.Context, id int64) (Article, error)
 	GetPubById(ctx context.Context, id int64) (PublishedArticle, error)
 	ListPub(ctx context.Context, start time.Time, offset int, limit int) ([]PublishedArticle, error)
+	// ListPubByAuthor 某个作者在 start 之前发表的文章，按照更新时间倒序
+	ListPubByAuthor(ctx context.Context, uid int64, start time.Time, limit int) ([]PublishedArticle, error)
 }
 
 type ArticleGORMDAO struct {
@@ -37,11 +39,26 @@ func (a *ArticleGORMDAO) ListPub(ctx context.Context, start time.Time,
 	const ArticleStatusPublished = 2
 	err := a.db.WithContext(ctx).
 		Where("utime < ? AND status = ?", start.UnixMilli(), ArticleStatusPublished).
+		// 不排序的话，分页遍历的时候可能会漏掉或者重复
+		Order("utime DESC").
 		Offset(offset).Limit(limit).
 		Find(&res).Error
 	return res, err
 }
 
+func (a *ArticleGORMDAO) ListPubByAuthor(ctx context.Context, uid int64,
+	start time.Time, limit int) ([]PublishedArticle, error) {
+	var res []PublishedArticle
+	const ArticleStatusPublished = 2
+	err := a.db.WithContext(ctx).
+		Where("author_id = ? AND utime < ? AND status = ?",
+			uid, start.UnixMilli(), ArticleStatusPublished).
+		Order("utime DESC").
+		Limit(limit).
+		Find(&res).Error
+	return res, err
+}
+
 func (a *ArticleGORMDAO) GetPubById(ctx context.Context, id int64) (PublishedArticle, error) {
 	var art PublishedArticle
 	err := a.db.WithContext(ctx).
@@ -82,12 +99,16 @@ func (a *ArticleGORMDAO) SyncStatus(ctx context.Context, uid int64, id int64, st
 		if res.RowsAffected == 0 {
 			return errors.New("更新失败, ID不对或者作者不对")
 		}
-		return tx.Model(&PublishedArticle{}).
+		err := tx.Model(&PublishedArticle{}).
 			Where("id = ?", id).
 			Updates(map[string]any{
 				"utime":  now,
 				"status": status,
 			}).Error
+		if err != nil {
+			return err
+		}
+		return insertArticleOutbox(txOutbox(tx), id, uid, status)
 	})
 }
 
@@ -126,12 +147,36 @@ func (a *ArticleGORMDAO) Sync(ctx context.Context, art Article) (int64, error) {
 				"status":  pubArt.Status,
 			}),
 		}).Create(&pubArt).Error
-		return err
+		if err != nil {
+			return err
+		}
+		return insertArticleOutbox(txOutbox(tx), id, art.AuthorId, domain.ArticleStatus(art.Status))
 	})
 
 	return id, err
 }
 
+// insertArticleOutbox 文章状态变化之后要通知下游，比如说推送 feed，更新搜索索引
+// insert 负责真正写入，在事务里面就是 insertOutbox(tx, evt)
+func insertArticleOutbox(insert func(evt OutboxEvent) error,
+	id int64, uid int64, status domain.ArticleStatus) error {
+	var typ string
+	switch status {
+	case domain.ArticleStatusPublished:
+		typ = domain.OutboxTypeArticlePublished
+	case domain.ArticleStatusPrivate:
+		typ = domain.OutboxTypeArticleWithdrawn
+	default:
+		return nil
+	}
+	return insert(OutboxEvent{
+		Biz:   domain.OutboxBizArticle,
+		BizId: id,
+		Type:  typ,
+		Uid:   uid,
+	})
+}
+
 func (a *ArticleGORMDAO) SyncV1(ctx context.Context, art Article) (int64, error) {
 	tx := a.db.WithContext(ctx).Begin()
 	if tx.Error != nil {
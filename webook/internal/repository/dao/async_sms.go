@@ -15,7 +15,10 @@ type AsyncSmsDAO interface {
 	Insert(ctx context.Context, s AsyncSms) error
 	GetWaitingSMS(ctx context.Context) (AsyncSms, error)
 	MarkSuccess(ctx context.Context, id int64) error
+	// MarkFailed 重试次数用完了才会变成失败，不然就等下一次重试
 	MarkFailed(ctx context.Context, id int64) error
+	// DeleteFinishedBefore 删除 utime 之前就已经结束的，返回删除的数量
+	DeleteFinishedBefore(ctx context.Context, utime int64, limit int) (int64, error)
 }
 
 const (
@@ -31,7 +34,11 @@ type GORMAsyncSmsDAO struct {
 }
 
 func (g *GORMAsyncSmsDAO) Insert(ctx context.Context, s AsyncSms) error {
-	return g.db.Create(&s).Error
+	now := time.Now().UnixMilli()
+	s.Status = asyncStatusWaiting
+	s.Ctime = now
+	s.Utime = now
+	return g.db.WithContext(ctx).Create(&s).Error
 }
 
 func (g *GORMAsyncSmsDAO) GetWaitingSMS(ctx context.Context) (AsyncSms, error) {
@@ -44,7 +51,8 @@ func (g *GORMAsyncSmsDAO) GetWaitingSMS(ctx context.Context) (AsyncSms, error) {
 		now := time.Now().UnixMilli()
 		endTime := now - time.Minute.Milliseconds()
 		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
-			Where("utime < ? and status = ?", endTime, asyncStatusWaiting).
+			// 重试次数用完了，但是没来得及标记失败的，比如说发送的时候节点崩溃了，等清理
+			Where("utime < ? and status = ? and retry_cnt < retry_max", endTime, asyncStatusWaiting).
 			First(&s).Error
 		// SELECT xx FROM xxx WHERE xx FOR UPDATE，锁住了
 		if err != nil {
@@ -66,16 +74,41 @@ func (g *GORMAsyncSmsDAO) GetWaitingSMS(ctx context.Context) (AsyncSms, error) {
 }
 
 func (g *GORMAsyncSmsDAO) MarkSuccess(ctx context.Context, id int64) error {
-	//TODO implement me
-	panic("implement me")
+	return g.db.WithContext(ctx).Model(&AsyncSms{}).
+		Where("id = ?", id).
+		Updates(map[string]any{
+			"status": asyncStatusSuccess,
+			"utime":  time.Now().UnixMilli(),
+		}).Error
 }
 
 func (g *GORMAsyncSmsDAO) MarkFailed(ctx context.Context, id int64) error {
-	//TODO implement me
-	panic("implement me")
+	// 还能重试的不用动，抢占的时候已经更新了 utime，一分钟之后会被再次抢占
+	return g.db.WithContext(ctx).Model(&AsyncSms{}).
+		Where("id = ? and retry_cnt >= retry_max", id).
+		Updates(map[string]any{
+			"status": asyncStatusFailed,
+			"utime":  time.Now().UnixMilli(),
+		}).Error
 }
 
-func NewGORMAsyncSmsDAO(db *gorm.DB) *GORMAsyncSmsDAO {
+func (g *GORMAsyncSmsDAO) DeleteFinishedBefore(ctx context.Context, utime int64, limit int) (int64, error) {
+	db := g.db.WithContext(ctx)
+	// MySQL 的 DELETE 不支持在子查询里面用 LIMIT，所以先查 ID
+	var ids []int64
+	err := db.Model(&AsyncSms{}).
+		Where("utime < ? and (status in ? or retry_cnt >= retry_max)",
+			utime, []int{asyncStatusFailed, asyncStatusSuccess}).
+		Limit(limit).
+		Pluck("id", &ids).Error
+	if err != nil || len(ids) == 0 {
+		return 0, err
+	}
+	res := db.Where("id IN ?", ids).Delete(&AsyncSms{})
+	return res.RowsAffected, res.Error
+}
+
+func NewGORMAsyncSmsDAO(db *gorm.DB) AsyncSmsDAO {
 	return &GORMAsyncSmsDAO{
 		db: db,
 	}
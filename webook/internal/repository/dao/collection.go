@@ -0,0 +1,179 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"gorm.io/gorm"
+	"time"
+)
+
+var ErrCollectionNotFound = errors.New("收藏夹不存在或者不属于该用户")
+
+//go:generate mockgen -source=./collection.go -package=daomocks -destination=./mocks/collection.mock.go CollectionDAO
+type CollectionDAO interface {
+	Insert(ctx context.Context, c Collection) (int64, error)
+	UpdateName(ctx context.Context, uid int64, id int64, name string) error
+	// Delete 删除收藏夹，连同里面的收藏一起删掉，并且扣减对应的收藏数
+	// 返回被删掉的收藏
+	Delete(ctx context.Context, uid int64, id int64) ([]UserCollectionBiz, error)
+	FindById(ctx context.Context, uid int64, id int64) (Collection, error)
+	FindByUid(ctx context.Context, uid int64, offset int, limit int) ([]Collection, error)
+	// CountItems 每个收藏夹里面有多少收藏
+	CountItems(ctx context.Context, uid int64, cids []int64) (map[int64]int64, error)
+	FindItems(ctx context.Context, uid int64, cid int64, offset int, limit int) ([]UserCollectionBiz, error)
+	// MoveItem 把收藏移动到另外一个收藏夹
+	MoveItem(ctx context.Context, uid int64, biz string, bizId int64, cid int64) error
+}
+
+type GORMCollectionDAO struct {
+	db *gorm.DB
+}
+
+func NewGORMCollectionDAO(db *gorm.DB) CollectionDAO {
+	return &GORMCollectionDAO{
+		db: db,
+	}
+}
+
+func (dao *GORMCollectionDAO) Insert(ctx context.Context, c Collection) (int64, error) {
+	now := time.Now().UnixMilli()
+	c.Ctime = now
+	c.Utime = now
+	err := dao.db.WithContext(ctx).Create(&c).Error
+	return c.Id, err
+}
+
+func (dao *GORMCollectionDAO) UpdateName(ctx context.Context, uid int64, id int64, name string) error {
+	res := dao.db.WithContext(ctx).Model(&Collection{}).
+		Where("id = ? AND uid = ?", id, uid).
+		Updates(map[string]any{
+			"name":  name,
+			"utime": time.Now().UnixMilli(),
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrCollectionNotFound
+	}
+	return nil
+}
+
+func (dao *GORMCollectionDAO) Delete(ctx context.Context, uid int64, id int64) ([]UserCollectionBiz, error) {
+	now := time.Now().UnixMilli()
+	var items []UserCollectionBiz
+	err := dao.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Where("id = ? AND uid = ?", id, uid).Delete(&Collection{})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrCollectionNotFound
+		}
+		err := tx.Where("uid = ? AND cid = ?", uid, id).Find(&items).Error
+		if err != nil {
+			return err
+		}
+		if len(items) == 0 {
+			return nil
+		}
+		err = tx.Where("uid = ? AND cid = ?", uid, id).
+			Delete(&UserCollectionBiz{}).Error
+		if err != nil {
+			return err
+		}
+		// 一个收藏夹里面的东西不会太多，逐个扣减就可以
+		for _, item := range items {
+			err = tx.Model(&Interactive{}).
+				Where("biz = ? AND biz_id = ?", item.Biz, item.BizId).
+				Updates(map[string]any{
+					"collect_cnt": gorm.Expr("collect_cnt - 1"),
+					"utime":       now,
+				}).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return items, err
+}
+
+func (dao *GORMCollectionDAO) FindById(ctx context.Context, uid int64, id int64) (Collection, error) {
+	var res Collection
+	err := dao.db.WithContext(ctx).
+		Where("id = ? AND uid = ?", id, uid).First(&res).Error
+	if err == gorm.ErrRecordNotFound {
+		return res, ErrCollectionNotFound
+	}
+	return res, err
+}
+
+func (dao *GORMCollectionDAO) FindByUid(ctx context.Context, uid int64,
+	offset int, limit int) ([]Collection, error) {
+	var res []Collection
+	err := dao.db.WithContext(ctx).
+		Where("uid = ?", uid).
+		Order("id DESC").
+		Offset(offset).Limit(limit).
+		Find(&res).Error
+	return res, err
+}
+
+func (dao *GORMCollectionDAO) CountItems(ctx context.Context, uid int64, cids []int64) (map[int64]int64, error) {
+	type cnt struct {
+		Cid int64
+		Cnt int64
+	}
+	var cnts []cnt
+	err := dao.db.WithContext(ctx).Model(&UserCollectionBiz{}).
+		Select("cid, COUNT(*) AS cnt").
+		Where("uid = ? AND cid IN ?", uid, cids).
+		Group("cid").
+		Scan(&cnts).Error
+	if err != nil {
+		return nil, err
+	}
+	res := make(map[int64]int64, len(cnts))
+	for _, c := range cnts {
+		res[c.Cid] = c.Cnt
+	}
+	return res, nil
+}
+
+func (dao *GORMCollectionDAO) FindItems(ctx context.Context, uid int64,
+	cid int64, offset int, limit int) ([]UserCollectionBiz, error) {
+	var res []UserCollectionBiz
+	err := dao.db.WithContext(ctx).
+		Where("uid = ? AND cid = ?", uid, cid).
+		Order("utime DESC").
+		Offset(offset).Limit(limit).
+		Find(&res).Error
+	return res, err
+}
+
+func (dao *GORMCollectionDAO) MoveItem(ctx context.Context, uid int64,
+	biz string, bizId int64, cid int64) error {
+	res := dao.db.WithContext(ctx).Model(&UserCollectionBiz{}).
+		Where("uid = ? AND biz = ? AND biz_id = ?", uid, biz, bizId).
+		Updates(map[string]any{
+			"cid":   cid,
+			"utime": time.Now().UnixMilli(),
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrRecordNotFound
+	}
+	return nil
+}
+
+// Collection 收藏夹
+type Collection struct {
+	Id    int64  `gorm:"primaryKey,autoIncrement"`
+	Uid   int64  `gorm:"index"`
+	Name  string `gorm:"type:varchar(256)"`
+	Ctime int64
+	Utime int64
+}
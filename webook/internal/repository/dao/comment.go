@@ -0,0 +1,157 @@
+package dao
+
+import (
+	"context"
+	"database/sql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+//go:generate mockgen -source=./comment.go -package=daomocks -destination=./mocks/comment.mock.go CommentDAO
+type CommentDAO interface {
+	// Insert 插入评论，同时会更新 Interactive 里面的评论数
+	Insert(ctx context.Context, c Comment) (int64, error)
+	// FindByBiz 只查找根评论，按照 id 倒序，minId 为 0 代表第一页
+	FindByBiz(ctx context.Context, biz string, bizId int64, minId int64, limit int) ([]Comment, error)
+	// FindRepliesByRid 查找某个根评论下的回复，按照 id 升序
+	FindRepliesByRid(ctx context.Context, rid int64, maxId int64, limit int) ([]Comment, error)
+	FindById(ctx context.Context, id int64) (Comment, error)
+	// Delete 删除评论以及它下面所有的回复，同时扣减评论数
+	// 返回实际删除的评论数量
+	Delete(ctx context.Context, c Comment) (int64, error)
+}
+
+type GORMCommentDAO struct {
+	db *gorm.DB
+}
+
+func NewGORMCommentDAO(db *gorm.DB) CommentDAO {
+	return &GORMCommentDAO{
+		db: db,
+	}
+}
+
+func (dao *GORMCommentDAO) Insert(ctx context.Context, c Comment) (int64, error) {
+	now := time.Now().UnixMilli()
+	c.Ctime = now
+	c.Utime = now
+	err := dao.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Create(&c).Error
+		if err != nil {
+			return err
+		}
+		return tx.Clauses(clause.OnConflict{
+			DoUpdates: clause.Assignments(map[string]any{
+				"comment_cnt": gorm.Expr("comment_cnt + 1"),
+				"utime":       now,
+			}),
+		}).Create(&Interactive{
+			Biz:        c.Biz,
+			BizId:      c.BizId,
+			CommentCnt: 1,
+			Ctime:      now,
+			Utime:      now,
+		}).Error
+	})
+	return c.Id, err
+}
+
+func (dao *GORMCommentDAO) FindByBiz(ctx context.Context, biz string,
+	bizId int64, minId int64, limit int) ([]Comment, error) {
+	var res []Comment
+	db := dao.db.WithContext(ctx).
+		Where("biz = ? AND biz_id = ? AND root_id IS NULL", biz, bizId)
+	if minId > 0 {
+		db = db.Where("id < ?", minId)
+	}
+	err := db.Order("id DESC").Limit(limit).Find(&res).Error
+	return res, err
+}
+
+func (dao *GORMCommentDAO) FindRepliesByRid(ctx context.Context,
+	rid int64, maxId int64, limit int) ([]Comment, error) {
+	var res []Comment
+	err := dao.db.WithContext(ctx).
+		Where("root_id = ? AND id > ?", rid, maxId).
+		Order("id ASC").
+		Limit(limit).Find(&res).Error
+	return res, err
+}
+
+func (dao *GORMCommentDAO) FindById(ctx context.Context, id int64) (Comment, error) {
+	var res Comment
+	err := dao.db.WithContext(ctx).
+		Where("id = ?", id).First(&res).Error
+	return res, err
+}
+
+func (dao *GORMCommentDAO) Delete(ctx context.Context, c Comment) (int64, error) {
+	now := time.Now().UnixMilli()
+	var cnt int64
+	err := dao.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		ids, err := dao.findSubTree(tx, c)
+		if err != nil {
+			return err
+		}
+		res := tx.Where("id IN ?", ids).Delete(&Comment{})
+		if res.Error != nil {
+			return res.Error
+		}
+		cnt = res.RowsAffected
+		if cnt == 0 {
+			// 已经被别人删掉了
+			return nil
+		}
+		return tx.Model(&Interactive{}).
+			Where("biz = ? AND biz_id = ?", c.Biz, c.BizId).
+			Updates(map[string]any{
+				"comment_cnt": gorm.Expr("comment_cnt - ?", cnt),
+				"utime":       now,
+			}).Error
+	})
+	return cnt, err
+}
+
+// findSubTree 找出 c 和它下面所有的回复的 id
+func (dao *GORMCommentDAO) findSubTree(tx *gorm.DB, c Comment) ([]int64, error) {
+	ids := []int64{c.Id}
+	if !c.RootId.Valid {
+		// 根评论，下面所有的回复 root_id 都是它
+		var children []int64
+		err := tx.Model(&Comment{}).
+			Where("root_id = ?", c.Id).
+			Pluck("id", &children).Error
+		return append(ids, children...), err
+	}
+	// 回复的回复，只能一层层往下找
+	parents := []int64{c.Id}
+	for len(parents) > 0 {
+		var children []int64
+		err := tx.Model(&Comment{}).
+			Where("pid IN ?", parents).
+			Pluck("id", &children).Error
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, children...)
+		parents = children
+	}
+	return ids, nil
+}
+
+type Comment struct {
+	Id int64 `gorm:"primaryKey,autoIncrement"`
+	// 发表评论的人
+	Uid int64 `gorm:"index"`
+	// 被评论的东西，我要根据它来查询
+	Biz   string `gorm:"type:varchar(128);index:biz_type_id"`
+	BizId int64  `gorm:"index:biz_type_id"`
+	// 根评论的 ID，为 NULL 说明自己就是根评论
+	RootId sql.NullInt64 `gorm:"index"`
+	// 父评论的 ID，为 NULL 说明自己就是根评论
+	PID     sql.NullInt64 `gorm:"column:pid;index"`
+	Content string        `gorm:"type:text"`
+	Ctime   int64
+	Utime   int64
+}
@@ -0,0 +1,153 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"github.com/go-sql-driver/mysql"
+	"gorm.io/gorm"
+	"time"
+)
+
+var ErrFollowRelationExists = errors.New("已经关注过了")
+
+const (
+	FollowRelationStatusUnknown uint8 = iota
+	FollowRelationStatusActive
+	// FollowRelationStatusInactive 取消关注了
+	FollowRelationStatusInactive
+)
+
+//go:generate mockgen -source=./follow.go -package=daomocks -destination=./mocks/follow.mock.go FollowRelationDAO
+type FollowRelationDAO interface {
+	// CreateFollowRelation 已经关注过了会返回 ErrFollowRelationExists
+	CreateFollowRelation(ctx context.Context, c FollowRelation) error
+	// CancelFollowRelation 本来就没有关注会返回 ErrRecordNotFound
+	CancelFollowRelation(ctx context.Context, follower, followee int64) error
+	// FollowRelationList 查询 follower 关注了哪些人，maxId 为 0 表示从头开始
+	FollowRelationList(ctx context.Context, follower, maxId int64, limit int) ([]FollowRelation, error)
+	// FollowerRelationList 查询 followee 有哪些粉丝
+	FollowerRelationList(ctx context.Context, followee, maxId int64, limit int) ([]FollowRelation, error)
+	FollowRelationDetail(ctx context.Context, follower, followee int64) (FollowRelation, error)
+	CntFollower(ctx context.Context, uid int64) (int64, error)
+	CntFollowee(ctx context.Context, uid int64) (int64, error)
+}
+
+type GORMFollowRelationDAO struct {
+	db *gorm.DB
+}
+
+func NewGORMFollowRelationDAO(db *gorm.DB) FollowRelationDAO {
+	return &GORMFollowRelationDAO{
+		db: db,
+	}
+}
+
+func (g *GORMFollowRelationDAO) CreateFollowRelation(ctx context.Context, c FollowRelation) error {
+	now := time.Now().UnixMilli()
+	db := g.db.WithContext(ctx)
+	// 先尝试把取消了的关注恢复过来
+	res := db.Model(&FollowRelation{}).
+		Where("follower = ? AND followee = ? AND status = ?",
+			c.Follower, c.Followee, FollowRelationStatusInactive).
+		Updates(map[string]any{
+			"status": FollowRelationStatusActive,
+			"utime":  now,
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected > 0 {
+		return nil
+	}
+	c.Status = FollowRelationStatusActive
+	c.Ctime = now
+	c.Utime = now
+	err := db.Create(&c).Error
+	if me, ok := err.(*mysql.MySQLError); ok {
+		const duplicateErr uint16 = 1062
+		if me.Number == duplicateErr {
+			// 唯一索引冲突，也就是已经关注了
+			return ErrFollowRelationExists
+		}
+	}
+	return err
+}
+
+func (g *GORMFollowRelationDAO) CancelFollowRelation(ctx context.Context, follower, followee int64) error {
+	res := g.db.WithContext(ctx).Model(&FollowRelation{}).
+		Where("follower = ? AND followee = ? AND status = ?",
+			follower, followee, FollowRelationStatusActive).
+		Updates(map[string]any{
+			"status": FollowRelationStatusInactive,
+			"utime":  time.Now().UnixMilli(),
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrRecordNotFound
+	}
+	return nil
+}
+
+func (g *GORMFollowRelationDAO) FollowRelationList(ctx context.Context,
+	follower, maxId int64, limit int) ([]FollowRelation, error) {
+	var res []FollowRelation
+	db := g.db.WithContext(ctx).
+		Where("follower = ? AND status = ?", follower, FollowRelationStatusActive)
+	if maxId > 0 {
+		db = db.Where("id < ?", maxId)
+	}
+	err := db.Order("id DESC").Limit(limit).Find(&res).Error
+	return res, err
+}
+
+func (g *GORMFollowRelationDAO) FollowerRelationList(ctx context.Context,
+	followee, maxId int64, limit int) ([]FollowRelation, error) {
+	var res []FollowRelation
+	db := g.db.WithContext(ctx).
+		Where("followee = ? AND status = ?", followee, FollowRelationStatusActive)
+	if maxId > 0 {
+		db = db.Where("id < ?", maxId)
+	}
+	err := db.Order("id DESC").Limit(limit).Find(&res).Error
+	return res, err
+}
+
+func (g *GORMFollowRelationDAO) FollowRelationDetail(ctx context.Context,
+	follower, followee int64) (FollowRelation, error) {
+	var res FollowRelation
+	err := g.db.WithContext(ctx).
+		Where("follower = ? AND followee = ? AND status = ?",
+			follower, followee, FollowRelationStatusActive).
+		First(&res).Error
+	return res, err
+}
+
+func (g *GORMFollowRelationDAO) CntFollower(ctx context.Context, uid int64) (int64, error) {
+	var cnt int64
+	err := g.db.WithContext(ctx).Model(&FollowRelation{}).
+		Where("followee = ? AND status = ?", uid, FollowRelationStatusActive).
+		Count(&cnt).Error
+	return cnt, err
+}
+
+func (g *GORMFollowRelationDAO) CntFollowee(ctx context.Context, uid int64) (int64, error) {
+	var cnt int64
+	err := g.db.WithContext(ctx).Model(&FollowRelation{}).
+		Where("follower = ? AND status = ?", uid, FollowRelationStatusActive).
+		Count(&cnt).Error
+	return cnt, err
+}
+
+// FollowRelation 关注关系，取消关注只是改状态，不删除
+type FollowRelation struct {
+	Id int64 `gorm:"primaryKey,autoIncrement"`
+	// 查我关注了谁走这个索引
+	Follower int64 `gorm:"uniqueIndex:follower_followee"`
+	// 查我的粉丝走这个索引
+	Followee int64 `gorm:"uniqueIndex:follower_followee;index"`
+	Status   uint8
+	Ctime    int64
+	Utime    int64
+}
@@ -0,0 +1,97 @@
+package dao
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+//go:generate mockgen -source=./history.go -package=daomocks -destination=./mocks/history.mock.go HistoryRecordDAO
+type HistoryRecordDAO interface {
+	// Upsert 同一个用户同一个资源只有一条记录，重复阅读只更新阅读时间
+	Upsert(ctx context.Context, r HistoryRecord) error
+	// List 按照阅读时间倒序，(maxUtime, maxId) 是上一页最后一条记录，maxUtime 为 0 表示从头开始
+	List(ctx context.Context, uid int64, maxUtime, maxId int64, limit int) ([]HistoryRecord, error)
+	// Delete 删除 uid 的部分记录，别人的记录会被忽略
+	Delete(ctx context.Context, uid int64, ids []int64) error
+	DeleteAll(ctx context.Context, uid int64) error
+	// DeleteBefore 删除阅读时间早于 utime 的记录，一次最多删除 limit 条，返回删除的条数
+	DeleteBefore(ctx context.Context, utime int64, limit int) (int64, error)
+}
+
+type GORMHistoryRecordDAO struct {
+	db *gorm.DB
+}
+
+func NewGORMHistoryRecordDAO(db *gorm.DB) HistoryRecordDAO {
+	return &GORMHistoryRecordDAO{
+		db: db,
+	}
+}
+
+func (g *GORMHistoryRecordDAO) Upsert(ctx context.Context, r HistoryRecord) error {
+	now := time.Now().UnixMilli()
+	r.Ctime = now
+	if r.Utime == 0 {
+		r.Utime = now
+	}
+	return g.db.WithContext(ctx).Clauses(clause.OnConflict{
+		// 消息可能乱序，阅读时间只往后推
+		DoUpdates: clause.Assignments(map[string]any{
+			"utime": gorm.Expr("GREATEST(utime, ?)", r.Utime),
+		}),
+	}).Create(&r).Error
+}
+
+func (g *GORMHistoryRecordDAO) List(ctx context.Context,
+	uid int64, maxUtime, maxId int64, limit int) ([]HistoryRecord, error) {
+	var res []HistoryRecord
+	db := g.db.WithContext(ctx).Where("uid = ?", uid)
+	if maxUtime > 0 {
+		// 阅读时间可能相同，用 id 来区分
+		db = db.Where("utime < ? OR (utime = ? AND id < ?)", maxUtime, maxUtime, maxId)
+	}
+	err := db.Order("utime DESC, id DESC").Limit(limit).Find(&res).Error
+	return res, err
+}
+
+func (g *GORMHistoryRecordDAO) Delete(ctx context.Context, uid int64, ids []int64) error {
+	return g.db.WithContext(ctx).
+		Where("uid = ? AND id IN ?", uid, ids).
+		Delete(&HistoryRecord{}).Error
+}
+
+func (g *GORMHistoryRecordDAO) DeleteAll(ctx context.Context, uid int64) error {
+	return g.db.WithContext(ctx).
+		Where("uid = ?", uid).
+		Delete(&HistoryRecord{}).Error
+}
+
+func (g *GORMHistoryRecordDAO) DeleteBefore(ctx context.Context, utime int64, limit int) (int64, error) {
+	// DELETE 不支持 LIMIT 的方言很多，所以先查出来 id 再删
+	var ids []int64
+	err := g.db.WithContext(ctx).Model(&HistoryRecord{}).
+		Where("utime < ?", utime).
+		Order("utime ASC").
+		Limit(limit).
+		Pluck("id", &ids).Error
+	if err != nil || len(ids) == 0 {
+		return 0, err
+	}
+	res := g.db.WithContext(ctx).
+		Where("id IN ?", ids).
+		Delete(&HistoryRecord{})
+	return res.RowsAffected, res.Error
+}
+
+// HistoryRecord 阅读记录，同一个用户同一个资源只保留一条
+type HistoryRecord struct {
+	Id    int64  `gorm:"primaryKey,autoIncrement"`
+	Uid   int64  `gorm:"uniqueIndex:uid_biz_bizid;index:uid_utime"`
+	Biz   string `gorm:"type:varchar(128);uniqueIndex:uid_biz_bizid"`
+	BizId int64  `gorm:"uniqueIndex:uid_biz_bizid"`
+	Ctime int64
+	// Utime 最近一次阅读的时间
+	Utime int64 `gorm:"index:uid_utime;index"`
+}
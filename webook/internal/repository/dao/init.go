@@ -15,11 +15,18 @@ func InitTable(db *gorm.DB) error {
 		&User{},
 		&Article{},
 		&PublishedArticle{},
+		&PublishedArticleV2{},
 		&Interactive{},
 		&UserLikeBiz{},
 		&UserCollectionBiz{},
+		&Collection{},
+		&Comment{},
+		&FollowRelation{},
+		&HistoryRecord{},
 		&AsyncSms{},
-		&Job{})
+		&Job{},
+		&JobExecution{},
+		&OutboxEvent{})
 }
 
 func InitCollection(mdb *mongo.Database) error {
@@ -33,7 +40,9 @@ func InitCollection(mdb *mongo.Database) error {
 			Options: options.Index().SetUnique(true),
 		},
 		{
-			Keys: bson.D{bson.E{Key: "author_id", Value: 1}},
+			// 创作者查看自己的文章列表，按照更新时间倒序
+			Keys: bson.D{bson.E{Key: "author_id", Value: 1},
+				bson.E{Key: "utime", Value: -1}},
 		},
 	})
 	if err != nil {
@@ -47,7 +56,13 @@ func InitCollection(mdb *mongo.Database) error {
 			Options: options.Index().SetUnique(true),
 		},
 		{
-			Keys: bson.D{bson.E{Key: "author_id", Value: 1}},
+			Keys: bson.D{bson.E{Key: "author_id", Value: 1},
+				bson.E{Key: "utime", Value: -1}},
+		},
+		{
+			// ListPub 用
+			Keys: bson.D{bson.E{Key: "status", Value: 1},
+				bson.E{Key: "utime", Value: -1}},
 		},
 	})
 	return err
@@ -13,6 +13,8 @@ type InteractiveDAO interface {
 	InsertLikeInfo(ctx context.Context, biz string, id int64, uid int64) error
 	DeleteLikeInfo(ctx context.Context, biz string, id int64, uid int64) error
 	InsertCollectionBiz(ctx context.Context, biz UserCollectionBiz) error
+	// DeleteCollectionBiz 取消收藏，没有收藏过会返回 ErrRecordNotFound
+	DeleteCollectionBiz(ctx context.Context, biz string, id int64, uid int64) error
 	GetLikeInfo(ctx context.Context,
 		biz string, id int64, uid int64) (UserLikeBiz, error)
 	GetCollectInfo(ctx context.Context,
@@ -104,6 +106,27 @@ func (dao *GORMInteractiveDAO) InsertCollectionBiz(ctx context.Context, cb UserC
 	})
 }
 
+func (dao *GORMInteractiveDAO) DeleteCollectionBiz(ctx context.Context,
+	biz string, id int64, uid int64) error {
+	now := time.Now().UnixMilli()
+	return dao.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Where("uid = ? AND biz_id = ? AND biz = ?", uid, id, biz).
+			Delete(&UserCollectionBiz{})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrRecordNotFound
+		}
+		return tx.Model(&Interactive{}).
+			Where("biz_id = ? AND biz = ?", id, biz).
+			Updates(map[string]any{
+				"utime":       now,
+				"collect_cnt": gorm.Expr("collect_cnt - 1"),
+			}).Error
+	})
+}
+
 func (dao *GORMInteractiveDAO) InsertLikeInfo(ctx context.Context,
 	biz string, id int64, uid int64) error {
 	now := time.Now().UnixMilli()
@@ -212,6 +235,7 @@ type Interactive struct {
 	ReadCnt    int64
 	LikeCnt    int64
 	CollectCnt int64
+	CommentCnt int64
 	Ctime      int64
 	Utime      int64
 }
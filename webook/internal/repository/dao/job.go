@@ -2,15 +2,43 @@ package dao
 
 import (
 	"context"
+	"errors"
+	"github.com/go-sql-driver/mysql"
 	"gorm.io/gorm"
 	"time"
+	"unicode/utf8"
 )
 
+var (
+	ErrJobNameExists = errors.New("任务名字已经存在")
+	// ErrJobLeaseLost 续约的时候发现任务已经被别的节点抢走了
+	ErrJobLeaseLost = errors.New("任务已经被别的节点抢占")
+)
+
+//go:generate mockgen -source=./job.go -package=daomocks -destination=./mocks/job.mock.go JobDAO
 type JobDAO interface {
-	Preempt(ctx context.Context) (Job, error)
-	Release(ctx context.Context, jid int64) error
-	UpdateUtime(ctx context.Context, id int64) error
+	// Preempt 抢占一个到了调度时间的任务，或者续约超时（执行的节点大概率已经崩溃了）的任务
+	// 返回的 Version 是抢占之后的版本，续约和释放都要带上它
+	Preempt(ctx context.Context, leaseTimeout time.Duration) (Job, error)
+	// Release 释放任务，任务已经被别的节点抢走的话什么也不做
+	Release(ctx context.Context, jid int64, version int) error
+	// UpdateUtime 续约，任务已经被别的节点抢走的话返回 ErrJobLeaseLost
+	UpdateUtime(ctx context.Context, id int64, version int) error
 	UpdateNextTime(ctx context.Context, id int64, t time.Time) error
+	// UpdateLastRun 记录最近一次执行的结果，errMsg 为空代表成功
+	UpdateLastRun(ctx context.Context, id int64, startTime int64, errMsg string) error
+
+	// Insert 名字重复会返回 ErrJobNameExists
+	Insert(ctx context.Context, j Job) (int64, error)
+	// Update 更新任务的定义，不存在会返回 ErrRecordNotFound
+	Update(ctx context.Context, j Job) error
+	// Pause 暂停调度，正在执行的那一次不受影响
+	Pause(ctx context.Context, id int64) error
+	// Resume 恢复调度，只对暂停了的任务有效
+	Resume(ctx context.Context, id int64, nextTime int64) error
+	Delete(ctx context.Context, id int64) error
+	FindById(ctx context.Context, id int64) (Job, error)
+	List(ctx context.Context, offset int, limit int) ([]Job, error)
 }
 
 type GORMJobDAO struct {
@@ -21,55 +49,70 @@ func NewGORMJobDAO(db *gorm.DB) JobDAO {
 	return &GORMJobDAO{db: db}
 }
 
-func (dao *GORMJobDAO) Preempt(ctx context.Context) (Job, error) {
+func (dao *GORMJobDAO) Preempt(ctx context.Context, leaseTimeout time.Duration) (Job, error) {
 	db := dao.db.WithContext(ctx)
 	for {
 		var j Job
 		// 到了调度的时间
 		now := time.Now().UnixMilli()
-		// 作业：这里是缺少找到续约失败的 JOB 出来执行
-		err := db.Where("status = ? AND next_time < ?", jobStatusWaiting, now).
+		// 正在运行，但是很久没有续约了，说明执行的节点大概率已经崩溃了
+		expired := now - leaseTimeout.Milliseconds()
+		err := db.Where("(status = ? AND next_time < ?) OR (status = ? AND utime < ?)",
+			jobStatusWaiting, now, jobStatusRunning, expired).
 			First(&j).Error
 		if err != nil {
 			return j, err
 		}
 		// 然后要开始抢占
 		// 这里利用 version 来执行 CAS 操作
-		// 其它一些公司可能会有一些 utime 之类的字段
+		// 带上 status 是为了避免在查询之后被暂停了，还被我们抢走
 		res := db.Model(&Job{}).
-			Where("id = ? AND version = ?", j.Id, j.Version).
+			Where("id = ? AND version = ? AND status = ?", j.Id, j.Version, j.Status).
 			Updates(map[string]any{
 				"status":  jobStatusRunning,
 				"version": j.Version + 1,
 				"utime":   now,
 			})
 		if res.Error != nil {
-			return Job{}, err
+			return Job{}, res.Error
 		}
 		if res.RowsAffected == 0 {
 			// 没抢到
 			continue
 		}
-		return j, err
+		j.Status = jobStatusRunning
+		j.Version = j.Version + 1
+		j.Utime = now
+		return j, nil
 	}
 }
 
-func (dao *GORMJobDAO) Release(ctx context.Context, jid int64) error {
+func (dao *GORMJobDAO) Release(ctx context.Context, jid int64, version int) error {
 	now := time.Now().UnixMilli()
+	// 执行期间可能被暂停了，这时候不能把状态改回去
+	// version 不对说明已经被别的节点抢走了，也不能释放
 	return dao.db.WithContext(ctx).Model(&Job{}).
-		Where("id = ?", jid).Updates(map[string]any{
-		"status": jobStatusWaiting,
-		"utime":  now,
-	}).Error
+		Where("id = ? AND version = ? AND status = ?", jid, version, jobStatusRunning).
+		Updates(map[string]any{
+			"status": jobStatusWaiting,
+			"utime":  now,
+		}).Error
 }
 
-func (dao *GORMJobDAO) UpdateUtime(ctx context.Context, jid int64) error {
+func (dao *GORMJobDAO) UpdateUtime(ctx context.Context, jid int64, version int) error {
 	now := time.Now().UnixMilli()
-	return dao.db.WithContext(ctx).Model(&Job{}).
-		Where("id = ?", jid).
+	res := dao.db.WithContext(ctx).Model(&Job{}).
+		Where("id = ? AND version = ?", jid, version).
 		Updates(map[string]any{
 			"utime": now,
-		}).Error
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrJobLeaseLost
+	}
+	return nil
 }
 
 func (dao *GORMJobDAO) UpdateNextTime(ctx context.Context, id int64, t time.Time) error {
@@ -81,19 +124,130 @@ func (dao *GORMJobDAO) UpdateNextTime(ctx context.Context, id int64, t time.Time
 	}).Error
 }
 
+func (dao *GORMJobDAO) UpdateLastRun(ctx context.Context, id int64, startTime int64, errMsg string) error {
+	// 错误信息可能很长，截断一下
+	errMsg = truncateErrMsg(errMsg)
+	return dao.db.WithContext(ctx).Model(&Job{}).
+		Where("id = ?", id).Updates(map[string]any{
+		"last_run_time": startTime,
+		"last_run_err":  errMsg,
+	}).Error
+}
+
+func (dao *GORMJobDAO) Insert(ctx context.Context, j Job) (int64, error) {
+	now := time.Now().UnixMilli()
+	j.Ctime = now
+	j.Utime = now
+	err := dao.db.WithContext(ctx).Create(&j).Error
+	if me, ok := err.(*mysql.MySQLError); ok {
+		const duplicateErr uint16 = 1062
+		if me.Number == duplicateErr {
+			return 0, ErrJobNameExists
+		}
+	}
+	return j.Id, err
+}
+
+func (dao *GORMJobDAO) Update(ctx context.Context, j Job) error {
+	// 不修改 version，version 只用来标记是谁抢占了任务
+	res := dao.db.WithContext(ctx).Model(&Job{}).
+		Where("id = ?", j.Id).
+		Updates(map[string]any{
+			"name":           j.Name,
+			"executor":       j.Executor,
+			"expression":     j.Expression,
+			"cfg":            j.Cfg,
+			"retry_max":      j.RetryMax,
+			"retry_interval": j.RetryInterval,
+			"next_time":      j.NextTime,
+			"utime":          time.Now().UnixMilli(),
+		})
+	if me, ok := res.Error.(*mysql.MySQLError); ok {
+		const duplicateErr uint16 = 1062
+		if me.Number == duplicateErr {
+			return ErrJobNameExists
+		}
+	}
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrRecordNotFound
+	}
+	return nil
+}
+
+func (dao *GORMJobDAO) Pause(ctx context.Context, id int64) error {
+	res := dao.db.WithContext(ctx).Model(&Job{}).
+		Where("id = ?", id).
+		Updates(map[string]any{
+			"status": jobStatusPaused,
+			"utime":  time.Now().UnixMilli(),
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrRecordNotFound
+	}
+	return nil
+}
+
+func (dao *GORMJobDAO) Resume(ctx context.Context, id int64, nextTime int64) error {
+	return dao.db.WithContext(ctx).Model(&Job{}).
+		Where("id = ? AND status = ?", id, jobStatusPaused).
+		Updates(map[string]any{
+			"status":    jobStatusWaiting,
+			"next_time": nextTime,
+			"utime":     time.Now().UnixMilli(),
+		}).Error
+}
+
+func (dao *GORMJobDAO) Delete(ctx context.Context, id int64) error {
+	res := dao.db.WithContext(ctx).Where("id = ?", id).Delete(&Job{})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrRecordNotFound
+	}
+	return nil
+}
+
+func (dao *GORMJobDAO) FindById(ctx context.Context, id int64) (Job, error) {
+	var j Job
+	err := dao.db.WithContext(ctx).Where("id = ?", id).First(&j).Error
+	return j, err
+}
+
+func (dao *GORMJobDAO) List(ctx context.Context, offset int, limit int) ([]Job, error) {
+	var res []Job
+	err := dao.db.WithContext(ctx).
+		Order("id ASC").
+		Offset(offset).Limit(limit).
+		Find(&res).Error
+	return res, err
+}
+
 type Job struct {
 	Id         int64  `gorm:"primaryKey,autoIncrement"`
 	Name       string `gorm:"type:varchar(128);unique"`
 	Executor   string // 执行器
 	Expression string
 	Cfg        string
+	// 执行失败之后最多重试几次，以及重试的间隔（毫秒）
+	RetryMax      int
+	RetryInterval int64
 	// 状态来表达，是不是可以抢占，有没有被人抢占
 	Status int
 
 	Version  int
 	NextTime int64 `gorm:"index"`
-	Utime    int64
-	Ctime    int64
+	// 最近一次执行的开始时间和错误信息，错误信息为空代表成功
+	LastRunTime int64
+	LastRunErr  string `gorm:"type:varchar(1024)"`
+	Utime       int64
+	Ctime       int64
 }
 
 const (
@@ -104,3 +258,18 @@ const (
 	// jobStatusPaused 不再需要调度了
 	jobStatusPaused
 )
+
+// maxErrLen 错误信息最多保存多少字节
+const maxErrLen = 1024
+
+// truncateErrMsg 按照字节截断，但是不能把一个字符截成两半，不然 utf8mb4 的列会拒绝写入
+func truncateErrMsg(msg string) string {
+	if len(msg) <= maxErrLen {
+		return msg
+	}
+	end := maxErrLen
+	for end > 0 && !utf8.RuneStart(msg[end]) {
+		end--
+	}
+	return msg[:end]
+}
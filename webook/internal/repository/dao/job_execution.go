@@ -0,0 +1,82 @@
+package dao
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"time"
+)
+
+const (
+	JobExecutionStatusUnknown uint8 = iota
+	// JobExecutionStatusRunning 还在执行，或者执行的节点崩溃了没来得及更新
+	JobExecutionStatusRunning
+	JobExecutionStatusSuccess
+	JobExecutionStatusFailed
+)
+
+//go:generate mockgen -source=./job_execution.go -package=daomocks -destination=./mocks/job_execution.mock.go JobExecutionDAO
+type JobExecutionDAO interface {
+	// Insert 开始执行的时候插入一条，返回 ID
+	Insert(ctx context.Context, e JobExecution) (int64, error)
+	// Finish 执行结束，更新结果
+	Finish(ctx context.Context, id int64, endTime int64, status uint8, errMsg string) error
+	// ListByJob 按照 ID 倒序，maxId 为 0 表示从头开始
+	ListByJob(ctx context.Context, jid int64, maxId int64, limit int) ([]JobExecution, error)
+}
+
+type GORMJobExecutionDAO struct {
+	db *gorm.DB
+}
+
+func NewGORMJobExecutionDAO(db *gorm.DB) JobExecutionDAO {
+	return &GORMJobExecutionDAO{db: db}
+}
+
+func (g *GORMJobExecutionDAO) Insert(ctx context.Context, e JobExecution) (int64, error) {
+	now := time.Now().UnixMilli()
+	e.Ctime = now
+	e.Utime = now
+	err := g.db.WithContext(ctx).Create(&e).Error
+	return e.Id, err
+}
+
+func (g *GORMJobExecutionDAO) Finish(ctx context.Context, id int64,
+	endTime int64, status uint8, errMsg string) error {
+	errMsg = truncateErrMsg(errMsg)
+	return g.db.WithContext(ctx).Model(&JobExecution{}).
+		Where("id = ?", id).
+		Updates(map[string]any{
+			"end_time": endTime,
+			"status":   status,
+			"err_msg":  errMsg,
+			"utime":    time.Now().UnixMilli(),
+		}).Error
+}
+
+func (g *GORMJobExecutionDAO) ListByJob(ctx context.Context,
+	jid int64, maxId int64, limit int) ([]JobExecution, error) {
+	var res []JobExecution
+	db := g.db.WithContext(ctx).Where("jid = ?", jid)
+	if maxId > 0 {
+		db = db.Where("id < ?", maxId)
+	}
+	err := db.Order("id DESC").Limit(limit).Find(&res).Error
+	return res, err
+}
+
+// JobExecution 任务的一次执行记录
+type JobExecution struct {
+	Id  int64 `gorm:"primaryKey,autoIncrement"`
+	Jid int64 `gorm:"index"`
+	// 冗余一下，方便按照执行器排查问题
+	Executor string `gorm:"type:varchar(128)"`
+	// 在哪个节点上执行的
+	NodeId    string `gorm:"type:varchar(128)"`
+	StartTime int64
+	// 还没执行完就是 0
+	EndTime int64
+	Status  uint8
+	ErrMsg  string `gorm:"type:varchar(1024)"`
+	Ctime   int64
+	Utime   int64
+}
@@ -0,0 +1,42 @@
+package dao
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestTruncateErrMsg(t *testing.T) {
+	testCases := []struct {
+		name string
+		msg  string
+
+		wantLen int
+	}{
+		{
+			name:    "不需要截断",
+			msg:     "执行失败",
+			wantLen: len("执行失败"),
+		},
+		{
+			name:    "英文刚好截断",
+			msg:     strings.Repeat("a", maxErrLen+10),
+			wantLen: maxErrLen,
+		},
+		{
+			// 一个中文三个字节，1024 不是 3 的倍数
+			name:    "中文不能截成两半",
+			msg:     strings.Repeat("错", maxErrLen),
+			wantLen: maxErrLen / 3 * 3,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			res := truncateErrMsg(tc.msg)
+			assert.Equal(t, tc.wantLen, len(res))
+			assert.True(t, utf8.ValidString(res))
+		})
+	}
+}
@@ -12,6 +12,7 @@ package daomocks
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	domain "github.com/wsqigo/basic-go/webook/internal/domain"
 	dao "github.com/wsqigo/basic-go/webook/internal/repository/dao"
@@ -101,6 +102,36 @@ func (mr *MockArticleDAOMockRecorder) Insert(ctx, art any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Insert", reflect.TypeOf((*MockArticleDAO)(nil).Insert), ctx, art)
 }
 
+// ListPub mocks base method.
+func (m *MockArticleDAO) ListPub(ctx context.Context, start time.Time, offset, limit int) ([]dao.PublishedArticle, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPub", ctx, start, offset, limit)
+	ret0, _ := ret[0].([]dao.PublishedArticle)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPub indicates an expected call of ListPub.
+func (mr *MockArticleDAOMockRecorder) ListPub(ctx, start, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPub", reflect.TypeOf((*MockArticleDAO)(nil).ListPub), ctx, start, offset, limit)
+}
+
+// ListPubByAuthor mocks base method.
+func (m *MockArticleDAO) ListPubByAuthor(ctx context.Context, uid int64, start time.Time, limit int) ([]dao.PublishedArticle, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPubByAuthor", ctx, uid, start, limit)
+	ret0, _ := ret[0].([]dao.PublishedArticle)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPubByAuthor indicates an expected call of ListPubByAuthor.
+func (mr *MockArticleDAOMockRecorder) ListPubByAuthor(ctx, uid, start, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPubByAuthor", reflect.TypeOf((*MockArticleDAO)(nil).ListPubByAuthor), ctx, uid, start, limit)
+}
+
 // Sync mocks base method.
 func (m *MockArticleDAO) Sync(ctx context.Context, art dao.Article) (int64, error) {
 	m.ctrl.T.Helper()
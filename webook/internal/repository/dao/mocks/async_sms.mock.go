@@ -40,6 +40,21 @@ func (m *MockAsyncSmsDAO) EXPECT() *MockAsyncSmsDAOMockRecorder {
 	return m.recorder
 }
 
+// DeleteFinishedBefore mocks base method.
+func (m *MockAsyncSmsDAO) DeleteFinishedBefore(ctx context.Context, utime int64, limit int) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteFinishedBefore", ctx, utime, limit)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteFinishedBefore indicates an expected call of DeleteFinishedBefore.
+func (mr *MockAsyncSmsDAOMockRecorder) DeleteFinishedBefore(ctx, utime, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFinishedBefore", reflect.TypeOf((*MockAsyncSmsDAO)(nil).DeleteFinishedBefore), ctx, utime, limit)
+}
+
 // GetWaitingSMS mocks base method.
 func (m *MockAsyncSmsDAO) GetWaitingSMS(ctx context.Context) (dao.AsyncSms, error) {
 	m.ctrl.T.Helper()
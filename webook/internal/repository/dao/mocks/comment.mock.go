@@ -0,0 +1,116 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./comment.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./comment.go -package=daomocks -destination=./mocks/comment.mock.go CommentDAO
+//
+
+// Package daomocks is a generated GoMock package.
+package daomocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	dao "github.com/wsqigo/basic-go/webook/internal/repository/dao"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockCommentDAO is a mock of CommentDAO interface.
+type MockCommentDAO struct {
+	ctrl     *gomock.Controller
+	recorder *MockCommentDAOMockRecorder
+}
+
+// MockCommentDAOMockRecorder is the mock recorder for MockCommentDAO.
+type MockCommentDAOMockRecorder struct {
+	mock *MockCommentDAO
+}
+
+// NewMockCommentDAO creates a new mock instance.
+func NewMockCommentDAO(ctrl *gomock.Controller) *MockCommentDAO {
+	mock := &MockCommentDAO{ctrl: ctrl}
+	mock.recorder = &MockCommentDAOMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCommentDAO) EXPECT() *MockCommentDAOMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockCommentDAO) Delete(ctx context.Context, c dao.Comment) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, c)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockCommentDAOMockRecorder) Delete(ctx, c any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockCommentDAO)(nil).Delete), ctx, c)
+}
+
+// FindByBiz mocks base method.
+func (m *MockCommentDAO) FindByBiz(ctx context.Context, biz string, bizId, minId int64, limit int) ([]dao.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByBiz", ctx, biz, bizId, minId, limit)
+	ret0, _ := ret[0].([]dao.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByBiz indicates an expected call of FindByBiz.
+func (mr *MockCommentDAOMockRecorder) FindByBiz(ctx, biz, bizId, minId, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByBiz", reflect.TypeOf((*MockCommentDAO)(nil).FindByBiz), ctx, biz, bizId, minId, limit)
+}
+
+// FindById mocks base method.
+func (m *MockCommentDAO) FindById(ctx context.Context, id int64) (dao.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindById", ctx, id)
+	ret0, _ := ret[0].(dao.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindById indicates an expected call of FindById.
+func (mr *MockCommentDAOMockRecorder) FindById(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindById", reflect.TypeOf((*MockCommentDAO)(nil).FindById), ctx, id)
+}
+
+// FindRepliesByRid mocks base method.
+func (m *MockCommentDAO) FindRepliesByRid(ctx context.Context, rid, maxId int64, limit int) ([]dao.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindRepliesByRid", ctx, rid, maxId, limit)
+	ret0, _ := ret[0].([]dao.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindRepliesByRid indicates an expected call of FindRepliesByRid.
+func (mr *MockCommentDAOMockRecorder) FindRepliesByRid(ctx, rid, maxId, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindRepliesByRid", reflect.TypeOf((*MockCommentDAO)(nil).FindRepliesByRid), ctx, rid, maxId, limit)
+}
+
+// Insert mocks base method.
+func (m *MockCommentDAO) Insert(ctx context.Context, c dao.Comment) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Insert", ctx, c)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Insert indicates an expected call of Insert.
+func (mr *MockCommentDAOMockRecorder) Insert(ctx, c any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Insert", reflect.TypeOf((*MockCommentDAO)(nil).Insert), ctx, c)
+}
@@ -0,0 +1,144 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./follow.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./follow.go -package=daomocks -destination=./mocks/follow.mock.go FollowRelationDAO
+//
+
+// Package daomocks is a generated GoMock package.
+package daomocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	dao "github.com/wsqigo/basic-go/webook/internal/repository/dao"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockFollowRelationDAO is a mock of FollowRelationDAO interface.
+type MockFollowRelationDAO struct {
+	ctrl     *gomock.Controller
+	recorder *MockFollowRelationDAOMockRecorder
+}
+
+// MockFollowRelationDAOMockRecorder is the mock recorder for MockFollowRelationDAO.
+type MockFollowRelationDAOMockRecorder struct {
+	mock *MockFollowRelationDAO
+}
+
+// NewMockFollowRelationDAO creates a new mock instance.
+func NewMockFollowRelationDAO(ctrl *gomock.Controller) *MockFollowRelationDAO {
+	mock := &MockFollowRelationDAO{ctrl: ctrl}
+	mock.recorder = &MockFollowRelationDAOMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFollowRelationDAO) EXPECT() *MockFollowRelationDAOMockRecorder {
+	return m.recorder
+}
+
+// CancelFollowRelation mocks base method.
+func (m *MockFollowRelationDAO) CancelFollowRelation(ctx context.Context, follower, followee int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelFollowRelation", ctx, follower, followee)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CancelFollowRelation indicates an expected call of CancelFollowRelation.
+func (mr *MockFollowRelationDAOMockRecorder) CancelFollowRelation(ctx, follower, followee any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelFollowRelation", reflect.TypeOf((*MockFollowRelationDAO)(nil).CancelFollowRelation), ctx, follower, followee)
+}
+
+// CntFollowee mocks base method.
+func (m *MockFollowRelationDAO) CntFollowee(ctx context.Context, uid int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CntFollowee", ctx, uid)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CntFollowee indicates an expected call of CntFollowee.
+func (mr *MockFollowRelationDAOMockRecorder) CntFollowee(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CntFollowee", reflect.TypeOf((*MockFollowRelationDAO)(nil).CntFollowee), ctx, uid)
+}
+
+// CntFollower mocks base method.
+func (m *MockFollowRelationDAO) CntFollower(ctx context.Context, uid int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CntFollower", ctx, uid)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CntFollower indicates an expected call of CntFollower.
+func (mr *MockFollowRelationDAOMockRecorder) CntFollower(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CntFollower", reflect.TypeOf((*MockFollowRelationDAO)(nil).CntFollower), ctx, uid)
+}
+
+// CreateFollowRelation mocks base method.
+func (m *MockFollowRelationDAO) CreateFollowRelation(ctx context.Context, c dao.FollowRelation) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateFollowRelation", ctx, c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateFollowRelation indicates an expected call of CreateFollowRelation.
+func (mr *MockFollowRelationDAOMockRecorder) CreateFollowRelation(ctx, c any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateFollowRelation", reflect.TypeOf((*MockFollowRelationDAO)(nil).CreateFollowRelation), ctx, c)
+}
+
+// FollowRelationDetail mocks base method.
+func (m *MockFollowRelationDAO) FollowRelationDetail(ctx context.Context, follower, followee int64) (dao.FollowRelation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FollowRelationDetail", ctx, follower, followee)
+	ret0, _ := ret[0].(dao.FollowRelation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FollowRelationDetail indicates an expected call of FollowRelationDetail.
+func (mr *MockFollowRelationDAOMockRecorder) FollowRelationDetail(ctx, follower, followee any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FollowRelationDetail", reflect.TypeOf((*MockFollowRelationDAO)(nil).FollowRelationDetail), ctx, follower, followee)
+}
+
+// FollowRelationList mocks base method.
+func (m *MockFollowRelationDAO) FollowRelationList(ctx context.Context, follower, maxId int64, limit int) ([]dao.FollowRelation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FollowRelationList", ctx, follower, maxId, limit)
+	ret0, _ := ret[0].([]dao.FollowRelation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FollowRelationList indicates an expected call of FollowRelationList.
+func (mr *MockFollowRelationDAOMockRecorder) FollowRelationList(ctx, follower, maxId, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FollowRelationList", reflect.TypeOf((*MockFollowRelationDAO)(nil).FollowRelationList), ctx, follower, maxId, limit)
+}
+
+// FollowerRelationList mocks base method.
+func (m *MockFollowRelationDAO) FollowerRelationList(ctx context.Context, followee, maxId int64, limit int) ([]dao.FollowRelation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FollowerRelationList", ctx, followee, maxId, limit)
+	ret0, _ := ret[0].([]dao.FollowRelation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FollowerRelationList indicates an expected call of FollowerRelationList.
+func (mr *MockFollowRelationDAOMockRecorder) FollowerRelationList(ctx, followee, maxId, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FollowerRelationList", reflect.TypeOf((*MockFollowRelationDAO)(nil).FollowerRelationList), ctx, followee, maxId, limit)
+}
@@ -0,0 +1,113 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./history.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./history.go -package=daomocks -destination=./mocks/history.mock.go HistoryRecordDAO
+//
+
+// Package daomocks is a generated GoMock package.
+package daomocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	dao "github.com/wsqigo/basic-go/webook/internal/repository/dao"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockHistoryRecordDAO is a mock of HistoryRecordDAO interface.
+type MockHistoryRecordDAO struct {
+	ctrl     *gomock.Controller
+	recorder *MockHistoryRecordDAOMockRecorder
+}
+
+// MockHistoryRecordDAOMockRecorder is the mock recorder for MockHistoryRecordDAO.
+type MockHistoryRecordDAOMockRecorder struct {
+	mock *MockHistoryRecordDAO
+}
+
+// NewMockHistoryRecordDAO creates a new mock instance.
+func NewMockHistoryRecordDAO(ctrl *gomock.Controller) *MockHistoryRecordDAO {
+	mock := &MockHistoryRecordDAO{ctrl: ctrl}
+	mock.recorder = &MockHistoryRecordDAOMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHistoryRecordDAO) EXPECT() *MockHistoryRecordDAOMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockHistoryRecordDAO) Delete(ctx context.Context, uid int64, ids []int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, uid, ids)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockHistoryRecordDAOMockRecorder) Delete(ctx, uid, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockHistoryRecordDAO)(nil).Delete), ctx, uid, ids)
+}
+
+// DeleteAll mocks base method.
+func (m *MockHistoryRecordDAO) DeleteAll(ctx context.Context, uid int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAll", ctx, uid)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAll indicates an expected call of DeleteAll.
+func (mr *MockHistoryRecordDAOMockRecorder) DeleteAll(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAll", reflect.TypeOf((*MockHistoryRecordDAO)(nil).DeleteAll), ctx, uid)
+}
+
+// DeleteBefore mocks base method.
+func (m *MockHistoryRecordDAO) DeleteBefore(ctx context.Context, utime int64, limit int) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteBefore", ctx, utime, limit)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteBefore indicates an expected call of DeleteBefore.
+func (mr *MockHistoryRecordDAOMockRecorder) DeleteBefore(ctx, utime, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBefore", reflect.TypeOf((*MockHistoryRecordDAO)(nil).DeleteBefore), ctx, utime, limit)
+}
+
+// List mocks base method.
+func (m *MockHistoryRecordDAO) List(ctx context.Context, uid, maxUtime, maxId int64, limit int) ([]dao.HistoryRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, uid, maxUtime, maxId, limit)
+	ret0, _ := ret[0].([]dao.HistoryRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockHistoryRecordDAOMockRecorder) List(ctx, uid, maxUtime, maxId, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockHistoryRecordDAO)(nil).List), ctx, uid, maxUtime, maxId, limit)
+}
+
+// Upsert mocks base method.
+func (m *MockHistoryRecordDAO) Upsert(ctx context.Context, r dao.HistoryRecord) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Upsert", ctx, r)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Upsert indicates an expected call of Upsert.
+func (mr *MockHistoryRecordDAOMockRecorder) Upsert(ctx, r any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upsert", reflect.TypeOf((*MockHistoryRecordDAO)(nil).Upsert), ctx, r)
+}
@@ -0,0 +1,214 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./job.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./job.go -package=daomocks -destination=./mocks/job.mock.go JobDAO
+//
+
+// Package daomocks is a generated GoMock package.
+package daomocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	dao "github.com/wsqigo/basic-go/webook/internal/repository/dao"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockJobDAO is a mock of JobDAO interface.
+type MockJobDAO struct {
+	ctrl     *gomock.Controller
+	recorder *MockJobDAOMockRecorder
+}
+
+// MockJobDAOMockRecorder is the mock recorder for MockJobDAO.
+type MockJobDAOMockRecorder struct {
+	mock *MockJobDAO
+}
+
+// NewMockJobDAO creates a new mock instance.
+func NewMockJobDAO(ctrl *gomock.Controller) *MockJobDAO {
+	mock := &MockJobDAO{ctrl: ctrl}
+	mock.recorder = &MockJobDAOMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockJobDAO) EXPECT() *MockJobDAOMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockJobDAO) Delete(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockJobDAOMockRecorder) Delete(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockJobDAO)(nil).Delete), ctx, id)
+}
+
+// FindById mocks base method.
+func (m *MockJobDAO) FindById(ctx context.Context, id int64) (dao.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindById", ctx, id)
+	ret0, _ := ret[0].(dao.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindById indicates an expected call of FindById.
+func (mr *MockJobDAOMockRecorder) FindById(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindById", reflect.TypeOf((*MockJobDAO)(nil).FindById), ctx, id)
+}
+
+// Insert mocks base method.
+func (m *MockJobDAO) Insert(ctx context.Context, j dao.Job) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Insert", ctx, j)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Insert indicates an expected call of Insert.
+func (mr *MockJobDAOMockRecorder) Insert(ctx, j any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Insert", reflect.TypeOf((*MockJobDAO)(nil).Insert), ctx, j)
+}
+
+// List mocks base method.
+func (m *MockJobDAO) List(ctx context.Context, offset, limit int) ([]dao.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, offset, limit)
+	ret0, _ := ret[0].([]dao.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockJobDAOMockRecorder) List(ctx, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockJobDAO)(nil).List), ctx, offset, limit)
+}
+
+// Pause mocks base method.
+func (m *MockJobDAO) Pause(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Pause", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Pause indicates an expected call of Pause.
+func (mr *MockJobDAOMockRecorder) Pause(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Pause", reflect.TypeOf((*MockJobDAO)(nil).Pause), ctx, id)
+}
+
+// Preempt mocks base method.
+func (m *MockJobDAO) Preempt(ctx context.Context, leaseTimeout time.Duration) (dao.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Preempt", ctx, leaseTimeout)
+	ret0, _ := ret[0].(dao.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Preempt indicates an expected call of Preempt.
+func (mr *MockJobDAOMockRecorder) Preempt(ctx, leaseTimeout any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Preempt", reflect.TypeOf((*MockJobDAO)(nil).Preempt), ctx, leaseTimeout)
+}
+
+// Release mocks base method.
+func (m *MockJobDAO) Release(ctx context.Context, jid int64, version int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Release", ctx, jid, version)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Release indicates an expected call of Release.
+func (mr *MockJobDAOMockRecorder) Release(ctx, jid, version any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Release", reflect.TypeOf((*MockJobDAO)(nil).Release), ctx, jid, version)
+}
+
+// Resume mocks base method.
+func (m *MockJobDAO) Resume(ctx context.Context, id, nextTime int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Resume", ctx, id, nextTime)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Resume indicates an expected call of Resume.
+func (mr *MockJobDAOMockRecorder) Resume(ctx, id, nextTime any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Resume", reflect.TypeOf((*MockJobDAO)(nil).Resume), ctx, id, nextTime)
+}
+
+// Update mocks base method.
+func (m *MockJobDAO) Update(ctx context.Context, j dao.Job) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, j)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockJobDAOMockRecorder) Update(ctx, j any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockJobDAO)(nil).Update), ctx, j)
+}
+
+// UpdateLastRun mocks base method.
+func (m *MockJobDAO) UpdateLastRun(ctx context.Context, id, startTime int64, errMsg string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateLastRun", ctx, id, startTime, errMsg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateLastRun indicates an expected call of UpdateLastRun.
+func (mr *MockJobDAOMockRecorder) UpdateLastRun(ctx, id, startTime, errMsg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLastRun", reflect.TypeOf((*MockJobDAO)(nil).UpdateLastRun), ctx, id, startTime, errMsg)
+}
+
+// UpdateNextTime mocks base method.
+func (m *MockJobDAO) UpdateNextTime(ctx context.Context, id int64, t time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateNextTime", ctx, id, t)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateNextTime indicates an expected call of UpdateNextTime.
+func (mr *MockJobDAOMockRecorder) UpdateNextTime(ctx, id, t any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateNextTime", reflect.TypeOf((*MockJobDAO)(nil).UpdateNextTime), ctx, id, t)
+}
+
+// UpdateUtime mocks base method.
+func (m *MockJobDAO) UpdateUtime(ctx context.Context, id int64, version int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUtime", ctx, id, version)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateUtime indicates an expected call of UpdateUtime.
+func (mr *MockJobDAOMockRecorder) UpdateUtime(ctx, id, version any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUtime", reflect.TypeOf((*MockJobDAO)(nil).UpdateUtime), ctx, id, version)
+}
@@ -0,0 +1,85 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./job_execution.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./job_execution.go -package=daomocks -destination=./mocks/job_execution.mock.go JobExecutionDAO
+//
+
+// Package daomocks is a generated GoMock package.
+package daomocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	dao "github.com/wsqigo/basic-go/webook/internal/repository/dao"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockJobExecutionDAO is a mock of JobExecutionDAO interface.
+type MockJobExecutionDAO struct {
+	ctrl     *gomock.Controller
+	recorder *MockJobExecutionDAOMockRecorder
+}
+
+// MockJobExecutionDAOMockRecorder is the mock recorder for MockJobExecutionDAO.
+type MockJobExecutionDAOMockRecorder struct {
+	mock *MockJobExecutionDAO
+}
+
+// NewMockJobExecutionDAO creates a new mock instance.
+func NewMockJobExecutionDAO(ctrl *gomock.Controller) *MockJobExecutionDAO {
+	mock := &MockJobExecutionDAO{ctrl: ctrl}
+	mock.recorder = &MockJobExecutionDAOMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockJobExecutionDAO) EXPECT() *MockJobExecutionDAOMockRecorder {
+	return m.recorder
+}
+
+// Finish mocks base method.
+func (m *MockJobExecutionDAO) Finish(ctx context.Context, id, endTime int64, status uint8, errMsg string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Finish", ctx, id, endTime, status, errMsg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Finish indicates an expected call of Finish.
+func (mr *MockJobExecutionDAOMockRecorder) Finish(ctx, id, endTime, status, errMsg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Finish", reflect.TypeOf((*MockJobExecutionDAO)(nil).Finish), ctx, id, endTime, status, errMsg)
+}
+
+// Insert mocks base method.
+func (m *MockJobExecutionDAO) Insert(ctx context.Context, e dao.JobExecution) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Insert", ctx, e)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Insert indicates an expected call of Insert.
+func (mr *MockJobExecutionDAOMockRecorder) Insert(ctx, e any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Insert", reflect.TypeOf((*MockJobExecutionDAO)(nil).Insert), ctx, e)
+}
+
+// ListByJob mocks base method.
+func (m *MockJobExecutionDAO) ListByJob(ctx context.Context, jid, maxId int64, limit int) ([]dao.JobExecution, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByJob", ctx, jid, maxId, limit)
+	ret0, _ := ret[0].([]dao.JobExecution)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByJob indicates an expected call of ListByJob.
+func (mr *MockJobExecutionDAOMockRecorder) ListByJob(ctx, jid, maxId, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByJob", reflect.TypeOf((*MockJobExecutionDAO)(nil).ListByJob), ctx, jid, maxId, limit)
+}
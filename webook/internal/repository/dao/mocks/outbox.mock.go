@@ -0,0 +1,99 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./outbox.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./outbox.go -package=daomocks -destination=./mocks/outbox.mock.go OutboxDAO
+//
+
+// Package daomocks is a generated GoMock package.
+package daomocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	dao "github.com/wsqigo/basic-go/webook/internal/repository/dao"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockOutboxDAO is a mock of OutboxDAO interface.
+type MockOutboxDAO struct {
+	ctrl     *gomock.Controller
+	recorder *MockOutboxDAOMockRecorder
+}
+
+// MockOutboxDAOMockRecorder is the mock recorder for MockOutboxDAO.
+type MockOutboxDAOMockRecorder struct {
+	mock *MockOutboxDAO
+}
+
+// NewMockOutboxDAO creates a new mock instance.
+func NewMockOutboxDAO(ctrl *gomock.Controller) *MockOutboxDAO {
+	mock := &MockOutboxDAO{ctrl: ctrl}
+	mock.recorder = &MockOutboxDAOMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOutboxDAO) EXPECT() *MockOutboxDAOMockRecorder {
+	return m.recorder
+}
+
+// DeleteSentBefore mocks base method.
+func (m *MockOutboxDAO) DeleteSentBefore(ctx context.Context, utime int64, limit int) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSentBefore", ctx, utime, limit)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteSentBefore indicates an expected call of DeleteSentBefore.
+func (mr *MockOutboxDAOMockRecorder) DeleteSentBefore(ctx, utime, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSentBefore", reflect.TypeOf((*MockOutboxDAO)(nil).DeleteSentBefore), ctx, utime, limit)
+}
+
+// FindPending mocks base method.
+func (m *MockOutboxDAO) FindPending(ctx context.Context, limit int) ([]dao.OutboxEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindPending", ctx, limit)
+	ret0, _ := ret[0].([]dao.OutboxEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindPending indicates an expected call of FindPending.
+func (mr *MockOutboxDAOMockRecorder) FindPending(ctx, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindPending", reflect.TypeOf((*MockOutboxDAO)(nil).FindPending), ctx, limit)
+}
+
+// Insert mocks base method.
+func (m *MockOutboxDAO) Insert(ctx context.Context, evt dao.OutboxEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Insert", ctx, evt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Insert indicates an expected call of Insert.
+func (mr *MockOutboxDAOMockRecorder) Insert(ctx, evt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Insert", reflect.TypeOf((*MockOutboxDAO)(nil).Insert), ctx, evt)
+}
+
+// MarkSent mocks base method.
+func (m *MockOutboxDAO) MarkSent(ctx context.Context, ids []int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkSent", ctx, ids)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkSent indicates an expected call of MarkSent.
+func (mr *MockOutboxDAOMockRecorder) MarkSent(ctx, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkSent", reflect.TypeOf((*MockOutboxDAO)(nil).MarkSent), ctx, ids)
+}
@@ -129,6 +129,21 @@ func (mr *MockUserDAOMockRecorder) Insert(ctx, u any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Insert", reflect.TypeOf((*MockUserDAO)(nil).Insert), ctx, u)
 }
 
+// ListAfter mocks base method.
+func (m *MockUserDAO) ListAfter(ctx context.Context, minId int64, limit int) ([]dao.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAfter", ctx, minId, limit)
+	ret0, _ := ret[0].([]dao.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAfter indicates an expected call of ListAfter.
+func (mr *MockUserDAOMockRecorder) ListAfter(ctx, minId, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAfter", reflect.TypeOf((*MockUserDAO)(nil).ListAfter), ctx, minId, limit)
+}
+
 // UpdateById mocks base method.
 func (m *MockUserDAO) UpdateById(ctx context.Context, entity dao.User) error {
 	m.ctrl.T.Helper()
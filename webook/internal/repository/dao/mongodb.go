@@ -15,33 +15,93 @@ type ArticleMongoDBDAO struct {
 	node    *snowflake.Node
 	col     *mongo.Collection
 	liveCol *mongo.Collection
+	// MongoDB 和 MySQL 之间没有事务，只能尽力而为
+	outbox OutboxDAO
 }
 
 func (m *ArticleMongoDBDAO) ListPub(ctx context.Context, start time.Time, offset int, limit int) ([]PublishedArticle, error) {
-	//TODO implement me
-	panic("implement me")
+	const ArticleStatusPublished = 2
+	filter := bson.D{
+		bson.E{Key: "utime", Value: bson.D{bson.E{Key: "$lt", Value: start.UnixMilli()}}},
+		bson.E{Key: "status", Value: ArticleStatusPublished},
+	}
+	// 命中 status + utime 的组合索引
+	opts := options.Find().
+		SetSort(bson.D{bson.E{Key: "utime", Value: -1}}).
+		SetSkip(int64(offset)).
+		SetLimit(int64(limit))
+	cursor, err := m.liveCol.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	var res []PublishedArticle
+	err = cursor.All(ctx, &res)
+	return res, err
+}
+
+func (m *ArticleMongoDBDAO) ListPubByAuthor(ctx context.Context, uid int64,
+	start time.Time, limit int) ([]PublishedArticle, error) {
+	const ArticleStatusPublished = 2
+	filter := bson.D{
+		bson.E{Key: "author_id", Value: uid},
+		bson.E{Key: "utime", Value: bson.D{bson.E{Key: "$lt", Value: start.UnixMilli()}}},
+		bson.E{Key: "status", Value: ArticleStatusPublished},
+	}
+	// 命中 author_id + utime 的组合索引
+	opts := options.Find().
+		SetSort(bson.D{bson.E{Key: "utime", Value: -1}}).
+		SetLimit(int64(limit))
+	cursor, err := m.liveCol.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	var res []PublishedArticle
+	err = cursor.All(ctx, &res)
+	return res, err
 }
 
 func (m *ArticleMongoDBDAO) GetByAuthor(ctx context.Context, uid int64, offset int, limit int) ([]Article, error) {
-	//TODO implement me
-	panic("implement me")
+	filter := bson.D{bson.E{Key: "author_id", Value: uid}}
+	// 命中 author_id + utime 的组合索引
+	opts := options.Find().
+		SetSort(bson.D{bson.E{Key: "utime", Value: -1}}).
+		SetSkip(int64(offset)).
+		SetLimit(int64(limit))
+	cursor, err := m.col.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	var res []Article
+	err = cursor.All(ctx, &res)
+	return res, err
 }
 
 func (m *ArticleMongoDBDAO) GetById(ctx context.Context, id int64) (Article, error) {
-	//TODO implement me
-	panic("implement me")
+	var res Article
+	err := m.col.FindOne(ctx, bson.D{bson.E{Key: "id", Value: id}}).Decode(&res)
+	return res, m.convertErr(err)
 }
 
 func (m *ArticleMongoDBDAO) GetPubById(ctx context.Context, id int64) (PublishedArticle, error) {
-	//TODO implement me
-	panic("implement me")
+	var res PublishedArticle
+	err := m.liveCol.FindOne(ctx, bson.D{bson.E{Key: "id", Value: id}}).Decode(&res)
+	return res, m.convertErr(err)
+}
+
+// convertErr 和 GORM 的实现保持一致，上层只需要认 ErrRecordNotFound
+func (m *ArticleMongoDBDAO) convertErr(err error) error {
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return ErrRecordNotFound
+	}
+	return err
 }
 
 var _ ArticleDAO = &ArticleMongoDBDAO{}
 
-func NewArticleMongoDBDAO(mdb *mongo.Database, node *snowflake.Node) *ArticleMongoDBDAO {
+func NewArticleMongoDBDAO(mdb *mongo.Database, node *snowflake.Node, outbox OutboxDAO) *ArticleMongoDBDAO {
 	return &ArticleMongoDBDAO{
 		node:    node,
+		outbox:  outbox,
 		col:     mdb.Collection("articles"),
 		liveCol: mdb.Collection("published_articles"),
 	}
@@ -106,7 +166,18 @@ func (m *ArticleMongoDBDAO) Sync(ctx context.Context, art Article) (int64, error
 
 	_, err = m.liveCol.UpdateOne(ctx, filter, set,
 		options.Update().SetUpsert(true))
-	return id, err
+	if err != nil {
+		return id, err
+	}
+	return id, m.insertOutbox(ctx, id, art.AuthorId, domain.ArticleStatus(art.Status))
+}
+
+// insertOutbox 文章已经写进去了才写 outbox，这一步失败了事件就丢了
+func (m *ArticleMongoDBDAO) insertOutbox(ctx context.Context,
+	id int64, uid int64, status domain.ArticleStatus) error {
+	return insertArticleOutbox(func(evt OutboxEvent) error {
+		return m.outbox.Insert(ctx, evt)
+	}, id, uid, status)
 }
 
 func (m *ArticleMongoDBDAO) SyncStatus(ctx context.Context, uid int64, id int64, status domain.ArticleStatus) error {
@@ -125,5 +196,8 @@ func (m *ArticleMongoDBDAO) SyncStatus(ctx context.Context, uid int64, id int64,
 		return errors.New("更新失败, ID不对或者作者不对")
 	}
 	_, err = m.liveCol.UpdateOne(ctx, filter, sets)
-	return err
+	if err != nil {
+		return err
+	}
+	return m.insertOutbox(ctx, id, uid, status)
 }
@@ -0,0 +1,107 @@
+package dao
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"time"
+)
+
+const (
+	OutboxStatusPending uint8 = iota
+	OutboxStatusSent
+)
+
+//go:generate mockgen -source=./outbox.go -package=daomocks -destination=./mocks/outbox.mock.go OutboxDAO
+type OutboxDAO interface {
+	// Insert 没有事务的场景用，比如说 MongoDB 存文章的时候
+	Insert(ctx context.Context, evt OutboxEvent) error
+	// FindPending 按照 ID 顺序找出还没发送的
+	FindPending(ctx context.Context, limit int) ([]OutboxEvent, error)
+	MarkSent(ctx context.Context, ids []int64) error
+	// DeleteSentBefore 删除 utime 之前就已经发送的，返回删除的数量
+	DeleteSentBefore(ctx context.Context, utime int64, limit int) (int64, error)
+}
+
+type GORMOutboxDAO struct {
+	db *gorm.DB
+}
+
+func NewGORMOutboxDAO(db *gorm.DB) OutboxDAO {
+	return &GORMOutboxDAO{db: db}
+}
+
+func (g *GORMOutboxDAO) Insert(ctx context.Context, evt OutboxEvent) error {
+	return insertOutbox(g.db.WithContext(ctx), evt)
+}
+
+// insertOutbox 业务数据的事务里面调用，保证两者同时成功或者同时失败
+func insertOutbox(tx *gorm.DB, evt OutboxEvent) error {
+	now := time.Now().UnixMilli()
+	evt.Status = OutboxStatusPending
+	evt.Ctime = now
+	evt.Utime = now
+	return tx.Create(&evt).Error
+}
+
+// txOutbox 在业务数据的事务里面写入 outbox
+func txOutbox(tx *gorm.DB) func(evt OutboxEvent) error {
+	return func(evt OutboxEvent) error {
+		return insertOutbox(tx, evt)
+	}
+}
+
+func (g *GORMOutboxDAO) FindPending(ctx context.Context, limit int) ([]OutboxEvent, error) {
+	var res []OutboxEvent
+	err := g.db.WithContext(ctx).
+		Where("status = ?", OutboxStatusPending).
+		Order("id ASC").
+		Limit(limit).
+		Find(&res).Error
+	return res, err
+}
+
+func (g *GORMOutboxDAO) MarkSent(ctx context.Context, ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return g.db.WithContext(ctx).Model(&OutboxEvent{}).
+		Where("id IN ?", ids).
+		Updates(map[string]any{
+			"status": OutboxStatusSent,
+			"utime":  time.Now().UnixMilli(),
+		}).Error
+}
+
+func (g *GORMOutboxDAO) DeleteSentBefore(ctx context.Context, utime int64, limit int) (int64, error) {
+	db := g.db.WithContext(ctx)
+	// MySQL 的 DELETE 不支持在子查询里面用 LIMIT，所以先查 ID
+	var ids []int64
+	err := db.Model(&OutboxEvent{}).
+		Where("status = ? AND utime < ?", OutboxStatusSent, utime).
+		Limit(limit).
+		Pluck("id", &ids).Error
+	if err != nil || len(ids) == 0 {
+		return 0, err
+	}
+	res := db.Where("id IN ?", ids).Delete(&OutboxEvent{})
+	return res.RowsAffected, res.Error
+}
+
+// OutboxEvent 和业务数据在同一个事务里面写入，再由 relay 任务投递到 Kafka
+// 这里只记录发生了什么，发到哪个 topic，消息长什么样，由 relay 决定
+type OutboxEvent struct {
+	Id int64 `gorm:"primaryKey,autoIncrement"`
+	// 业务，比如说 article
+	Biz   string `gorm:"type:varchar(128)"`
+	BizId int64
+	// 事件类型，比如说 published, withdrawn
+	Type string `gorm:"type:varchar(64)"`
+	// 触发事件的用户
+	Uid int64
+	// 找待发送的，二级索引里面带了主键，按照 ID 排序不需要额外排序
+	Status uint8 `gorm:"index"`
+	// 事件发生的时间
+	Ctime int64
+	// 清理已经发送的
+	Utime int64 `gorm:"index"`
+}
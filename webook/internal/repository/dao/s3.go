@@ -1,30 +1,116 @@
 package dao
 
 import (
-	"bytes"
 	"context"
 	"errors"
-	"github.com/aws/aws-sdk-go/service/s3"
-	"github.com/ecodeclub/ekit"
+	"github.com/ecodeclub/ekit/slice"
 	"github.com/wsqigo/basic-go/webook/internal/domain"
+	"github.com/wsqigo/basic-go/webook/pkg/logger"
+	"github.com/wsqigo/basic-go/webook/pkg/ossx"
+	"golang.org/x/sync/errgroup"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 	"strconv"
 	"time"
 )
 
+// ArticleS3DAO 线上库只保存标题之类的元数据（PublishedArticleV2），
+// 内容放在对象存储上。制作库还是和 ArticleGORMDAO 一样
 type ArticleS3DAO struct {
 	ArticleGORMDAO
-	oss *s3.S3
+	oss ossx.Store
+	l   logger.LoggerV1
 }
 
-func NewArticleS3DAO(db *gorm.DB, oss *s3.S3) *ArticleS3DAO {
+func NewArticleS3DAO(db *gorm.DB, oss ossx.Store, l logger.LoggerV1) ArticleDAO {
 	return &ArticleS3DAO{
 		ArticleGORMDAO: ArticleGORMDAO{db: db},
 		oss:            oss,
+		l:              l,
 	}
 }
 
+func (a *ArticleS3DAO) ListPub(ctx context.Context, start time.Time,
+	offset int, limit int) ([]PublishedArticle, error) {
+	var arts []PublishedArticleV2
+	const ArticleStatusPublished = 2
+	err := a.db.WithContext(ctx).
+		Where("utime < ? AND status = ?", start.UnixMilli(), ArticleStatusPublished).
+		Order("utime DESC").
+		Offset(offset).Limit(limit).
+		Find(&arts).Error
+	if err != nil {
+		return nil, err
+	}
+	return a.withContent(ctx, arts)
+}
+
+func (a *ArticleS3DAO) ListPubByAuthor(ctx context.Context, uid int64,
+	start time.Time, limit int) ([]PublishedArticle, error) {
+	var arts []PublishedArticleV2
+	const ArticleStatusPublished = 2
+	err := a.db.WithContext(ctx).
+		Where("author_id = ? AND utime < ? AND status = ?",
+			uid, start.UnixMilli(), ArticleStatusPublished).
+		Order("utime DESC").
+		Limit(limit).
+		Find(&arts).Error
+	if err != nil {
+		return nil, err
+	}
+	return a.withContent(ctx, arts)
+}
+
+// withContent 并发去对象存储上拿内容，但是不能一下子打太多请求过去
+// 某一篇文章的内容不在对象存储上，只记录日志，内容留空，不影响同一批的其它文章
+func (a *ArticleS3DAO) withContent(ctx context.Context,
+	arts []PublishedArticleV2) ([]PublishedArticle, error) {
+	res := slice.Map(arts, func(idx int, src PublishedArticleV2) PublishedArticle {
+		return src.toPublishedArticle()
+	})
+	// 有一个失败了，其它还没开始的就不用再去拿了
+	eg, ctx := errgroup.WithContext(ctx)
+	eg.SetLimit(10)
+	for i := range res {
+		i := i
+		eg.Go(func() error {
+			content, er := a.oss.Get(ctx, a.key(res[i].Id))
+			if er == ossx.ErrObjectNotFound {
+				a.l.Error("对象存储上没有文章内容",
+					logger.Int64("aid", res[i].Id), logger.Error(er))
+				return nil
+			}
+			if er != nil {
+				return er
+			}
+			res[i].Content = string(content)
+			return nil
+		})
+	}
+	return res, eg.Wait()
+}
+
+func (a *ArticleS3DAO) GetPubById(ctx context.Context, id int64) (PublishedArticle, error) {
+	var art PublishedArticleV2
+	err := a.db.WithContext(ctx).
+		Where("id = ?", id).First(&art).Error
+	if err != nil {
+		return PublishedArticle{}, err
+	}
+	res := art.toPublishedArticle()
+	const ArticleStatusPublished = 2
+	// 只有已发表的文章，对象存储上才有内容
+	if art.Status != ArticleStatusPublished {
+		return res, nil
+	}
+	content, err := a.oss.Get(ctx, a.key(id))
+	if err != nil {
+		return PublishedArticle{}, err
+	}
+	res.Content = string(content)
+	return res, nil
+}
+
 func (a *ArticleS3DAO) Sync(ctx context.Context, art Article) (int64, error) {
 	id := art.Id
 	err := a.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
@@ -50,7 +136,7 @@ func (a *ArticleS3DAO) Sync(ctx context.Context, art Article) (int64, error) {
 			Utime:    now,
 			Status:   art.Status,
 		}
-		return tx.Clauses(clause.OnConflict{
+		err = tx.Clauses(clause.OnConflict{
 			// 对MySQL不起效，但是可以兼容别的方言
 			// INSERT xxx ON DUPLICATE KEY SET `title`=?
 			// 别的方言：
@@ -65,17 +151,17 @@ func (a *ArticleS3DAO) Sync(ctx context.Context, art Article) (int64, error) {
 				"status": pubArt.Status,
 			}),
 		}).Create(&pubArt).Error
+		if err != nil {
+			return err
+		}
+		// 内容是之后才写到 OSS 上的，消费者读到的时候可能还没写完，要能容忍
+		return insertArticleOutbox(txOutbox(tx), id, art.AuthorId, domain.ArticleStatus(art.Status))
 	})
 	if err != nil {
 		return 0, err
 	}
 	// 最后同步到 OSS 上，但是只同步了 Content
-	_, err = a.oss.PutObjectWithContext(ctx, &s3.PutObjectInput{
-		Bucket:      ekit.ToPtr[string]("weibook"),
-		Key:         ekit.ToPtr[string](strconv.FormatInt(art.Id, 10)),
-		Body:        bytes.NewReader([]byte(art.Content)),
-		ContentType: ekit.ToPtr[string]("text-plain;charset=utf-8"),
-	})
+	err = a.oss.Put(ctx, a.key(art.Id), []byte(art.Content))
 	return id, err
 }
 
@@ -94,26 +180,31 @@ func (a *ArticleS3DAO) SyncStatus(ctx context.Context, uid int64, id int64, stat
 		if res.RowsAffected == 0 {
 			return errors.New("更新失败, ID不对或者作者不对")
 		}
-		return tx.Model(&PublishedArticleV2{}).
+		err := tx.Model(&PublishedArticleV2{}).
 			Where("id = ?", id).
 			Updates(map[string]any{
 				"utime":  now,
 				"status": status,
 			}).Error
+		if err != nil {
+			return err
+		}
+		return insertArticleOutbox(txOutbox(tx), id, uid, status)
 	})
 	if err != nil {
 		return err
 	}
 	const statusPrivate = 3
 	if status == statusPrivate {
-		_, err = a.oss.DeleteObjectWithContext(ctx, &s3.DeleteObjectInput{
-			Bucket: ekit.ToPtr[string]("weibook"),
-			Key:    ekit.ToPtr[string](strconv.FormatInt(id, 10)),
-		})
+		err = a.oss.Delete(ctx, a.key(id))
 	}
 	return err
 }
 
+func (a *ArticleS3DAO) key(id int64) string {
+	return strconv.FormatInt(id, 10)
+}
+
 type PublishedArticleV2 struct {
 	Id    int64  `gorm:"primaryKey,autoIncrement" bson:"id,omitempty"`
 	Title string `gorm:"type=varchar(4096)" bson:"title,omitempty"`
@@ -124,3 +215,14 @@ type PublishedArticleV2 struct {
 	// 更新时间
 	Utime int64 `bson:"utime,omitempty"`
 }
+
+func (p PublishedArticleV2) toPublishedArticle() PublishedArticle {
+	return PublishedArticle{
+		Id:       p.Id,
+		Title:    p.Title,
+		AuthorId: p.AuthorId,
+		Status:   p.Status,
+		Ctime:    p.Ctime,
+		Utime:    p.Utime,
+	}
+}
@@ -0,0 +1,142 @@
+package dao
+
+import (
+	"context"
+	"database/sql"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wsqigo/basic-go/webook/pkg/logger"
+	"github.com/wsqigo/basic-go/webook/pkg/ossx"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"testing"
+	"time"
+)
+
+func TestArticleS3DAO_GetPubById(t *testing.T) {
+	columns := []string{"id", "title", "author_id", "status", "ctime", "utime"}
+	testCases := []struct {
+		name   string
+		mock   func(t *testing.T) *sql.DB
+		before func(t *testing.T, store ossx.Store)
+
+		wantArt PublishedArticle
+		wantErr error
+	}{
+		{
+			name: "已发表，内容从对象存储拿",
+			mock: func(t *testing.T) *sql.DB {
+				db, mock, err := sqlmock.New()
+				require.NoError(t, err)
+				mock.ExpectQuery("SELECT .*").WillReturnRows(
+					sqlmock.NewRows(columns).AddRow(1, "我的标题", 123, 2, 100, 200))
+				return db
+			},
+			before: func(t *testing.T, store ossx.Store) {
+				err := store.Put(context.Background(), "1", []byte("我的内容"))
+				require.NoError(t, err)
+			},
+			wantArt: PublishedArticle{
+				Id:       1,
+				Title:    "我的标题",
+				Content:  "我的内容",
+				AuthorId: 123,
+				Status:   2,
+				Ctime:    100,
+				Utime:    200,
+			},
+		},
+		{
+			name: "仅自己可见，不读对象存储",
+			mock: func(t *testing.T) *sql.DB {
+				db, mock, err := sqlmock.New()
+				require.NoError(t, err)
+				mock.ExpectQuery("SELECT .*").WillReturnRows(
+					sqlmock.NewRows(columns).AddRow(1, "我的标题", 123, 3, 100, 200))
+				return db
+			},
+			before: func(t *testing.T, store ossx.Store) {},
+			wantArt: PublishedArticle{
+				Id:       1,
+				Title:    "我的标题",
+				AuthorId: 123,
+				Status:   3,
+				Ctime:    100,
+				Utime:    200,
+			},
+		},
+		{
+			name: "文章不存在",
+			mock: func(t *testing.T) *sql.DB {
+				db, mock, err := sqlmock.New()
+				require.NoError(t, err)
+				mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows(columns))
+				return db
+			},
+			before:  func(t *testing.T, store ossx.Store) {},
+			wantErr: ErrRecordNotFound,
+		},
+		{
+			name: "对象存储上没有内容",
+			mock: func(t *testing.T) *sql.DB {
+				db, mock, err := sqlmock.New()
+				require.NoError(t, err)
+				mock.ExpectQuery("SELECT .*").WillReturnRows(
+					sqlmock.NewRows(columns).AddRow(1, "我的标题", 123, 2, 100, 200))
+				return db
+			},
+			before:  func(t *testing.T, store ossx.Store) {},
+			wantErr: ossx.ErrObjectNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			store, err := ossx.NewLocalStore(t.TempDir())
+			require.NoError(t, err)
+			tc.before(t, store)
+			dao := NewArticleS3DAO(newMockGORM(t, tc.mock(t)), store, logger.NewNopLogger())
+			art, err := dao.GetPubById(context.Background(), 1)
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantArt, art)
+		})
+	}
+}
+
+func TestArticleS3DAO_ListPub(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	mock.ExpectQuery("SELECT .* ORDER BY utime DESC.*").WillReturnRows(
+		sqlmock.NewRows([]string{"id", "title", "author_id", "status", "ctime", "utime"}).
+			AddRow(3, "标题3", 123, 2, 100, 400).
+			AddRow(2, "标题2", 123, 2, 100, 300).
+			AddRow(1, "标题1", 123, 2, 100, 200))
+	store, err := ossx.NewLocalStore(t.TempDir())
+	require.NoError(t, err)
+	ctx := context.Background()
+	// 3 的内容不在对象存储上，内容留空，不影响其它文章
+	require.NoError(t, store.Put(ctx, "1", []byte("内容1")))
+	require.NoError(t, store.Put(ctx, "2", []byte("内容2")))
+
+	dao := NewArticleS3DAO(newMockGORM(t, sqlDB), store, logger.NewNopLogger())
+	arts, err := dao.ListPub(ctx, time.Now(), 0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []PublishedArticle{
+		{Id: 3, Title: "标题3", AuthorId: 123, Status: 2, Ctime: 100, Utime: 400},
+		{Id: 2, Title: "标题2", Content: "内容2", AuthorId: 123, Status: 2, Ctime: 100, Utime: 300},
+		{Id: 1, Title: "标题1", Content: "内容1", AuthorId: 123, Status: 2, Ctime: 100, Utime: 200},
+	}, arts)
+}
+
+func newMockGORM(t *testing.T, sqlDB *sql.DB) *gorm.DB {
+	db, err := gorm.Open(mysql.New(mysql.Config{
+		Conn:                      sqlDB,
+		SkipInitializeWithVersion: true,
+	}), &gorm.Config{
+		DisableAutomaticPing:   true,
+		SkipDefaultTransaction: true,
+	})
+	require.NoError(t, err)
+	return db
+}
@@ -23,6 +23,8 @@ type UserDAO interface {
 	FindByPhone(ctx context.Context, phone string) (User, error)
 	FindByWechat(ctx context.Context, openId string) (User, error)
 	FindByDDing(ctx context.Context, openId string) (User, error)
+	// ListAfter 按照 ID 升序遍历用户，minId 为 0 表示从头开始
+	ListAfter(ctx context.Context, minId int64, limit int) ([]User, error)
 }
 
 type GORMUserDAO struct {
@@ -84,6 +86,13 @@ func (dao *GORMUserDAO) FindById(ctx context.Context, uid int64) (User, error) {
 	return u, err
 }
 
+func (dao *GORMUserDAO) ListAfter(ctx context.Context, minId int64, limit int) ([]User, error) {
+	var res []User
+	err := dao.db.WithContext(ctx).Where("id > ?", minId).
+		Order("id ASC").Limit(limit).Find(&res).Error
+	return res, err
+}
+
 func (dao *GORMUserDAO) FindByPhone(ctx context.Context, phone string) (User, error) {
 	var u User
 	err := dao.db.WithContext(ctx).Where("phone = ?", phone).First(&u).Error
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"context"
+	"github.com/wsqigo/basic-go/webook/internal/domain"
+	"github.com/wsqigo/basic-go/webook/internal/repository/cache"
+	"time"
+)
+
+//go:generate mockgen -source=./feed.go -package=repomocks -destination=./mocks/feed.mock.go FeedRepository
+type FeedRepository interface {
+	AddToInboxes(ctx context.Context, uids []int64, aid int64, ctime time.Time) error
+	Inbox(ctx context.Context, uid int64, maxTime time.Time, limit int) ([]domain.FeedItem, error)
+	MarkPullAuthor(ctx context.Context, uid int64) error
+	FilterPullAuthors(ctx context.Context, uids []int64) ([]int64, error)
+}
+
+// CachedFeedRepository 收件箱只放在 Redis 上，丢了也没关系，
+// 最多就是 feed 流里面少了一些文章
+type CachedFeedRepository struct {
+	cache cache.FeedCache
+}
+
+func NewCachedFeedRepository(cache cache.FeedCache) FeedRepository {
+	return &CachedFeedRepository{
+		cache: cache,
+	}
+}
+
+func (c *CachedFeedRepository) AddToInboxes(ctx context.Context,
+	uids []int64, aid int64, ctime time.Time) error {
+	return c.cache.AddToInboxes(ctx, uids, aid, ctime)
+}
+
+func (c *CachedFeedRepository) Inbox(ctx context.Context, uid int64,
+	maxTime time.Time, limit int) ([]domain.FeedItem, error) {
+	return c.cache.Inbox(ctx, uid, maxTime, limit)
+}
+
+func (c *CachedFeedRepository) MarkPullAuthor(ctx context.Context, uid int64) error {
+	return c.cache.MarkPullAuthor(ctx, uid)
+}
+
+func (c *CachedFeedRepository) FilterPullAuthors(ctx context.Context, uids []int64) ([]int64, error) {
+	return c.cache.FilterPullAuthors(ctx, uids)
+}
@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"context"
+	"github.com/ecodeclub/ekit/slice"
+	"github.com/wsqigo/basic-go/webook/internal/domain"
+	"github.com/wsqigo/basic-go/webook/internal/repository/cache"
+	"github.com/wsqigo/basic-go/webook/internal/repository/dao"
+	"github.com/wsqigo/basic-go/webook/pkg/logger"
+	"time"
+)
+
+var (
+	ErrFollowRelationExists   = dao.ErrFollowRelationExists
+	ErrFollowRelationNotFound = dao.ErrRecordNotFound
+)
+
+//go:generate mockgen -source=./follow.go -package=repomocks -destination=./mocks/follow.mock.go FollowRepository
+type FollowRepository interface {
+	// AddFollowRelation 已经关注过了会返回 ErrFollowRelationExists
+	AddFollowRelation(ctx context.Context, f domain.FollowRelation) error
+	// InactiveFollowRelation 没有关注过会返回 ErrFollowRelationNotFound
+	InactiveFollowRelation(ctx context.Context, follower, followee int64) error
+	GetFollowee(ctx context.Context, follower, maxId int64, limit int) ([]domain.FollowRelation, error)
+	GetFollower(ctx context.Context, followee, maxId int64, limit int) ([]domain.FollowRelation, error)
+	FollowInfo(ctx context.Context, follower, followee int64) (domain.FollowRelation, error)
+	GetFollowStatics(ctx context.Context, uid int64) (domain.FollowStatics, error)
+}
+
+type CachedFollowRepository struct {
+	dao   dao.FollowRelationDAO
+	cache cache.FollowCache
+	l     logger.LoggerV1
+}
+
+func NewCachedFollowRepository(dao dao.FollowRelationDAO,
+	cache cache.FollowCache, l logger.LoggerV1) FollowRepository {
+	return &CachedFollowRepository{
+		dao:   dao,
+		cache: cache,
+		l:     l,
+	}
+}
+
+func (c *CachedFollowRepository) AddFollowRelation(ctx context.Context, f domain.FollowRelation) error {
+	err := c.dao.CreateFollowRelation(ctx, dao.FollowRelation{
+		Follower: f.Follower,
+		Followee: f.Followee,
+	})
+	if err != nil {
+		return err
+	}
+	// 计数不准确问题不大，所以缓存更新失败只记录日志
+	er := c.cache.Follow(ctx, f.Follower, f.Followee)
+	if er != nil {
+		c.l.Error("更新关注数缓存失败",
+			logger.Int64("follower", f.Follower),
+			logger.Int64("followee", f.Followee),
+			logger.Error(er))
+	}
+	return nil
+}
+
+func (c *CachedFollowRepository) InactiveFollowRelation(ctx context.Context, follower, followee int64) error {
+	err := c.dao.CancelFollowRelation(ctx, follower, followee)
+	if err != nil {
+		return err
+	}
+	er := c.cache.CancelFollow(ctx, follower, followee)
+	if er != nil {
+		c.l.Error("更新关注数缓存失败",
+			logger.Int64("follower", follower),
+			logger.Int64("followee", followee),
+			logger.Error(er))
+	}
+	return nil
+}
+
+func (c *CachedFollowRepository) GetFollowee(ctx context.Context,
+	follower, maxId int64, limit int) ([]domain.FollowRelation, error) {
+	rs, err := c.dao.FollowRelationList(ctx, follower, maxId, limit)
+	if err != nil {
+		return nil, err
+	}
+	return c.toDomains(rs), nil
+}
+
+func (c *CachedFollowRepository) GetFollower(ctx context.Context,
+	followee, maxId int64, limit int) ([]domain.FollowRelation, error) {
+	rs, err := c.dao.FollowerRelationList(ctx, followee, maxId, limit)
+	if err != nil {
+		return nil, err
+	}
+	return c.toDomains(rs), nil
+}
+
+func (c *CachedFollowRepository) FollowInfo(ctx context.Context,
+	follower, followee int64) (domain.FollowRelation, error) {
+	r, err := c.dao.FollowRelationDetail(ctx, follower, followee)
+	if err != nil {
+		return domain.FollowRelation{}, err
+	}
+	return c.toDomain(r), nil
+}
+
+func (c *CachedFollowRepository) GetFollowStatics(ctx context.Context, uid int64) (domain.FollowStatics, error) {
+	res, err := c.cache.StaticsInfo(ctx, uid)
+	if err == nil {
+		return res, nil
+	}
+	res.Uid = uid
+	res.Followers, err = c.dao.CntFollower(ctx, uid)
+	if err != nil {
+		return domain.FollowStatics{}, err
+	}
+	res.Followees, err = c.dao.CntFollowee(ctx, uid)
+	if err != nil {
+		return domain.FollowStatics{}, err
+	}
+	er := c.cache.SetStaticsInfo(ctx, uid, res)
+	if er != nil {
+		c.l.Error("回写关注数缓存失败",
+			logger.Int64("uid", uid),
+			logger.Error(er))
+	}
+	return res, nil
+}
+
+func (c *CachedFollowRepository) toDomains(rs []dao.FollowRelation) []domain.FollowRelation {
+	return slice.Map(rs, func(idx int, src dao.FollowRelation) domain.FollowRelation {
+		return c.toDomain(src)
+	})
+}
+
+func (c *CachedFollowRepository) toDomain(r dao.FollowRelation) domain.FollowRelation {
+	return domain.FollowRelation{
+		Id:       r.Id,
+		Follower: r.Follower,
+		Followee: r.Followee,
+		Ctime:    time.UnixMilli(r.Ctime),
+	}
+}
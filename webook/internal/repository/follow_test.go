@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/wsqigo/basic-go/webook/internal/domain"
+	"github.com/wsqigo/basic-go/webook/internal/repository/cache"
+	cachemocks "github.com/wsqigo/basic-go/webook/internal/repository/cache/mocks"
+	"github.com/wsqigo/basic-go/webook/internal/repository/dao"
+	daomocks "github.com/wsqigo/basic-go/webook/internal/repository/dao/mocks"
+	"github.com/wsqigo/basic-go/webook/pkg/logger"
+	"go.uber.org/mock/gomock"
+	"testing"
+)
+
+func TestCachedFollowRepository_GetFollowStatics(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) (dao.FollowRelationDAO, cache.FollowCache)
+
+		wantRes domain.FollowStatics
+		wantErr error
+	}{
+		{
+			name: "缓存命中",
+			mock: func(ctrl *gomock.Controller) (dao.FollowRelationDAO, cache.FollowCache) {
+				d := daomocks.NewMockFollowRelationDAO(ctrl)
+				c := cachemocks.NewMockFollowCache(ctrl)
+				c.EXPECT().StaticsInfo(gomock.Any(), int64(123)).
+					Return(domain.FollowStatics{Uid: 123, Followers: 10, Followees: 5}, nil)
+				return d, c
+			},
+			wantRes: domain.FollowStatics{Uid: 123, Followers: 10, Followees: 5},
+		},
+		{
+			name: "缓存未命中，回写缓存",
+			mock: func(ctrl *gomock.Controller) (dao.FollowRelationDAO, cache.FollowCache) {
+				d := daomocks.NewMockFollowRelationDAO(ctrl)
+				c := cachemocks.NewMockFollowCache(ctrl)
+				c.EXPECT().StaticsInfo(gomock.Any(), int64(123)).
+					Return(domain.FollowStatics{}, cache.ErrKeyNotExist)
+				d.EXPECT().CntFollower(gomock.Any(), int64(123)).Return(int64(10), nil)
+				d.EXPECT().CntFollowee(gomock.Any(), int64(123)).Return(int64(5), nil)
+				c.EXPECT().SetStaticsInfo(gomock.Any(), int64(123),
+					domain.FollowStatics{Uid: 123, Followers: 10, Followees: 5}).
+					Return(errors.New("mock redis 错误"))
+				return d, c
+			},
+			wantRes: domain.FollowStatics{Uid: 123, Followers: 10, Followees: 5},
+		},
+		{
+			name: "数据库错误",
+			mock: func(ctrl *gomock.Controller) (dao.FollowRelationDAO, cache.FollowCache) {
+				d := daomocks.NewMockFollowRelationDAO(ctrl)
+				c := cachemocks.NewMockFollowCache(ctrl)
+				c.EXPECT().StaticsInfo(gomock.Any(), int64(123)).
+					Return(domain.FollowStatics{}, cache.ErrKeyNotExist)
+				d.EXPECT().CntFollower(gomock.Any(), int64(123)).
+					Return(int64(0), errors.New("mock db 错误"))
+				return d, c
+			},
+			wantErr: errors.New("mock db 错误"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			d, c := tc.mock(ctrl)
+			repo := NewCachedFollowRepository(d, c, logger.NewNopLogger())
+			res, err := repo.GetFollowStatics(context.Background(), 123)
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantRes, res)
+		})
+	}
+}
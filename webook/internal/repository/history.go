@@ -2,9 +2,83 @@ package repository
 
 import (
 	"context"
+	"github.com/ecodeclub/ekit/slice"
 	"github.com/wsqigo/basic-go/webook/internal/domain"
+	"github.com/wsqigo/basic-go/webook/internal/repository/dao"
+	"time"
 )
 
+//go:generate mockgen -source=./history.go -package=repomocks -destination=./mocks/history.mock.go HistoryRecordRepository
 type HistoryRecordRepository interface {
+	// AddRecord 重复阅读只会更新阅读时间
 	AddRecord(ctx context.Context, record domain.HistoryRecord) error
+	// GetRecords 按照阅读时间倒序，maxTime 为零值表示从头开始
+	GetRecords(ctx context.Context, uid int64, maxTime time.Time, maxId int64, limit int) ([]domain.HistoryRecord, error)
+	DeleteRecords(ctx context.Context, uid int64, ids []int64) error
+	DeleteAllRecords(ctx context.Context, uid int64) error
+	// DeleteExpired 删除阅读时间早于 before 的记录，一次最多删除 limit 条
+	DeleteExpired(ctx context.Context, before time.Time, limit int) (int64, error)
+}
+
+type DBHistoryRecordRepository struct {
+	dao dao.HistoryRecordDAO
+}
+
+func NewDBHistoryRecordRepository(dao dao.HistoryRecordDAO) HistoryRecordRepository {
+	return &DBHistoryRecordRepository{dao: dao}
+}
+
+func (d *DBHistoryRecordRepository) AddRecord(ctx context.Context, record domain.HistoryRecord) error {
+	return d.dao.Upsert(ctx, d.toEntity(record))
+}
+
+func (d *DBHistoryRecordRepository) GetRecords(ctx context.Context,
+	uid int64, maxTime time.Time, maxId int64, limit int) ([]domain.HistoryRecord, error) {
+	var maxUtime int64
+	if !maxTime.IsZero() {
+		maxUtime = maxTime.UnixMilli()
+	}
+	rs, err := d.dao.List(ctx, uid, maxUtime, maxId, limit)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(rs, func(idx int, src dao.HistoryRecord) domain.HistoryRecord {
+		return d.toDomain(src)
+	}), nil
+}
+
+func (d *DBHistoryRecordRepository) DeleteRecords(ctx context.Context, uid int64, ids []int64) error {
+	return d.dao.Delete(ctx, uid, ids)
+}
+
+func (d *DBHistoryRecordRepository) DeleteAllRecords(ctx context.Context, uid int64) error {
+	return d.dao.DeleteAll(ctx, uid)
+}
+
+func (d *DBHistoryRecordRepository) DeleteExpired(ctx context.Context, before time.Time, limit int) (int64, error) {
+	return d.dao.DeleteBefore(ctx, before.UnixMilli(), limit)
+}
+
+func (d *DBHistoryRecordRepository) toEntity(r domain.HistoryRecord) dao.HistoryRecord {
+	var utime int64
+	if !r.ReadTime.IsZero() {
+		utime = r.ReadTime.UnixMilli()
+	}
+	return dao.HistoryRecord{
+		Id:    r.Id,
+		Uid:   r.Uid,
+		Biz:   r.Biz,
+		BizId: r.BizId,
+		Utime: utime,
+	}
+}
+
+func (d *DBHistoryRecordRepository) toDomain(r dao.HistoryRecord) domain.HistoryRecord {
+	return domain.HistoryRecord{
+		Id:       r.Id,
+		Uid:      r.Uid,
+		Biz:      r.Biz,
+		BizId:    r.BizId,
+		ReadTime: time.UnixMilli(r.Utime),
+	}
 }
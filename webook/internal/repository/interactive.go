@@ -14,6 +14,7 @@ type InteractiveRepository interface {
 	IncrLike(ctx context.Context, biz string, id int64, uid int64) error
 	DecrLike(ctx context.Context, biz string, id int64, uid int64) error
 	AddCollectionItem(ctx context.Context, biz string, id int64, cid int64, uid int64) error
+	DeleteCollectionItem(ctx context.Context, biz string, id int64, uid int64) error
 	Get(ctx context.Context, biz string, id int64) (domain.Interactive, error)
 	Liked(ctx context.Context, biz string, id int64, uid int64) (bool, error)
 	Collected(ctx context.Context, biz string, id int64, uid int64) (bool, error)
@@ -61,6 +62,19 @@ func (c *CachedInteractiveRepository) AddCollectionItem(ctx context.Context,
 	return c.cache.IncrCollectCntIfPresent(ctx, biz, id)
 }
 
+func (c *CachedInteractiveRepository) DeleteCollectionItem(ctx context.Context,
+	biz string, id int64, uid int64) error {
+	err := c.dao.DeleteCollectionBiz(ctx, biz, id, uid)
+	if err == dao.ErrRecordNotFound {
+		// 本来就没有收藏
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return c.cache.DecrCollectCntIfPresent(ctx, biz, id)
+}
+
 func (c *CachedInteractiveRepository) Get(ctx context.Context, biz string, id int64) (domain.Interactive, error) {
 	inter, err := c.cache.Get(ctx, biz, id)
 	if err == nil {
@@ -180,5 +194,6 @@ func (c *CachedInteractiveRepository) toDomain(ie dao.Interactive) domain.Intera
 		ReadCnt:    ie.ReadCnt,
 		LikeCnt:    ie.LikeCnt,
 		CollectCnt: ie.CollectCnt,
+		CommentCnt: ie.CommentCnt,
 	}
 }
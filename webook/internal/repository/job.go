@@ -2,16 +2,37 @@ package repository
 
 import (
 	"context"
+	"github.com/ecodeclub/ekit/slice"
 	"github.com/wsqigo/basic-go/webook/internal/domain"
 	"github.com/wsqigo/basic-go/webook/internal/repository/dao"
 	"time"
 )
 
+var (
+	ErrJobNameExists = dao.ErrJobNameExists
+	ErrJobNotFound   = dao.ErrRecordNotFound
+	ErrJobLeaseLost  = dao.ErrJobLeaseLost
+)
+
+//go:generate mockgen -source=./job.go -package=repomocks -destination=./mocks/job.mock.go CronJobRepository
 type CronJobRepository interface {
-	Preempt(ctx context.Context) (domain.Job, error)
-	Release(ctx context.Context, jid int64) error
-	UpdateUtime(ctx context.Context, id int64) error
+	// Preempt 续约超过 leaseTimeout 的任务也会被抢占
+	Preempt(ctx context.Context, leaseTimeout time.Duration) (domain.Job, error)
+	Release(ctx context.Context, jid int64, version int) error
+	// UpdateUtime 续约，任务已经被别的节点抢走了会返回 ErrJobLeaseLost
+	UpdateUtime(ctx context.Context, id int64, version int) error
 	UpdateNextTime(ctx context.Context, id int64, time time.Time) error
+	UpdateLastRun(ctx context.Context, id int64, startTime time.Time, errMsg string) error
+
+	// Create 名字重复会返回 ErrJobNameExists
+	Create(ctx context.Context, j domain.Job) (int64, error)
+	// Update 不存在会返回 ErrJobNotFound
+	Update(ctx context.Context, j domain.Job) error
+	Pause(ctx context.Context, id int64) error
+	Resume(ctx context.Context, id int64, nextTime time.Time) error
+	Delete(ctx context.Context, id int64) error
+	FindById(ctx context.Context, id int64) (domain.Job, error)
+	List(ctx context.Context, offset int, limit int) ([]domain.Job, error)
 }
 
 type PreemptJobRepository struct {
@@ -22,24 +43,107 @@ func NewPreemptJobRepository(dao dao.JobDAO) CronJobRepository {
 	return &PreemptJobRepository{dao: dao}
 }
 
-func (p *PreemptJobRepository) Preempt(ctx context.Context) (domain.Job, error) {
-	j, err := p.dao.Preempt(ctx)
+func (p *PreemptJobRepository) Preempt(ctx context.Context, leaseTimeout time.Duration) (domain.Job, error) {
+	j, err := p.dao.Preempt(ctx, leaseTimeout)
 	return domain.Job{
-		Id:         j.Id,
-		Name:       j.Name,
-		Expression: j.Expression,
-		Executor:   j.Executor,
+		Id:            j.Id,
+		Name:          j.Name,
+		Expression:    j.Expression,
+		Executor:      j.Executor,
+		Cfg:           j.Cfg,
+		RetryMax:      j.RetryMax,
+		RetryInterval: time.Duration(j.RetryInterval) * time.Millisecond,
+		Version:       j.Version,
 	}, err
 }
 
-func (p *PreemptJobRepository) Release(ctx context.Context, jid int64) error {
-	return p.dao.Release(ctx, jid)
+func (p *PreemptJobRepository) Release(ctx context.Context, jid int64, version int) error {
+	return p.dao.Release(ctx, jid, version)
 }
 
-func (p *PreemptJobRepository) UpdateUtime(ctx context.Context, id int64) error {
-	return p.dao.UpdateUtime(ctx, id)
+func (p *PreemptJobRepository) UpdateUtime(ctx context.Context, id int64, version int) error {
+	return p.dao.UpdateUtime(ctx, id, version)
 }
 
 func (p *PreemptJobRepository) UpdateNextTime(ctx context.Context, id int64, t time.Time) error {
 	return p.dao.UpdateNextTime(ctx, id, t)
 }
+
+func (p *PreemptJobRepository) UpdateLastRun(ctx context.Context, id int64,
+	startTime time.Time, errMsg string) error {
+	return p.dao.UpdateLastRun(ctx, id, startTime.UnixMilli(), errMsg)
+}
+
+func (p *PreemptJobRepository) Create(ctx context.Context, j domain.Job) (int64, error) {
+	return p.dao.Insert(ctx, p.toEntity(j))
+}
+
+func (p *PreemptJobRepository) Update(ctx context.Context, j domain.Job) error {
+	return p.dao.Update(ctx, p.toEntity(j))
+}
+
+func (p *PreemptJobRepository) Pause(ctx context.Context, id int64) error {
+	return p.dao.Pause(ctx, id)
+}
+
+func (p *PreemptJobRepository) Resume(ctx context.Context, id int64, nextTime time.Time) error {
+	return p.dao.Resume(ctx, id, nextTime.UnixMilli())
+}
+
+func (p *PreemptJobRepository) Delete(ctx context.Context, id int64) error {
+	return p.dao.Delete(ctx, id)
+}
+
+func (p *PreemptJobRepository) FindById(ctx context.Context, id int64) (domain.Job, error) {
+	j, err := p.dao.FindById(ctx, id)
+	if err != nil {
+		return domain.Job{}, err
+	}
+	return p.toDomain(j), nil
+}
+
+func (p *PreemptJobRepository) List(ctx context.Context, offset int, limit int) ([]domain.Job, error) {
+	js, err := p.dao.List(ctx, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(js, func(idx int, src dao.Job) domain.Job {
+		return p.toDomain(src)
+	}), nil
+}
+
+func (p *PreemptJobRepository) toEntity(j domain.Job) dao.Job {
+	return dao.Job{
+		Id:            j.Id,
+		Name:          j.Name,
+		Executor:      j.Executor,
+		Expression:    j.Expression,
+		Cfg:           j.Cfg,
+		RetryMax:      j.RetryMax,
+		RetryInterval: j.RetryInterval.Milliseconds(),
+		Status:        int(j.Status),
+		NextTime:      j.NextRunTime.UnixMilli(),
+	}
+}
+
+func (p *PreemptJobRepository) toDomain(j dao.Job) domain.Job {
+	res := domain.Job{
+		Id:            j.Id,
+		Name:          j.Name,
+		Expression:    j.Expression,
+		Executor:      j.Executor,
+		Cfg:           j.Cfg,
+		RetryMax:      j.RetryMax,
+		RetryInterval: time.Duration(j.RetryInterval) * time.Millisecond,
+		Status:        domain.JobStatus(j.Status),
+		Version:       j.Version,
+		NextRunTime:   time.UnixMilli(j.NextTime),
+		LastRunErr:    j.LastRunErr,
+		Ctime:         time.UnixMilli(j.Ctime),
+		Utime:         time.UnixMilli(j.Utime),
+	}
+	if j.LastRunTime > 0 {
+		res.LastRunTime = time.UnixMilli(j.LastRunTime)
+	}
+	return res
+}
@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"github.com/ecodeclub/ekit/slice"
+	"github.com/wsqigo/basic-go/webook/internal/domain"
+	"github.com/wsqigo/basic-go/webook/internal/repository/dao"
+	"time"
+)
+
+//go:generate mockgen -source=./job_execution.go -package=repomocks -destination=./mocks/job_execution.mock.go JobExecutionRepository
+type JobExecutionRepository interface {
+	// Create 记录开始执行，返回执行记录的 ID
+	Create(ctx context.Context, e domain.JobExecution) (int64, error)
+	// Finish 记录执行结果
+	Finish(ctx context.Context, e domain.JobExecution) error
+	FindByJob(ctx context.Context, jid int64, maxId int64, limit int) ([]domain.JobExecution, error)
+}
+
+type DBJobExecutionRepository struct {
+	dao dao.JobExecutionDAO
+}
+
+func NewDBJobExecutionRepository(dao dao.JobExecutionDAO) JobExecutionRepository {
+	return &DBJobExecutionRepository{dao: dao}
+}
+
+func (d *DBJobExecutionRepository) Create(ctx context.Context, e domain.JobExecution) (int64, error) {
+	return d.dao.Insert(ctx, dao.JobExecution{
+		Jid:       e.Jid,
+		Executor:  e.Executor,
+		NodeId:    e.NodeId,
+		StartTime: e.StartTime.UnixMilli(),
+		Status:    e.Status.ToUint8(),
+	})
+}
+
+func (d *DBJobExecutionRepository) Finish(ctx context.Context, e domain.JobExecution) error {
+	return d.dao.Finish(ctx, e.Id, e.EndTime.UnixMilli(), e.Status.ToUint8(), e.ErrMsg)
+}
+
+func (d *DBJobExecutionRepository) FindByJob(ctx context.Context,
+	jid int64, maxId int64, limit int) ([]domain.JobExecution, error) {
+	es, err := d.dao.ListByJob(ctx, jid, maxId, limit)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(es, func(idx int, src dao.JobExecution) domain.JobExecution {
+		return d.toDomain(src)
+	}), nil
+}
+
+func (d *DBJobExecutionRepository) toDomain(e dao.JobExecution) domain.JobExecution {
+	res := domain.JobExecution{
+		Id:        e.Id,
+		Jid:       e.Jid,
+		Executor:  e.Executor,
+		NodeId:    e.NodeId,
+		StartTime: time.UnixMilli(e.StartTime),
+		Status:    domain.JobExecutionStatus(e.Status),
+		ErrMsg:    e.ErrMsg,
+	}
+	if e.EndTime > 0 {
+		res.EndTime = time.UnixMilli(e.EndTime)
+	}
+	return res
+}
@@ -12,6 +12,7 @@ package repomocks
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	domain "github.com/wsqigo/basic-go/webook/internal/domain"
 	gomock "go.uber.org/mock/gomock"
@@ -100,6 +101,36 @@ func (mr *MockArticleRepositoryMockRecorder) GetPubByID(ctx, id any) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPubByID", reflect.TypeOf((*MockArticleRepository)(nil).GetPubByID), ctx, id)
 }
 
+// ListPub mocks base method.
+func (m *MockArticleRepository) ListPub(ctx context.Context, start time.Time, offset, limit int) ([]domain.Article, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPub", ctx, start, offset, limit)
+	ret0, _ := ret[0].([]domain.Article)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPub indicates an expected call of ListPub.
+func (mr *MockArticleRepositoryMockRecorder) ListPub(ctx, start, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPub", reflect.TypeOf((*MockArticleRepository)(nil).ListPub), ctx, start, offset, limit)
+}
+
+// ListPubByAuthor mocks base method.
+func (m *MockArticleRepository) ListPubByAuthor(ctx context.Context, uid int64, start time.Time, limit int) ([]domain.Article, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPubByAuthor", ctx, uid, start, limit)
+	ret0, _ := ret[0].([]domain.Article)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPubByAuthor indicates an expected call of ListPubByAuthor.
+func (mr *MockArticleRepositoryMockRecorder) ListPubByAuthor(ctx, uid, start, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPubByAuthor", reflect.TypeOf((*MockArticleRepository)(nil).ListPubByAuthor), ctx, uid, start, limit)
+}
+
 // Sync mocks base method.
 func (m *MockArticleRepository) Sync(ctx context.Context, art domain.Article) (int64, error) {
 	m.ctrl.T.Helper()
@@ -12,6 +12,7 @@ package repomocks
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	domain "github.com/wsqigo/basic-go/webook/internal/domain"
 	gomock "go.uber.org/mock/gomock"
@@ -54,6 +55,21 @@ func (mr *MockAsyncSmsRepositoryMockRecorder) Add(ctx, s any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockAsyncSmsRepository)(nil).Add), ctx, s)
 }
 
+// DeleteFinished mocks base method.
+func (m *MockAsyncSmsRepository) DeleteFinished(ctx context.Context, before time.Time, limit int) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteFinished", ctx, before, limit)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteFinished indicates an expected call of DeleteFinished.
+func (mr *MockAsyncSmsRepositoryMockRecorder) DeleteFinished(ctx, before, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFinished", reflect.TypeOf((*MockAsyncSmsRepository)(nil).DeleteFinished), ctx, before, limit)
+}
+
 // PreemptWaitingSMS mocks base method.
 func (m *MockAsyncSmsRepository) PreemptWaitingSMS(ctx context.Context) (domain.AsyncSms, error) {
 	m.ctrl.T.Helper()
@@ -0,0 +1,100 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./feed.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./feed.go -package=repomocks -destination=./mocks/feed.mock.go FeedRepository
+//
+
+// Package repomocks is a generated GoMock package.
+package repomocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	domain "github.com/wsqigo/basic-go/webook/internal/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockFeedRepository is a mock of FeedRepository interface.
+type MockFeedRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockFeedRepositoryMockRecorder
+}
+
+// MockFeedRepositoryMockRecorder is the mock recorder for MockFeedRepository.
+type MockFeedRepositoryMockRecorder struct {
+	mock *MockFeedRepository
+}
+
+// NewMockFeedRepository creates a new mock instance.
+func NewMockFeedRepository(ctrl *gomock.Controller) *MockFeedRepository {
+	mock := &MockFeedRepository{ctrl: ctrl}
+	mock.recorder = &MockFeedRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFeedRepository) EXPECT() *MockFeedRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AddToInboxes mocks base method.
+func (m *MockFeedRepository) AddToInboxes(ctx context.Context, uids []int64, aid int64, ctime time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddToInboxes", ctx, uids, aid, ctime)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddToInboxes indicates an expected call of AddToInboxes.
+func (mr *MockFeedRepositoryMockRecorder) AddToInboxes(ctx, uids, aid, ctime any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddToInboxes", reflect.TypeOf((*MockFeedRepository)(nil).AddToInboxes), ctx, uids, aid, ctime)
+}
+
+// FilterPullAuthors mocks base method.
+func (m *MockFeedRepository) FilterPullAuthors(ctx context.Context, uids []int64) ([]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FilterPullAuthors", ctx, uids)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FilterPullAuthors indicates an expected call of FilterPullAuthors.
+func (mr *MockFeedRepositoryMockRecorder) FilterPullAuthors(ctx, uids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FilterPullAuthors", reflect.TypeOf((*MockFeedRepository)(nil).FilterPullAuthors), ctx, uids)
+}
+
+// Inbox mocks base method.
+func (m *MockFeedRepository) Inbox(ctx context.Context, uid int64, maxTime time.Time, limit int) ([]domain.FeedItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Inbox", ctx, uid, maxTime, limit)
+	ret0, _ := ret[0].([]domain.FeedItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Inbox indicates an expected call of Inbox.
+func (mr *MockFeedRepositoryMockRecorder) Inbox(ctx, uid, maxTime, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Inbox", reflect.TypeOf((*MockFeedRepository)(nil).Inbox), ctx, uid, maxTime, limit)
+}
+
+// MarkPullAuthor mocks base method.
+func (m *MockFeedRepository) MarkPullAuthor(ctx context.Context, uid int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkPullAuthor", ctx, uid)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkPullAuthor indicates an expected call of MarkPullAuthor.
+func (mr *MockFeedRepositoryMockRecorder) MarkPullAuthor(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkPullAuthor", reflect.TypeOf((*MockFeedRepository)(nil).MarkPullAuthor), ctx, uid)
+}
@@ -0,0 +1,114 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./history.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./history.go -package=repomocks -destination=./mocks/history.mock.go HistoryRecordRepository
+//
+
+// Package repomocks is a generated GoMock package.
+package repomocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	domain "github.com/wsqigo/basic-go/webook/internal/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockHistoryRecordRepository is a mock of HistoryRecordRepository interface.
+type MockHistoryRecordRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockHistoryRecordRepositoryMockRecorder
+}
+
+// MockHistoryRecordRepositoryMockRecorder is the mock recorder for MockHistoryRecordRepository.
+type MockHistoryRecordRepositoryMockRecorder struct {
+	mock *MockHistoryRecordRepository
+}
+
+// NewMockHistoryRecordRepository creates a new mock instance.
+func NewMockHistoryRecordRepository(ctrl *gomock.Controller) *MockHistoryRecordRepository {
+	mock := &MockHistoryRecordRepository{ctrl: ctrl}
+	mock.recorder = &MockHistoryRecordRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHistoryRecordRepository) EXPECT() *MockHistoryRecordRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AddRecord mocks base method.
+func (m *MockHistoryRecordRepository) AddRecord(ctx context.Context, record domain.HistoryRecord) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddRecord", ctx, record)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddRecord indicates an expected call of AddRecord.
+func (mr *MockHistoryRecordRepositoryMockRecorder) AddRecord(ctx, record any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddRecord", reflect.TypeOf((*MockHistoryRecordRepository)(nil).AddRecord), ctx, record)
+}
+
+// DeleteAllRecords mocks base method.
+func (m *MockHistoryRecordRepository) DeleteAllRecords(ctx context.Context, uid int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAllRecords", ctx, uid)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAllRecords indicates an expected call of DeleteAllRecords.
+func (mr *MockHistoryRecordRepositoryMockRecorder) DeleteAllRecords(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAllRecords", reflect.TypeOf((*MockHistoryRecordRepository)(nil).DeleteAllRecords), ctx, uid)
+}
+
+// DeleteExpired mocks base method.
+func (m *MockHistoryRecordRepository) DeleteExpired(ctx context.Context, before time.Time, limit int) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpired", ctx, before, limit)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpired indicates an expected call of DeleteExpired.
+func (mr *MockHistoryRecordRepositoryMockRecorder) DeleteExpired(ctx, before, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpired", reflect.TypeOf((*MockHistoryRecordRepository)(nil).DeleteExpired), ctx, before, limit)
+}
+
+// DeleteRecords mocks base method.
+func (m *MockHistoryRecordRepository) DeleteRecords(ctx context.Context, uid int64, ids []int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteRecords", ctx, uid, ids)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteRecords indicates an expected call of DeleteRecords.
+func (mr *MockHistoryRecordRepositoryMockRecorder) DeleteRecords(ctx, uid, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRecords", reflect.TypeOf((*MockHistoryRecordRepository)(nil).DeleteRecords), ctx, uid, ids)
+}
+
+// GetRecords mocks base method.
+func (m *MockHistoryRecordRepository) GetRecords(ctx context.Context, uid int64, maxTime time.Time, maxId int64, limit int) ([]domain.HistoryRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRecords", ctx, uid, maxTime, maxId, limit)
+	ret0, _ := ret[0].([]domain.HistoryRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRecords indicates an expected call of GetRecords.
+func (mr *MockHistoryRecordRepositoryMockRecorder) GetRecords(ctx, uid, maxTime, maxId, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecords", reflect.TypeOf((*MockHistoryRecordRepository)(nil).GetRecords), ctx, uid, maxTime, maxId, limit)
+}
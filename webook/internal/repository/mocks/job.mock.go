@@ -0,0 +1,214 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./job.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./job.go -package=repomocks -destination=./mocks/job.mock.go CronJobRepository
+//
+
+// Package repomocks is a generated GoMock package.
+package repomocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	domain "github.com/wsqigo/basic-go/webook/internal/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockCronJobRepository is a mock of CronJobRepository interface.
+type MockCronJobRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockCronJobRepositoryMockRecorder
+}
+
+// MockCronJobRepositoryMockRecorder is the mock recorder for MockCronJobRepository.
+type MockCronJobRepositoryMockRecorder struct {
+	mock *MockCronJobRepository
+}
+
+// NewMockCronJobRepository creates a new mock instance.
+func NewMockCronJobRepository(ctrl *gomock.Controller) *MockCronJobRepository {
+	mock := &MockCronJobRepository{ctrl: ctrl}
+	mock.recorder = &MockCronJobRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCronJobRepository) EXPECT() *MockCronJobRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockCronJobRepository) Create(ctx context.Context, j domain.Job) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, j)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockCronJobRepositoryMockRecorder) Create(ctx, j any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockCronJobRepository)(nil).Create), ctx, j)
+}
+
+// Delete mocks base method.
+func (m *MockCronJobRepository) Delete(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockCronJobRepositoryMockRecorder) Delete(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockCronJobRepository)(nil).Delete), ctx, id)
+}
+
+// FindById mocks base method.
+func (m *MockCronJobRepository) FindById(ctx context.Context, id int64) (domain.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindById", ctx, id)
+	ret0, _ := ret[0].(domain.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindById indicates an expected call of FindById.
+func (mr *MockCronJobRepositoryMockRecorder) FindById(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindById", reflect.TypeOf((*MockCronJobRepository)(nil).FindById), ctx, id)
+}
+
+// List mocks base method.
+func (m *MockCronJobRepository) List(ctx context.Context, offset, limit int) ([]domain.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, offset, limit)
+	ret0, _ := ret[0].([]domain.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockCronJobRepositoryMockRecorder) List(ctx, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockCronJobRepository)(nil).List), ctx, offset, limit)
+}
+
+// Pause mocks base method.
+func (m *MockCronJobRepository) Pause(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Pause", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Pause indicates an expected call of Pause.
+func (mr *MockCronJobRepositoryMockRecorder) Pause(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Pause", reflect.TypeOf((*MockCronJobRepository)(nil).Pause), ctx, id)
+}
+
+// Preempt mocks base method.
+func (m *MockCronJobRepository) Preempt(ctx context.Context, leaseTimeout time.Duration) (domain.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Preempt", ctx, leaseTimeout)
+	ret0, _ := ret[0].(domain.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Preempt indicates an expected call of Preempt.
+func (mr *MockCronJobRepositoryMockRecorder) Preempt(ctx, leaseTimeout any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Preempt", reflect.TypeOf((*MockCronJobRepository)(nil).Preempt), ctx, leaseTimeout)
+}
+
+// Release mocks base method.
+func (m *MockCronJobRepository) Release(ctx context.Context, jid int64, version int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Release", ctx, jid, version)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Release indicates an expected call of Release.
+func (mr *MockCronJobRepositoryMockRecorder) Release(ctx, jid, version any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Release", reflect.TypeOf((*MockCronJobRepository)(nil).Release), ctx, jid, version)
+}
+
+// Resume mocks base method.
+func (m *MockCronJobRepository) Resume(ctx context.Context, id int64, nextTime time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Resume", ctx, id, nextTime)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Resume indicates an expected call of Resume.
+func (mr *MockCronJobRepositoryMockRecorder) Resume(ctx, id, nextTime any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Resume", reflect.TypeOf((*MockCronJobRepository)(nil).Resume), ctx, id, nextTime)
+}
+
+// Update mocks base method.
+func (m *MockCronJobRepository) Update(ctx context.Context, j domain.Job) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, j)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockCronJobRepositoryMockRecorder) Update(ctx, j any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockCronJobRepository)(nil).Update), ctx, j)
+}
+
+// UpdateLastRun mocks base method.
+func (m *MockCronJobRepository) UpdateLastRun(ctx context.Context, id int64, startTime time.Time, errMsg string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateLastRun", ctx, id, startTime, errMsg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateLastRun indicates an expected call of UpdateLastRun.
+func (mr *MockCronJobRepositoryMockRecorder) UpdateLastRun(ctx, id, startTime, errMsg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLastRun", reflect.TypeOf((*MockCronJobRepository)(nil).UpdateLastRun), ctx, id, startTime, errMsg)
+}
+
+// UpdateNextTime mocks base method.
+func (m *MockCronJobRepository) UpdateNextTime(ctx context.Context, id int64, time time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateNextTime", ctx, id, time)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateNextTime indicates an expected call of UpdateNextTime.
+func (mr *MockCronJobRepositoryMockRecorder) UpdateNextTime(ctx, id, time any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateNextTime", reflect.TypeOf((*MockCronJobRepository)(nil).UpdateNextTime), ctx, id, time)
+}
+
+// UpdateUtime mocks base method.
+func (m *MockCronJobRepository) UpdateUtime(ctx context.Context, id int64, version int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUtime", ctx, id, version)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateUtime indicates an expected call of UpdateUtime.
+func (mr *MockCronJobRepositoryMockRecorder) UpdateUtime(ctx, id, version any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUtime", reflect.TypeOf((*MockCronJobRepository)(nil).UpdateUtime), ctx, id, version)
+}
@@ -0,0 +1,113 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./search.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./search.go -package=repomocks -destination=./mocks/search.mock.go SearchRepository
+//
+
+// Package repomocks is a generated GoMock package.
+package repomocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/wsqigo/basic-go/webook/internal/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockSearchRepository is a mock of SearchRepository interface.
+type MockSearchRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockSearchRepositoryMockRecorder
+}
+
+// MockSearchRepositoryMockRecorder is the mock recorder for MockSearchRepository.
+type MockSearchRepositoryMockRecorder struct {
+	mock *MockSearchRepository
+}
+
+// NewMockSearchRepository creates a new mock instance.
+func NewMockSearchRepository(ctrl *gomock.Controller) *MockSearchRepository {
+	mock := &MockSearchRepository{ctrl: ctrl}
+	mock.recorder = &MockSearchRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSearchRepository) EXPECT() *MockSearchRepositoryMockRecorder {
+	return m.recorder
+}
+
+// DeleteArticle mocks base method.
+func (m *MockSearchRepository) DeleteArticle(ctx context.Context, id, version int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteArticle", ctx, id, version)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteArticle indicates an expected call of DeleteArticle.
+func (mr *MockSearchRepositoryMockRecorder) DeleteArticle(ctx, id, version any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteArticle", reflect.TypeOf((*MockSearchRepository)(nil).DeleteArticle), ctx, id, version)
+}
+
+// InputArticle mocks base method.
+func (m *MockSearchRepository) InputArticle(ctx context.Context, art domain.Article) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InputArticle", ctx, art)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InputArticle indicates an expected call of InputArticle.
+func (mr *MockSearchRepositoryMockRecorder) InputArticle(ctx, art any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InputArticle", reflect.TypeOf((*MockSearchRepository)(nil).InputArticle), ctx, art)
+}
+
+// InputUser mocks base method.
+func (m *MockSearchRepository) InputUser(ctx context.Context, u domain.User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InputUser", ctx, u)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InputUser indicates an expected call of InputUser.
+func (mr *MockSearchRepositoryMockRecorder) InputUser(ctx, u any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InputUser", reflect.TypeOf((*MockSearchRepository)(nil).InputUser), ctx, u)
+}
+
+// SearchArticle mocks base method.
+func (m *MockSearchRepository) SearchArticle(ctx context.Context, keywords string, offset, limit int) (domain.ArticleSearchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchArticle", ctx, keywords, offset, limit)
+	ret0, _ := ret[0].(domain.ArticleSearchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchArticle indicates an expected call of SearchArticle.
+func (mr *MockSearchRepositoryMockRecorder) SearchArticle(ctx, keywords, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchArticle", reflect.TypeOf((*MockSearchRepository)(nil).SearchArticle), ctx, keywords, offset, limit)
+}
+
+// SearchUser mocks base method.
+func (m *MockSearchRepository) SearchUser(ctx context.Context, keywords string, offset, limit int) (domain.UserSearchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchUser", ctx, keywords, offset, limit)
+	ret0, _ := ret[0].(domain.UserSearchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchUser indicates an expected call of SearchUser.
+func (mr *MockSearchRepositoryMockRecorder) SearchUser(ctx, keywords, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchUser", reflect.TypeOf((*MockSearchRepository)(nil).SearchUser), ctx, keywords, offset, limit)
+}
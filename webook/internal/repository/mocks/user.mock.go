@@ -129,6 +129,21 @@ func (mr *MockUserRepositoryMockRecorder) FindByWechat(ctx, openId any) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByWechat", reflect.TypeOf((*MockUserRepository)(nil).FindByWechat), ctx, openId)
 }
 
+// ListAfter mocks base method.
+func (m *MockUserRepository) ListAfter(ctx context.Context, minId int64, limit int) ([]domain.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAfter", ctx, minId, limit)
+	ret0, _ := ret[0].([]domain.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAfter indicates an expected call of ListAfter.
+func (mr *MockUserRepositoryMockRecorder) ListAfter(ctx, minId, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAfter", reflect.TypeOf((*MockUserRepository)(nil).ListAfter), ctx, minId, limit)
+}
+
 // UpdateNonZeroFields mocks base method.
 func (m *MockUserRepository) UpdateNonZeroFields(ctx context.Context, user domain.User) error {
 	m.ctrl.T.Helper()
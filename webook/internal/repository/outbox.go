@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+	"github.com/ecodeclub/ekit/slice"
+	"github.com/wsqigo/basic-go/webook/internal/domain"
+	"github.com/wsqigo/basic-go/webook/internal/repository/dao"
+	"time"
+)
+
+//go:generate mockgen -source=./outbox.go -package=repomocks -destination=./mocks/outbox.mock.go OutboxRepository
+type OutboxRepository interface {
+	// FindPending 按照写入的顺序找出还没投递的事件
+	FindPending(ctx context.Context, limit int) ([]domain.OutboxEvent, error)
+	MarkSent(ctx context.Context, ids []int64) error
+	// DeleteSentBefore 删除 before 之前就已经投递的事件，一次最多删除 limit 条
+	DeleteSentBefore(ctx context.Context, before time.Time, limit int) (int64, error)
+}
+
+type DBOutboxRepository struct {
+	dao dao.OutboxDAO
+}
+
+func NewDBOutboxRepository(dao dao.OutboxDAO) OutboxRepository {
+	return &DBOutboxRepository{dao: dao}
+}
+
+func (d *DBOutboxRepository) FindPending(ctx context.Context, limit int) ([]domain.OutboxEvent, error) {
+	evts, err := d.dao.FindPending(ctx, limit)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(evts, func(idx int, src dao.OutboxEvent) domain.OutboxEvent {
+		return domain.OutboxEvent{
+			Id:    src.Id,
+			Biz:   src.Biz,
+			BizId: src.BizId,
+			Type:  src.Type,
+			Uid:   src.Uid,
+			Ctime: time.UnixMilli(src.Ctime),
+		}
+	}), nil
+}
+
+func (d *DBOutboxRepository) MarkSent(ctx context.Context, ids []int64) error {
+	return d.dao.MarkSent(ctx, ids)
+}
+
+func (d *DBOutboxRepository) DeleteSentBefore(ctx context.Context, before time.Time, limit int) (int64, error) {
+	return d.dao.DeleteSentBefore(ctx, before.UnixMilli(), limit)
+}
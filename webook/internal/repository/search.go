@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"context"
+	"github.com/ecodeclub/ekit/slice"
+	"github.com/wsqigo/basic-go/webook/internal/domain"
+	"github.com/wsqigo/basic-go/webook/pkg/searchx"
+	"strconv"
+	"time"
+)
+
+const (
+	searchFieldTitle    = "title"
+	searchFieldContent  = "content"
+	searchFieldAuthorId = "author_id"
+	searchFieldUtime    = "utime"
+	searchFieldNickname = "nickname"
+	searchFieldAboutMe  = "about_me"
+)
+
+//go:generate mockgen -source=./search.go -package=repomocks -destination=./mocks/search.mock.go SearchRepository
+type SearchRepository interface {
+	InputArticle(ctx context.Context, art domain.Article) error
+	// DeleteArticle version 是文章在数据库里面的更新时间，比它新的写入不会被覆盖
+	DeleteArticle(ctx context.Context, id int64, version int64) error
+	SearchArticle(ctx context.Context, keywords string, offset, limit int) (domain.ArticleSearchResult, error)
+	InputUser(ctx context.Context, u domain.User) error
+	SearchUser(ctx context.Context, keywords string, offset, limit int) (domain.UserSearchResult, error)
+}
+
+type IndexSearchRepository struct {
+	artIdx  searchx.Index
+	userIdx searchx.Index
+}
+
+// NewMemorySearchRepository 用进程内的倒排索引
+func NewMemorySearchRepository() SearchRepository {
+	tokenizer := searchx.NewNGramTokenizer()
+	return NewIndexSearchRepository(
+		searchx.NewMemoryIndex(tokenizer,
+			searchx.Field{Name: searchFieldTitle, Weight: 3},
+			// 和文章摘要一样长
+			searchx.Field{Name: searchFieldContent, Weight: 1, SnippetLen: 128}),
+		searchx.NewMemoryIndex(tokenizer,
+			searchx.Field{Name: searchFieldNickname, Weight: 3},
+			searchx.Field{Name: searchFieldAboutMe, Weight: 1, SnippetLen: 64}))
+}
+
+func NewIndexSearchRepository(artIdx searchx.Index, userIdx searchx.Index) SearchRepository {
+	return &IndexSearchRepository{
+		artIdx:  artIdx,
+		userIdx: userIdx,
+	}
+}
+
+func (i *IndexSearchRepository) InputArticle(ctx context.Context, art domain.Article) error {
+	return i.artIdx.Upsert(ctx, searchx.Document{
+		Id:      art.Id,
+		Version: art.Utime.UnixMilli(),
+		Fields: map[string]string{
+			searchFieldTitle:    art.Title,
+			searchFieldContent:  art.Content,
+			searchFieldAuthorId: strconv.FormatInt(art.Author.Id, 10),
+			searchFieldUtime:    strconv.FormatInt(art.Utime.UnixMilli(), 10),
+		},
+	})
+}
+
+func (i *IndexSearchRepository) DeleteArticle(ctx context.Context, id int64, version int64) error {
+	return i.artIdx.Delete(ctx, id, version)
+}
+
+func (i *IndexSearchRepository) SearchArticle(ctx context.Context,
+	keywords string, offset, limit int) (domain.ArticleSearchResult, error) {
+	res, err := i.artIdx.Search(ctx, searchx.Query{
+		Keywords: keywords,
+		Offset:   offset,
+		Limit:    limit,
+	})
+	if err != nil {
+		return domain.ArticleSearchResult{}, err
+	}
+	return domain.ArticleSearchResult{
+		Total: res.Total,
+		Articles: slice.Map(res.Hits, func(idx int, src searchx.Hit) domain.SearchArticle {
+			authorId, _ := strconv.ParseInt(src.Fields[searchFieldAuthorId], 10, 64)
+			utime, _ := strconv.ParseInt(src.Fields[searchFieldUtime], 10, 64)
+			return domain.SearchArticle{
+				Id:       src.Id,
+				AuthorId: authorId,
+				Title:    src.Highlights[searchFieldTitle],
+				Abstract: src.Highlights[searchFieldContent],
+				Utime:    time.UnixMilli(utime),
+			}
+		}),
+	}, nil
+}
+
+func (i *IndexSearchRepository) InputUser(ctx context.Context, u domain.User) error {
+	return i.userIdx.Upsert(ctx, searchx.Document{
+		Id:      u.Id,
+		Version: u.Utime.UnixMilli(),
+		Fields: map[string]string{
+			searchFieldNickname: u.Nickname,
+			searchFieldAboutMe:  u.AboutMe,
+		},
+	})
+}
+
+func (i *IndexSearchRepository) SearchUser(ctx context.Context,
+	keywords string, offset, limit int) (domain.UserSearchResult, error) {
+	res, err := i.userIdx.Search(ctx, searchx.Query{
+		Keywords: keywords,
+		Offset:   offset,
+		Limit:    limit,
+	})
+	if err != nil {
+		return domain.UserSearchResult{}, err
+	}
+	return domain.UserSearchResult{
+		Total: res.Total,
+		Users: slice.Map(res.Hits, func(idx int, src searchx.Hit) domain.SearchUser {
+			return domain.SearchUser{
+				Id:       src.Id,
+				Nickname: src.Highlights[searchFieldNickname],
+				AboutMe:  src.Highlights[searchFieldAboutMe],
+			}
+		}),
+	}, nil
+}
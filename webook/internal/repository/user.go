@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"github.com/ecodeclub/ekit/slice"
 	"github.com/wsqigo/basic-go/webook/internal/domain"
 	"github.com/wsqigo/basic-go/webook/internal/repository/cache"
 	"github.com/wsqigo/basic-go/webook/internal/repository/dao"
@@ -24,6 +25,8 @@ type UserRepository interface {
 	FindById(ctx context.Context, uid int64) (domain.User, error)
 	FindByWechat(ctx context.Context, openId string) (domain.User, error)
 	FindByDDing(ctx context.Context, openId string) (domain.User, error)
+	// ListAfter 按照 ID 升序遍历用户，minId 为 0 表示从头开始
+	ListAfter(ctx context.Context, minId int64, limit int) ([]domain.User, error)
 }
 
 type CachedUserRepository struct {
@@ -91,6 +94,7 @@ func (repo *CachedUserRepository) toDomain(u dao.User) domain.User {
 		Birthday: time.UnixMilli(u.Birthday),
 		AboutMe:  u.AboutMe,
 		Ctime:    time.UnixMilli(u.Ctime),
+		Utime:    time.UnixMilli(u.Utime),
 		WechatInfo: domain.WechatInfo{
 			OpenId:  u.WechatOpenId.String,
 			UnionId: u.WechatUnionId.String,
@@ -137,7 +141,26 @@ func (repo *CachedUserRepository) toEntity(u domain.User) dao.User {
 }
 
 func (repo *CachedUserRepository) UpdateNonZeroFields(ctx context.Context, user domain.User) error {
-	return repo.dao.UpdateById(ctx, repo.toEntity(user))
+	err := repo.dao.UpdateById(ctx, repo.toEntity(user))
+	if err != nil {
+		return err
+	}
+	// 删掉缓存，不然改完之后还是能读到旧的资料
+	err = repo.cache.Del(ctx, user.Id)
+	if err != nil {
+		log.Println(err)
+	}
+	return nil
+}
+
+func (repo *CachedUserRepository) ListAfter(ctx context.Context, minId int64, limit int) ([]domain.User, error) {
+	us, err := repo.dao.ListAfter(ctx, minId, limit)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(us, func(idx int, src dao.User) domain.User {
+		return repo.toDomain(src)
+	}), nil
 }
 
 func (repo *CachedUserRepository) FindById(ctx context.Context, uid int64) (domain.User, error) {
@@ -54,6 +54,7 @@ func TestCachedUserRepository_FindById(t *testing.T) {
 					AboutMe:  "自我介绍",
 					Phone:    "15212345678",
 					Ctime:    time.UnixMilli(101),
+					Utime:    time.UnixMilli(102),
 				}).Return(nil)
 				return c, d
 			},
@@ -67,6 +68,7 @@ func TestCachedUserRepository_FindById(t *testing.T) {
 				AboutMe:  "自我介绍",
 				Phone:    "15212345678",
 				Ctime:    time.UnixMilli(101),
+				Utime:    time.UnixMilli(102),
 			},
 		},
 		{
@@ -145,6 +147,7 @@ func TestCachedUserRepository_FindById(t *testing.T) {
 					AboutMe:  "自我介绍",
 					Phone:    "15212345678",
 					Ctime:    time.UnixMilli(101),
+					Utime:    time.UnixMilli(102),
 				}).Return(errors.New("redis错误"))
 				return c, d
 			},
@@ -158,6 +161,7 @@ func TestCachedUserRepository_FindById(t *testing.T) {
 				AboutMe:  "自我介绍",
 				Phone:    "15212345678",
 				Ctime:    time.UnixMilli(101),
+				Utime:    time.UnixMilli(102),
 			},
 		},
 	}
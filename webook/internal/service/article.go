@@ -32,10 +32,11 @@ type articleService struct {
 }
 
 func NewArticleService(repo repository.ArticleRepository,
-	producer article.Producer) ArticleService {
+	producer article.Producer, l logger.LoggerV1) ArticleService {
 	return &articleService{
 		repo:     repo,
 		producer: producer,
+		logger:   l,
 	}
 }
 
@@ -54,7 +55,12 @@ func NewArticleServiceV1(authorRepo repository.ArticleAuthorRepository,
 }
 
 func (a *articleService) Withdraw(ctx context.Context, uid int64, id int64) error {
-	return a.repo.SyncStatus(ctx, uid, id, domain.ArticleStatusPrivate)
+	err := a.repo.SyncStatus(ctx, uid, id, domain.ArticleStatusPrivate)
+	if err != nil {
+		return err
+	}
+	// WithdrawEvent 和状态在同一个事务里面写进了 outbox，由 OutboxRelayJob 投递
+	return nil
 }
 
 func (a *articleService) Save(ctx context.Context, art domain.Article) (int64, error) {
@@ -69,7 +75,13 @@ func (a *articleService) Save(ctx context.Context, art domain.Article) (int64, e
 
 func (a *articleService) Publish(ctx context.Context, art domain.Article) (int64, error) {
 	art.Status = domain.ArticleStatusPublished
-	return a.repo.Sync(ctx, art)
+	id, err := a.repo.Sync(ctx, art)
+	if err != nil {
+		return id, err
+	}
+	// PublishEvent 和文章在同一个事务里面写进了 outbox，由 OutboxRelayJob 投递
+	// 推送 feed 之类的后续处理都靠这个消息
+	return id, nil
 }
 
 func (a *articleService) PublishV1(ctx context.Context, art domain.Article) (int64, error) {
@@ -124,20 +136,18 @@ func (a *articleService) GetById(ctx context.Context, id int64) (domain.Article,
 
 func (a *articleService) GetPubByID(ctx context.Context, id, uid int64) (domain.Article, error) {
 	res, err := a.repo.GetPubByID(ctx, id)
-	go func() {
-		if err == nil {
-			// 在这里发一个消息
-			er := a.producer.ProduceReadEvent(article.ReadEvent{
-				Aid: id,
-				Uid: uid,
-			})
-			if er != nil {
-				a.logger.Error("发送 ReadEvent 失败",
-					logger.Int64("aid", id),
-					logger.Int64("uid", uid),
-					logger.Error(err))
-			}
+	if err == nil {
+		// 在这里发一个消息，异步发送，不会阻塞查询
+		er := a.producer.ProduceReadEvent(article.ReadEvent{
+			Aid: id,
+			Uid: uid,
+		})
+		if er != nil {
+			a.logger.Error("发送 ReadEvent 失败",
+				logger.Int64("aid", id),
+				logger.Int64("uid", uid),
+				logger.Error(er))
 		}
-	}()
+	}
 	return res, err
 }
@@ -34,8 +34,9 @@ func (svc *codeService) Send(ctx context.Context, biz, phone string) error {
 	if err != nil {
 		return err
 	}
-	const codeTplId = "SMS_186030080"
-	return svc.sms.Send(ctx, codeTplId, []string{code}, phone)
+	// 逻辑上的模板名字，各个服务商的模板 ID 在模板配置里面
+	const codeTplName = "login_code"
+	return svc.sms.Send(ctx, codeTplName, []string{code}, phone)
 }
 
 func (svc *codeService) Verify(ctx context.Context, biz, phone, inputCode string) (bool, error) {
@@ -50,5 +51,6 @@ func (svc *codeService) Verify(ctx context.Context, biz, phone, inputCode string
 func (svc *codeService) generate() string {
 	// 0-999999
 	code := rand.Intn(1000000)
-	return fmt.Sprintf("%6d", code)
+	// 不足六位的前面补 0，不然会有空格，过不了模板参数的校验
+	return fmt.Sprintf("%06d", code)
 }
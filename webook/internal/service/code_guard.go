@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/wsqigo/basic-go/webook/internal/domain"
+	"github.com/wsqigo/basic-go/webook/pkg/limiter"
+	"strings"
+)
+
+var (
+	ErrCodeSendPhoneBlocked  = errors.New("这个手机号码不能发送验证码")
+	ErrCodeSendHumanCheck    = errors.New("人机校验没有通过")
+	ErrCodeSendPhoneLimited  = errors.New("这个手机号码今天发送的验证码太多了")
+	ErrCodeSendIPLimited     = errors.New("这个 IP 发送的验证码太多了")
+	ErrCodeSendDeviceLimited = errors.New("这个设备发送的验证码太多了")
+)
+
+// CodeSendGuard 发送验证码之前的防刷检查，通过了才能调用 CodeService.Send
+//
+//go:generate mockgen -destination=./mocks/code_guard.mock.go -package=svcmocks -source=./code_guard.go CodeSendGuard HumanVerifier
+type CodeSendGuard interface {
+	Check(ctx context.Context, req domain.CodeSendRequest) error
+}
+
+// HumanVerifier 人机校验，比如说滑块验证码，可以换成不同厂商的实现
+type HumanVerifier interface {
+	Verify(ctx context.Context, token string, ip string) (bool, error)
+}
+
+// NopHumanVerifier 不做人机校验
+type NopHumanVerifier struct {
+}
+
+func (n NopHumanVerifier) Verify(ctx context.Context, token string, ip string) (bool, error) {
+	return true, nil
+}
+
+type codeSendGuard struct {
+	// 每个手机号码一天
+	phoneLimiter limiter.Limiter
+	// 每个 IP 一小时
+	ipLimiter limiter.Limiter
+	// 每个设备一天
+	deviceLimiter limiter.Limiter
+	verifier      HumanVerifier
+	// 比如说虚拟运营商的号段
+	blockedPrefixes []string
+}
+
+func NewCodeSendGuard(phoneLimiter, ipLimiter, deviceLimiter limiter.Limiter,
+	verifier HumanVerifier, blockedPrefixes []string) CodeSendGuard {
+	return &codeSendGuard{
+		phoneLimiter:    phoneLimiter,
+		ipLimiter:       ipLimiter,
+		deviceLimiter:   deviceLimiter,
+		verifier:        verifier,
+		blockedPrefixes: blockedPrefixes,
+	}
+}
+
+// Check 先做不需要访问 Redis 的检查
+// 限流的时候先看 IP 和设备，再看手机号码，避免别人用一个 IP 耗光某个手机号码的额度
+func (g *codeSendGuard) Check(ctx context.Context, req domain.CodeSendRequest) error {
+	for _, prefix := range g.blockedPrefixes {
+		if strings.HasPrefix(req.Phone, prefix) {
+			return ErrCodeSendPhoneBlocked
+		}
+	}
+	ok, err := g.verifier.Verify(ctx, req.HumanToken, req.IP)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrCodeSendHumanCheck
+	}
+	err = g.limit(ctx, g.ipLimiter, fmt.Sprintf("code_send:ip:%s", req.IP), ErrCodeSendIPLimited)
+	if err != nil {
+		return err
+	}
+	// 没有设备 ID 的只能靠 IP 和手机号码限制
+	if req.DeviceId != "" {
+		err = g.limit(ctx, g.deviceLimiter,
+			fmt.Sprintf("code_send:device:%s", req.DeviceId), ErrCodeSendDeviceLimited)
+		if err != nil {
+			return err
+		}
+	}
+	return g.limit(ctx, g.phoneLimiter,
+		fmt.Sprintf("code_send:phone:%s:%s", req.Biz, req.Phone), ErrCodeSendPhoneLimited)
+}
+
+func (g *codeSendGuard) limit(ctx context.Context, l limiter.Limiter, key string, limitedErr error) error {
+	limited, err := l.Limit(ctx, key)
+	if err != nil {
+		return err
+	}
+	if limited {
+		return limitedErr
+	}
+	return nil
+}
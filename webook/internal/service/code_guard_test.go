@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/wsqigo/basic-go/webook/internal/domain"
+	svcmocks "github.com/wsqigo/basic-go/webook/internal/service/mocks"
+	"github.com/wsqigo/basic-go/webook/pkg/limiter"
+	limitermocks "github.com/wsqigo/basic-go/webook/pkg/limiter/mocks"
+	"go.uber.org/mock/gomock"
+	"testing"
+)
+
+func TestCodeSendGuard_Check(t *testing.T) {
+	req := domain.CodeSendRequest{
+		Biz:        "login",
+		Phone:      "15212345678",
+		IP:         "127.0.0.1",
+		DeviceId:   "dev-1",
+		HumanToken: "token",
+	}
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) (phone, ip, device limiter.Limiter, verifier HumanVerifier)
+		req  domain.CodeSendRequest
+
+		wantErr error
+	}{
+		{
+			name: "通过",
+			mock: func(ctrl *gomock.Controller) (limiter.Limiter, limiter.Limiter, limiter.Limiter, HumanVerifier) {
+				phone := limitermocks.NewMockLimiter(ctrl)
+				ip := limitermocks.NewMockLimiter(ctrl)
+				device := limitermocks.NewMockLimiter(ctrl)
+				verifier := svcmocks.NewMockHumanVerifier(ctrl)
+				verifier.EXPECT().Verify(gomock.Any(), "token", "127.0.0.1").Return(true, nil)
+				ip.EXPECT().Limit(gomock.Any(), "code_send:ip:127.0.0.1").Return(false, nil)
+				device.EXPECT().Limit(gomock.Any(), "code_send:device:dev-1").Return(false, nil)
+				phone.EXPECT().Limit(gomock.Any(), "code_send:phone:login:15212345678").Return(false, nil)
+				return phone, ip, device, verifier
+			},
+			req: req,
+		},
+		{
+			name: "号段被屏蔽",
+			mock: func(ctrl *gomock.Controller) (limiter.Limiter, limiter.Limiter, limiter.Limiter, HumanVerifier) {
+				return limitermocks.NewMockLimiter(ctrl), limitermocks.NewMockLimiter(ctrl),
+					limitermocks.NewMockLimiter(ctrl), svcmocks.NewMockHumanVerifier(ctrl)
+			},
+			req: domain.CodeSendRequest{
+				Biz:   "login",
+				Phone: "17012345678",
+				IP:    "127.0.0.1",
+			},
+			wantErr: ErrCodeSendPhoneBlocked,
+		},
+		{
+			name: "人机校验失败",
+			mock: func(ctrl *gomock.Controller) (limiter.Limiter, limiter.Limiter, limiter.Limiter, HumanVerifier) {
+				verifier := svcmocks.NewMockHumanVerifier(ctrl)
+				verifier.EXPECT().Verify(gomock.Any(), "token", "127.0.0.1").Return(false, nil)
+				return limitermocks.NewMockLimiter(ctrl), limitermocks.NewMockLimiter(ctrl),
+					limitermocks.NewMockLimiter(ctrl), verifier
+			},
+			req:     req,
+			wantErr: ErrCodeSendHumanCheck,
+		},
+		{
+			name: "IP 被限流",
+			mock: func(ctrl *gomock.Controller) (limiter.Limiter, limiter.Limiter, limiter.Limiter, HumanVerifier) {
+				ip := limitermocks.NewMockLimiter(ctrl)
+				verifier := svcmocks.NewMockHumanVerifier(ctrl)
+				verifier.EXPECT().Verify(gomock.Any(), "token", "127.0.0.1").Return(true, nil)
+				ip.EXPECT().Limit(gomock.Any(), "code_send:ip:127.0.0.1").Return(true, nil)
+				return limitermocks.NewMockLimiter(ctrl), ip, limitermocks.NewMockLimiter(ctrl), verifier
+			},
+			req:     req,
+			wantErr: ErrCodeSendIPLimited,
+		},
+		{
+			name: "设备被限流",
+			mock: func(ctrl *gomock.Controller) (limiter.Limiter, limiter.Limiter, limiter.Limiter, HumanVerifier) {
+				ip := limitermocks.NewMockLimiter(ctrl)
+				device := limitermocks.NewMockLimiter(ctrl)
+				verifier := svcmocks.NewMockHumanVerifier(ctrl)
+				verifier.EXPECT().Verify(gomock.Any(), "token", "127.0.0.1").Return(true, nil)
+				ip.EXPECT().Limit(gomock.Any(), "code_send:ip:127.0.0.1").Return(false, nil)
+				device.EXPECT().Limit(gomock.Any(), "code_send:device:dev-1").Return(true, nil)
+				return limitermocks.NewMockLimiter(ctrl), ip, device, verifier
+			},
+			req:     req,
+			wantErr: ErrCodeSendDeviceLimited,
+		},
+		{
+			name: "没有设备 ID，手机号码被限流",
+			mock: func(ctrl *gomock.Controller) (limiter.Limiter, limiter.Limiter, limiter.Limiter, HumanVerifier) {
+				phone := limitermocks.NewMockLimiter(ctrl)
+				ip := limitermocks.NewMockLimiter(ctrl)
+				verifier := svcmocks.NewMockHumanVerifier(ctrl)
+				verifier.EXPECT().Verify(gomock.Any(), "", "127.0.0.1").Return(true, nil)
+				ip.EXPECT().Limit(gomock.Any(), "code_send:ip:127.0.0.1").Return(false, nil)
+				phone.EXPECT().Limit(gomock.Any(), "code_send:phone:login:15212345678").Return(true, nil)
+				return phone, ip, limitermocks.NewMockLimiter(ctrl), verifier
+			},
+			req: domain.CodeSendRequest{
+				Biz:   "login",
+				Phone: "15212345678",
+				IP:    "127.0.0.1",
+			},
+			wantErr: ErrCodeSendPhoneLimited,
+		},
+		{
+			name: "限流器出错",
+			mock: func(ctrl *gomock.Controller) (limiter.Limiter, limiter.Limiter, limiter.Limiter, HumanVerifier) {
+				ip := limitermocks.NewMockLimiter(ctrl)
+				verifier := svcmocks.NewMockHumanVerifier(ctrl)
+				verifier.EXPECT().Verify(gomock.Any(), "token", "127.0.0.1").Return(true, nil)
+				ip.EXPECT().Limit(gomock.Any(), "code_send:ip:127.0.0.1").
+					Return(false, errors.New("redis 出错"))
+				return limitermocks.NewMockLimiter(ctrl), ip, limitermocks.NewMockLimiter(ctrl), verifier
+			},
+			req:     req,
+			wantErr: errors.New("redis 出错"),
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			phone, ip, device, verifier := tc.mock(ctrl)
+			guard := NewCodeSendGuard(phone, ip, device, verifier, []string{"170", "171"})
+			err := guard.Check(context.Background(), tc.req)
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"github.com/wsqigo/basic-go/webook/internal/domain"
+	"github.com/wsqigo/basic-go/webook/internal/repository"
+)
+
+var (
+	ErrCollectionNotFound     = repository.ErrCollectionNotFound
+	ErrCollectionItemNotFound = repository.ErrCollectionItemNotFound
+)
+
+//go:generate mockgen -source=./collection.go -package=svcmocks -destination=./mocks/collection.mock.go CollectionService
+type CollectionService interface {
+	Create(ctx context.Context, c domain.Collection) (int64, error)
+	Rename(ctx context.Context, uid int64, id int64, name string) error
+	// Delete 删除收藏夹，里面的收藏也会被取消
+	Delete(ctx context.Context, uid int64, id int64) error
+	List(ctx context.Context, uid int64, offset int, limit int) ([]domain.Collection, error)
+	// ListItems cid 为 0 代表默认收藏夹
+	ListItems(ctx context.Context, uid int64, cid int64, offset int, limit int) ([]domain.CollectionItem, error)
+	// MoveItem 把收藏移动到 cid 收藏夹
+	MoveItem(ctx context.Context, uid int64, biz string, bizId int64, cid int64) error
+}
+
+type collectionService struct {
+	repo repository.CollectionRepository
+}
+
+func NewCollectionService(repo repository.CollectionRepository) CollectionService {
+	return &collectionService{
+		repo: repo,
+	}
+}
+
+func (c *collectionService) Create(ctx context.Context, col domain.Collection) (int64, error) {
+	return c.repo.Create(ctx, col)
+}
+
+func (c *collectionService) Rename(ctx context.Context, uid int64, id int64, name string) error {
+	return c.repo.Rename(ctx, uid, id, name)
+}
+
+func (c *collectionService) Delete(ctx context.Context, uid int64, id int64) error {
+	return c.repo.Delete(ctx, uid, id)
+}
+
+func (c *collectionService) List(ctx context.Context, uid int64,
+	offset int, limit int) ([]domain.Collection, error) {
+	return c.repo.FindByUid(ctx, uid, offset, limit)
+}
+
+func (c *collectionService) ListItems(ctx context.Context, uid int64,
+	cid int64, offset int, limit int) ([]domain.CollectionItem, error) {
+	if err := c.checkOwner(ctx, uid, cid); err != nil {
+		return nil, err
+	}
+	return c.repo.FindItems(ctx, uid, cid, offset, limit)
+}
+
+func (c *collectionService) MoveItem(ctx context.Context, uid int64,
+	biz string, bizId int64, cid int64) error {
+	// 不能挪到别人的收藏夹里面
+	if err := c.checkOwner(ctx, uid, cid); err != nil {
+		return err
+	}
+	return c.repo.MoveItem(ctx, uid, biz, bizId, cid)
+}
+
+// checkOwner 确认收藏夹是 uid 的，默认收藏夹不需要检查
+func (c *collectionService) checkOwner(ctx context.Context, uid int64, cid int64) error {
+	if cid == 0 {
+		return nil
+	}
+	_, err := c.repo.FindById(ctx, uid, cid)
+	return err
+}
@@ -0,0 +1,70 @@
+package service
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/wsqigo/basic-go/webook/internal/domain"
+	"github.com/wsqigo/basic-go/webook/internal/repository"
+	repomocks "github.com/wsqigo/basic-go/webook/internal/repository/mocks"
+	"go.uber.org/mock/gomock"
+	"testing"
+)
+
+func Test_collectionService_MoveItem(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) repository.CollectionRepository
+
+		uid int64
+		cid int64
+
+		wantErr error
+	}{
+		{
+			name: "移动到自己的收藏夹",
+			mock: func(ctrl *gomock.Controller) repository.CollectionRepository {
+				repo := repomocks.NewMockCollectionRepository(ctrl)
+				repo.EXPECT().FindById(gomock.Any(), int64(123), int64(2)).
+					Return(domain.Collection{Id: 2, Uid: 123}, nil)
+				repo.EXPECT().MoveItem(gomock.Any(), int64(123), "article", int64(1), int64(2)).
+					Return(nil)
+				return repo
+			},
+			uid: 123,
+			cid: 2,
+		},
+		{
+			name: "移动到默认收藏夹",
+			mock: func(ctrl *gomock.Controller) repository.CollectionRepository {
+				repo := repomocks.NewMockCollectionRepository(ctrl)
+				repo.EXPECT().MoveItem(gomock.Any(), int64(123), "article", int64(1), int64(0)).
+					Return(nil)
+				return repo
+			},
+			uid: 123,
+			cid: 0,
+		},
+		{
+			name: "移动到别人的收藏夹",
+			mock: func(ctrl *gomock.Controller) repository.CollectionRepository {
+				repo := repomocks.NewMockCollectionRepository(ctrl)
+				repo.EXPECT().FindById(gomock.Any(), int64(123), int64(2)).
+					Return(domain.Collection{}, repository.ErrCollectionNotFound)
+				return repo
+			},
+			uid:     123,
+			cid:     2,
+			wantErr: ErrCollectionNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc := NewCollectionService(tc.mock(ctrl))
+			err := svc.MoveItem(context.Background(), tc.uid, "article", 1, tc.cid)
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
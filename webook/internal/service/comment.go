@@ -0,0 +1,123 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/wsqigo/basic-go/webook/internal/domain"
+	"github.com/wsqigo/basic-go/webook/internal/repository"
+	"golang.org/x/sync/errgroup"
+)
+
+var (
+	ErrInvalidComment          = errors.New("回复的评论不存在或者不属于同一个业务")
+	ErrCommentPermissionDenied = errors.New("只有评论者或者作者才能删除评论")
+)
+
+//go:generate mockgen -source=./comment.go -package=svcmocks -destination=./mocks/comment.mock.go CommentService
+type CommentService interface {
+	// CreateComment 发表评论，ParentComment 不为 nil 说明是回复
+	CreateComment(ctx context.Context, c domain.Comment) (int64, error)
+	// GetCommentList 根评论列表，每一条根评论会带上最早的几条回复
+	GetCommentList(ctx context.Context, biz string, bizId int64, minId int64, limit int) ([]domain.Comment, error)
+	GetMoreReplies(ctx context.Context, rid int64, maxId int64, limit int) ([]domain.Comment, error)
+	// DeleteComment 评论者或者被评论文章的作者可以删除
+	DeleteComment(ctx context.Context, uid int64, id int64) error
+}
+
+type commentService struct {
+	repo    repository.CommentRepository
+	artRepo repository.ArticleRepository
+	// 每一条根评论预加载多少条回复
+	replyCnt int
+}
+
+func NewCommentService(repo repository.CommentRepository,
+	artRepo repository.ArticleRepository) CommentService {
+	return &commentService{
+		repo:     repo,
+		artRepo:  artRepo,
+		replyCnt: 3,
+	}
+}
+
+func (c *commentService) CreateComment(ctx context.Context, cmt domain.Comment) (int64, error) {
+	if cmt.ParentComment != nil {
+		parent, err := c.repo.FindById(ctx, cmt.ParentComment.Id)
+		if err == repository.ErrCommentNotFound {
+			return 0, ErrInvalidComment
+		}
+		if err != nil {
+			return 0, err
+		}
+		if parent.Biz != cmt.Biz || parent.BizId != cmt.BizId {
+			return 0, ErrInvalidComment
+		}
+		// 根评论以数据库里面的为准，不信任前端传过来的
+		if parent.IsRoot() {
+			cmt.RootComment = &domain.Comment{Id: parent.Id}
+		} else {
+			cmt.RootComment = &domain.Comment{Id: parent.RootComment.Id}
+		}
+	} else {
+		cmt.RootComment = nil
+	}
+	return c.repo.CreateComment(ctx, cmt)
+}
+
+func (c *commentService) GetCommentList(ctx context.Context, biz string,
+	bizId int64, minId int64, limit int) ([]domain.Comment, error) {
+	cmts, err := c.repo.FindByBiz(ctx, biz, bizId, minId, limit)
+	if err != nil {
+		return nil, err
+	}
+	var eg errgroup.Group
+	for i := range cmts {
+		cmt := &cmts[i]
+		eg.Go(func() error {
+			var er error
+			cmt.Children, er = c.repo.GetMoreReplies(ctx, cmt.Id, 0, c.replyCnt)
+			return er
+		})
+	}
+	return cmts, eg.Wait()
+}
+
+func (c *commentService) GetMoreReplies(ctx context.Context,
+	rid int64, maxId int64, limit int) ([]domain.Comment, error) {
+	return c.repo.GetMoreReplies(ctx, rid, maxId, limit)
+}
+
+func (c *commentService) DeleteComment(ctx context.Context, uid int64, id int64) error {
+	cmt, err := c.repo.FindById(ctx, id)
+	if err == repository.ErrCommentNotFound {
+		// 已经删掉了
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if cmt.Commentator.Id != uid {
+		ok, err := c.isAuthor(ctx, cmt, uid)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ErrCommentPermissionDenied
+		}
+	}
+	return c.repo.DeleteComment(ctx, cmt)
+}
+
+// isAuthor uid 是不是被评论的东西的作者
+func (c *commentService) isAuthor(ctx context.Context, cmt domain.Comment, uid int64) (bool, error) {
+	switch cmt.Biz {
+	case "article":
+		art, err := c.artRepo.GetPubByID(ctx, cmt.BizId)
+		if err != nil {
+			return false, err
+		}
+		return art.Author.Id == uid, nil
+	default:
+		return false, nil
+	}
+}
@@ -0,0 +1,247 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/wsqigo/basic-go/webook/internal/domain"
+	"github.com/wsqigo/basic-go/webook/internal/repository"
+	repomocks "github.com/wsqigo/basic-go/webook/internal/repository/mocks"
+	"go.uber.org/mock/gomock"
+	"testing"
+)
+
+func Test_commentService_CreateComment(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) repository.CommentRepository
+
+		cmt domain.Comment
+
+		wantId  int64
+		wantErr error
+	}{
+		{
+			name: "根评论",
+			mock: func(ctrl *gomock.Controller) repository.CommentRepository {
+				repo := repomocks.NewMockCommentRepository(ctrl)
+				repo.EXPECT().CreateComment(gomock.Any(), domain.Comment{
+					Commentator: domain.User{Id: 123},
+					Biz:         "article",
+					BizId:       1,
+					Content:     "写得好",
+				}).Return(int64(10), nil)
+				return repo
+			},
+			cmt: domain.Comment{
+				Commentator: domain.User{Id: 123},
+				Biz:         "article",
+				BizId:       1,
+				Content:     "写得好",
+				// 前端传过来的根评论要被忽略
+				RootComment: &domain.Comment{Id: 99},
+			},
+			wantId: 10,
+		},
+		{
+			name: "回复根评论",
+			mock: func(ctrl *gomock.Controller) repository.CommentRepository {
+				repo := repomocks.NewMockCommentRepository(ctrl)
+				repo.EXPECT().FindById(gomock.Any(), int64(10)).
+					Return(domain.Comment{Id: 10, Biz: "article", BizId: 1}, nil)
+				repo.EXPECT().CreateComment(gomock.Any(), domain.Comment{
+					Commentator:   domain.User{Id: 123},
+					Biz:           "article",
+					BizId:         1,
+					Content:       "同意",
+					RootComment:   &domain.Comment{Id: 10},
+					ParentComment: &domain.Comment{Id: 10},
+				}).Return(int64(11), nil)
+				return repo
+			},
+			cmt: domain.Comment{
+				Commentator:   domain.User{Id: 123},
+				Biz:           "article",
+				BizId:         1,
+				Content:       "同意",
+				ParentComment: &domain.Comment{Id: 10},
+			},
+			wantId: 11,
+		},
+		{
+			name: "回复的回复，根评论沿用父评论的",
+			mock: func(ctrl *gomock.Controller) repository.CommentRepository {
+				repo := repomocks.NewMockCommentRepository(ctrl)
+				repo.EXPECT().FindById(gomock.Any(), int64(11)).
+					Return(domain.Comment{
+						Id: 11, Biz: "article", BizId: 1,
+						RootComment: &domain.Comment{Id: 10},
+					}, nil)
+				repo.EXPECT().CreateComment(gomock.Any(), domain.Comment{
+					Commentator:   domain.User{Id: 123},
+					Biz:           "article",
+					BizId:         1,
+					Content:       "同意",
+					RootComment:   &domain.Comment{Id: 10},
+					ParentComment: &domain.Comment{Id: 11},
+				}).Return(int64(12), nil)
+				return repo
+			},
+			cmt: domain.Comment{
+				Commentator:   domain.User{Id: 123},
+				Biz:           "article",
+				BizId:         1,
+				Content:       "同意",
+				ParentComment: &domain.Comment{Id: 11},
+			},
+			wantId: 12,
+		},
+		{
+			name: "父评论不属于同一篇文章",
+			mock: func(ctrl *gomock.Controller) repository.CommentRepository {
+				repo := repomocks.NewMockCommentRepository(ctrl)
+				repo.EXPECT().FindById(gomock.Any(), int64(10)).
+					Return(domain.Comment{Id: 10, Biz: "article", BizId: 2}, nil)
+				return repo
+			},
+			cmt: domain.Comment{
+				Commentator:   domain.User{Id: 123},
+				Biz:           "article",
+				BizId:         1,
+				Content:       "同意",
+				ParentComment: &domain.Comment{Id: 10},
+			},
+			wantErr: ErrInvalidComment,
+		},
+		{
+			name: "父评论不存在",
+			mock: func(ctrl *gomock.Controller) repository.CommentRepository {
+				repo := repomocks.NewMockCommentRepository(ctrl)
+				repo.EXPECT().FindById(gomock.Any(), int64(10)).
+					Return(domain.Comment{}, repository.ErrCommentNotFound)
+				return repo
+			},
+			cmt: domain.Comment{
+				Commentator:   domain.User{Id: 123},
+				Biz:           "article",
+				BizId:         1,
+				Content:       "同意",
+				ParentComment: &domain.Comment{Id: 10},
+			},
+			wantErr: ErrInvalidComment,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc := NewCommentService(tc.mock(ctrl), nil)
+			id, err := svc.CreateComment(context.Background(), tc.cmt)
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantId, id)
+		})
+	}
+}
+
+func Test_commentService_DeleteComment(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) (repository.CommentRepository,
+			repository.ArticleRepository)
+
+		uid int64
+		id  int64
+
+		wantErr error
+	}{
+		{
+			name: "评论者删除",
+			mock: func(ctrl *gomock.Controller) (repository.CommentRepository,
+				repository.ArticleRepository) {
+				repo := repomocks.NewMockCommentRepository(ctrl)
+				cmt := domain.Comment{Id: 10, Commentator: domain.User{Id: 123},
+					Biz: "article", BizId: 1}
+				repo.EXPECT().FindById(gomock.Any(), int64(10)).Return(cmt, nil)
+				repo.EXPECT().DeleteComment(gomock.Any(), cmt).Return(nil)
+				return repo, repomocks.NewMockArticleRepository(ctrl)
+			},
+			uid: 123,
+			id:  10,
+		},
+		{
+			name: "文章作者删除",
+			mock: func(ctrl *gomock.Controller) (repository.CommentRepository,
+				repository.ArticleRepository) {
+				repo := repomocks.NewMockCommentRepository(ctrl)
+				cmt := domain.Comment{Id: 10, Commentator: domain.User{Id: 123},
+					Biz: "article", BizId: 1}
+				repo.EXPECT().FindById(gomock.Any(), int64(10)).Return(cmt, nil)
+				repo.EXPECT().DeleteComment(gomock.Any(), cmt).Return(nil)
+				artRepo := repomocks.NewMockArticleRepository(ctrl)
+				artRepo.EXPECT().GetPubByID(gomock.Any(), int64(1)).
+					Return(domain.Article{Id: 1, Author: domain.Author{Id: 456}}, nil)
+				return repo, artRepo
+			},
+			uid: 456,
+			id:  10,
+		},
+		{
+			name: "无关的人删除",
+			mock: func(ctrl *gomock.Controller) (repository.CommentRepository,
+				repository.ArticleRepository) {
+				repo := repomocks.NewMockCommentRepository(ctrl)
+				cmt := domain.Comment{Id: 10, Commentator: domain.User{Id: 123},
+					Biz: "article", BizId: 1}
+				repo.EXPECT().FindById(gomock.Any(), int64(10)).Return(cmt, nil)
+				artRepo := repomocks.NewMockArticleRepository(ctrl)
+				artRepo.EXPECT().GetPubByID(gomock.Any(), int64(1)).
+					Return(domain.Article{Id: 1, Author: domain.Author{Id: 456}}, nil)
+				return repo, artRepo
+			},
+			uid:     789,
+			id:      10,
+			wantErr: ErrCommentPermissionDenied,
+		},
+		{
+			name: "查询文章失败",
+			mock: func(ctrl *gomock.Controller) (repository.CommentRepository,
+				repository.ArticleRepository) {
+				repo := repomocks.NewMockCommentRepository(ctrl)
+				cmt := domain.Comment{Id: 10, Commentator: domain.User{Id: 123},
+					Biz: "article", BizId: 1}
+				repo.EXPECT().FindById(gomock.Any(), int64(10)).Return(cmt, nil)
+				artRepo := repomocks.NewMockArticleRepository(ctrl)
+				artRepo.EXPECT().GetPubByID(gomock.Any(), int64(1)).
+					Return(domain.Article{}, errors.New("mock db error"))
+				return repo, artRepo
+			},
+			uid:     789,
+			id:      10,
+			wantErr: errors.New("mock db error"),
+		},
+		{
+			name: "评论已经不存在",
+			mock: func(ctrl *gomock.Controller) (repository.CommentRepository,
+				repository.ArticleRepository) {
+				repo := repomocks.NewMockCommentRepository(ctrl)
+				repo.EXPECT().FindById(gomock.Any(), int64(10)).
+					Return(domain.Comment{}, repository.ErrCommentNotFound)
+				return repo, repomocks.NewMockArticleRepository(ctrl)
+			},
+			uid: 123,
+			id:  10,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			repo, artRepo := tc.mock(ctrl)
+			svc := NewCommentService(repo, artRepo)
+			err := svc.DeleteComment(context.Background(), tc.uid, tc.id)
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
@@ -0,0 +1,209 @@
+package service
+
+import (
+	"context"
+	"github.com/wsqigo/basic-go/webook/internal/domain"
+	"github.com/wsqigo/basic-go/webook/internal/repository"
+	"golang.org/x/sync/errgroup"
+	"sort"
+	"sync"
+	"time"
+)
+
+//go:generate mockgen -source=./feed.go -package=svcmocks -destination=./mocks/feed.mock.go FeedService
+type FeedService interface {
+	// PushFeed 文章发表之后调用。粉丝少的作者推到粉丝的收件箱，粉丝多的作者标记为拉模型
+	PushFeed(ctx context.Context, aid int64, author int64, ctime time.Time) error
+	// GetFeed uid 的 feed 流，maxTime 之前的数据，按照时间倒序
+	GetFeed(ctx context.Context, uid int64, maxTime time.Time, limit int) ([]domain.FeedItem, error)
+}
+
+type feedService struct {
+	repo       repository.FeedRepository
+	followRepo repository.FollowRepository
+	artRepo    repository.ArticleRepository
+
+	// 粉丝数超过这个值就走拉模型
+	pushThreshold int64
+	// 推的时候每一批处理多少个粉丝
+	batchSize int
+	// 拉的时候最多看多少个关注的人
+	followeeLimit int
+}
+
+func NewFeedService(repo repository.FeedRepository,
+	followRepo repository.FollowRepository,
+	artRepo repository.ArticleRepository) FeedService {
+	return &feedService{
+		repo:          repo,
+		followRepo:    followRepo,
+		artRepo:       artRepo,
+		pushThreshold: 1000,
+		batchSize:     500,
+		followeeLimit: 1000,
+	}
+}
+
+func (f *feedService) PushFeed(ctx context.Context, aid int64, author int64, ctime time.Time) error {
+	statics, err := f.followRepo.GetFollowStatics(ctx, author)
+	if err != nil {
+		return err
+	}
+	if statics.Followers > f.pushThreshold {
+		// 大 V 写扩散的代价太大，读的时候再去拉
+		return f.repo.MarkPullAuthor(ctx, author)
+	}
+	var maxId int64
+	for {
+		followers, err := f.followRepo.GetFollower(ctx, author, maxId, f.batchSize)
+		if err != nil {
+			return err
+		}
+		if len(followers) == 0 {
+			return nil
+		}
+		uids := make([]int64, 0, len(followers))
+		for _, fr := range followers {
+			uids = append(uids, fr.Follower)
+		}
+		err = f.repo.AddToInboxes(ctx, uids, aid, ctime)
+		if err != nil {
+			return err
+		}
+		if len(followers) < f.batchSize {
+			return nil
+		}
+		maxId = followers[len(followers)-1].Id
+	}
+}
+
+func (f *feedService) GetFeed(ctx context.Context, uid int64,
+	maxTime time.Time, limit int) ([]domain.FeedItem, error) {
+	var (
+		eg     errgroup.Group
+		inbox  []domain.FeedItem
+		pulled []domain.FeedItem
+	)
+	eg.Go(func() error {
+		var err error
+		inbox, err = f.repo.Inbox(ctx, uid, maxTime, limit)
+		return err
+	})
+	eg.Go(func() error {
+		var err error
+		pulled, err = f.pull(ctx, uid, maxTime, limit)
+		return err
+	})
+	if err := eg.Wait(); err != nil {
+		return nil, err
+	}
+
+	// 收件箱里面只有 ID，拉过来的是完整的文章，同一篇文章优先用拉过来的
+	loaded := make(map[int64]struct{}, len(pulled))
+	items := make([]domain.FeedItem, 0, len(inbox)+len(pulled))
+	for _, item := range pulled {
+		loaded[item.Article.Id] = struct{}{}
+		items = append(items, item)
+	}
+	seen := make(map[int64]struct{}, len(inbox)+len(pulled))
+	for id := range loaded {
+		seen[id] = struct{}{}
+	}
+	for _, item := range inbox {
+		if _, ok := seen[item.Article.Id]; ok {
+			continue
+		}
+		seen[item.Article.Id] = struct{}{}
+		items = append(items, item)
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Ctime.After(items[j].Ctime)
+	})
+	if len(items) > limit {
+		items = items[:limit]
+	}
+	return f.loadArticles(ctx, items, loaded)
+}
+
+// pull 拉模型，查询关注的大 V 最近发表的文章
+func (f *feedService) pull(ctx context.Context, uid int64,
+	maxTime time.Time, limit int) ([]domain.FeedItem, error) {
+	followees, err := f.followRepo.GetFollowee(ctx, uid, 0, f.followeeLimit)
+	if err != nil {
+		return nil, err
+	}
+	uids := make([]int64, 0, len(followees))
+	for _, fr := range followees {
+		uids = append(uids, fr.Followee)
+	}
+	authors, err := f.repo.FilterPullAuthors(ctx, uids)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		eg  errgroup.Group
+		mu  sync.Mutex
+		res []domain.FeedItem
+	)
+	eg.SetLimit(10)
+	for _, author := range authors {
+		author := author
+		eg.Go(func() error {
+			arts, er := f.artRepo.ListPubByAuthor(ctx, author, maxTime, limit)
+			if er != nil {
+				return er
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			for _, art := range arts {
+				res = append(res, domain.FeedItem{
+					Article: art,
+					Ctime:   art.Utime,
+				})
+			}
+			return nil
+		})
+	}
+	return res, eg.Wait()
+}
+
+// loadArticles 把收件箱里面的文章补全。
+// 已经撤回或者删掉的文章会被过滤掉，所以返回的数量可能比 limit 少
+func (f *feedService) loadArticles(ctx context.Context,
+	items []domain.FeedItem, loaded map[int64]struct{}) ([]domain.FeedItem, error) {
+	var eg errgroup.Group
+	eg.SetLimit(10)
+	found := make([]bool, len(items))
+	for i := range items {
+		i := i
+		if _, ok := loaded[items[i].Article.Id]; ok {
+			found[i] = true
+			continue
+		}
+		eg.Go(func() error {
+			art, err := f.artRepo.GetPubByID(ctx, items[i].Article.Id)
+			if err == repository.ErrArticleNotFound {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			if art.Status != domain.ArticleStatusPublished {
+				return nil
+			}
+			items[i].Article = art
+			found[i] = true
+			return nil
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return nil, err
+	}
+	res := make([]domain.FeedItem, 0, len(items))
+	for i, item := range items {
+		if found[i] {
+			res = append(res, item)
+		}
+	}
+	return res, nil
+}
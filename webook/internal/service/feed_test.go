@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wsqigo/basic-go/webook/internal/domain"
+	"github.com/wsqigo/basic-go/webook/internal/repository"
+	repomocks "github.com/wsqigo/basic-go/webook/internal/repository/mocks"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+)
+
+func Test_feedService_PushFeed(t *testing.T) {
+	now := time.UnixMilli(1000)
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) (repository.FeedRepository, repository.FollowRepository)
+
+		wantErr error
+	}{
+		{
+			name: "粉丝少，推到收件箱",
+			mock: func(ctrl *gomock.Controller) (repository.FeedRepository, repository.FollowRepository) {
+				feedRepo := repomocks.NewMockFeedRepository(ctrl)
+				followRepo := repomocks.NewMockFollowRepository(ctrl)
+				followRepo.EXPECT().GetFollowStatics(gomock.Any(), int64(123)).
+					Return(domain.FollowStatics{Followers: 2}, nil)
+				followRepo.EXPECT().GetFollower(gomock.Any(), int64(123), int64(0), 500).
+					Return([]domain.FollowRelation{
+						{Id: 2, Follower: 456, Followee: 123},
+						{Id: 1, Follower: 789, Followee: 123},
+					}, nil)
+				feedRepo.EXPECT().AddToInboxes(gomock.Any(), []int64{456, 789}, int64(1), now).
+					Return(nil)
+				return feedRepo, followRepo
+			},
+		},
+		{
+			name: "粉丝多，标记为拉模型",
+			mock: func(ctrl *gomock.Controller) (repository.FeedRepository, repository.FollowRepository) {
+				feedRepo := repomocks.NewMockFeedRepository(ctrl)
+				followRepo := repomocks.NewMockFollowRepository(ctrl)
+				followRepo.EXPECT().GetFollowStatics(gomock.Any(), int64(123)).
+					Return(domain.FollowStatics{Followers: 100000}, nil)
+				feedRepo.EXPECT().MarkPullAuthor(gomock.Any(), int64(123)).Return(nil)
+				return feedRepo, followRepo
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			feedRepo, followRepo := tc.mock(ctrl)
+			svc := NewFeedService(feedRepo, followRepo, repomocks.NewMockArticleRepository(ctrl))
+			err := svc.PushFeed(context.Background(), 1, 123, now)
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
+
+func Test_feedService_GetFeed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	feedRepo := repomocks.NewMockFeedRepository(ctrl)
+	followRepo := repomocks.NewMockFollowRepository(ctrl)
+	artRepo := repomocks.NewMockArticleRepository(ctrl)
+	maxTime := time.UnixMilli(10000)
+
+	// 收件箱里面 3 是重复的，4 已经撤回了
+	feedRepo.EXPECT().Inbox(gomock.Any(), int64(123), maxTime, 10).
+		Return([]domain.FeedItem{
+			{Article: domain.Article{Id: 1}, Ctime: time.UnixMilli(500)},
+			{Article: domain.Article{Id: 3}, Ctime: time.UnixMilli(300)},
+			{Article: domain.Article{Id: 4}, Ctime: time.UnixMilli(200)},
+		}, nil)
+	followRepo.EXPECT().GetFollowee(gomock.Any(), int64(123), int64(0), 1000).
+		Return([]domain.FollowRelation{
+			{Follower: 123, Followee: 456},
+			{Follower: 123, Followee: 789},
+		}, nil)
+	feedRepo.EXPECT().FilterPullAuthors(gomock.Any(), []int64{456, 789}).
+		Return([]int64{789}, nil)
+	artRepo.EXPECT().ListPubByAuthor(gomock.Any(), int64(789), maxTime, 10).
+		Return([]domain.Article{
+			{Id: 2, Title: "大 V 的文章", Status: domain.ArticleStatusPublished, Utime: time.UnixMilli(400)},
+			{Id: 3, Title: "大 V 的旧文章", Status: domain.ArticleStatusPublished, Utime: time.UnixMilli(300)},
+		}, nil)
+	artRepo.EXPECT().GetPubByID(gomock.Any(), int64(1)).
+		Return(domain.Article{Id: 1, Title: "小作者的文章", Status: domain.ArticleStatusPublished}, nil)
+	artRepo.EXPECT().GetPubByID(gomock.Any(), int64(4)).
+		Return(domain.Article{Id: 4, Status: domain.ArticleStatusPrivate}, nil)
+
+	svc := NewFeedService(feedRepo, followRepo, artRepo)
+	items, err := svc.GetFeed(context.Background(), 123, maxTime, 10)
+	require.NoError(t, err)
+	ids := make([]int64, 0, len(items))
+	for _, item := range items {
+		ids = append(ids, item.Article.Id)
+	}
+	assert.Equal(t, []int64{1, 2, 3}, ids)
+	assert.Equal(t, "小作者的文章", items[0].Article.Title)
+}
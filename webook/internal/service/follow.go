@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/wsqigo/basic-go/webook/internal/domain"
+	"github.com/wsqigo/basic-go/webook/internal/events/follow"
+	"github.com/wsqigo/basic-go/webook/internal/repository"
+	"github.com/wsqigo/basic-go/webook/pkg/logger"
+	"time"
+)
+
+var ErrFollowSelf = errors.New("不能关注自己")
+
+//go:generate mockgen -source=./follow.go -package=svcmocks -destination=./mocks/follow.mock.go FollowService
+type FollowService interface {
+	// Follow 重复关注不会报错
+	Follow(ctx context.Context, follower, followee int64) error
+	// CancelFollow 本来就没有关注不会报错
+	CancelFollow(ctx context.Context, follower, followee int64) error
+	// GetFollowee follower 关注了哪些人，按照关注时间倒序，maxId 是游标
+	GetFollowee(ctx context.Context, follower, maxId int64, limit int) ([]domain.FollowRelation, error)
+	// GetFollower followee 的粉丝
+	GetFollower(ctx context.Context, followee, maxId int64, limit int) ([]domain.FollowRelation, error)
+	// Followed follower 有没有关注 followee
+	Followed(ctx context.Context, follower, followee int64) (bool, error)
+	GetFollowStatics(ctx context.Context, uid int64) (domain.FollowStatics, error)
+}
+
+type followService struct {
+	repo     repository.FollowRepository
+	producer follow.Producer
+	l        logger.LoggerV1
+}
+
+func NewFollowService(repo repository.FollowRepository,
+	producer follow.Producer, l logger.LoggerV1) FollowService {
+	return &followService{
+		repo:     repo,
+		producer: producer,
+		l:        l,
+	}
+}
+
+func (f *followService) Follow(ctx context.Context, follower, followee int64) error {
+	if follower == followee {
+		return ErrFollowSelf
+	}
+	err := f.repo.AddFollowRelation(ctx, domain.FollowRelation{
+		Follower: follower,
+		Followee: followee,
+	})
+	if err == repository.ErrFollowRelationExists {
+		// 关系没有变化，也就不需要发消息
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	f.produceFollowEvent(follower, followee, true)
+	return nil
+}
+
+func (f *followService) CancelFollow(ctx context.Context, follower, followee int64) error {
+	err := f.repo.InactiveFollowRelation(ctx, follower, followee)
+	if err == repository.ErrFollowRelationNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	f.produceFollowEvent(follower, followee, false)
+	return nil
+}
+
+func (f *followService) GetFollowee(ctx context.Context,
+	follower, maxId int64, limit int) ([]domain.FollowRelation, error) {
+	return f.repo.GetFollowee(ctx, follower, maxId, limit)
+}
+
+func (f *followService) GetFollower(ctx context.Context,
+	followee, maxId int64, limit int) ([]domain.FollowRelation, error) {
+	return f.repo.GetFollower(ctx, followee, maxId, limit)
+}
+
+func (f *followService) Followed(ctx context.Context, follower, followee int64) (bool, error) {
+	_, err := f.repo.FollowInfo(ctx, follower, followee)
+	switch err {
+	case nil:
+		return true, nil
+	case repository.ErrFollowRelationNotFound:
+		return false, nil
+	default:
+		return false, err
+	}
+}
+
+func (f *followService) GetFollowStatics(ctx context.Context, uid int64) (domain.FollowStatics, error) {
+	return f.repo.GetFollowStatics(ctx, uid)
+}
+
+// produceFollowEvent 关注已经成功了，消息发不出去也只是记录日志
+func (f *followService) produceFollowEvent(follower, followee int64, followed bool) {
+	err := f.producer.ProduceFollowEvent(follow.FollowEvent{
+		Follower: follower,
+		Followee: followee,
+		Followed: followed,
+		Ctime:    time.Now().UnixMilli(),
+	})
+	if err != nil {
+		f.l.Error("发送关注事件失败",
+			logger.Int64("follower", follower),
+			logger.Int64("followee", followee),
+			logger.Bool("followed", followed),
+			logger.Error(err))
+	}
+}
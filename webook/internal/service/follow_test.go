@@ -0,0 +1,153 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/wsqigo/basic-go/webook/internal/domain"
+	"github.com/wsqigo/basic-go/webook/internal/events/follow"
+	evtmocks "github.com/wsqigo/basic-go/webook/internal/events/follow/mocks"
+	"github.com/wsqigo/basic-go/webook/internal/repository"
+	repomocks "github.com/wsqigo/basic-go/webook/internal/repository/mocks"
+	"github.com/wsqigo/basic-go/webook/pkg/logger"
+	"go.uber.org/mock/gomock"
+	"testing"
+)
+
+func Test_followService_Follow(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) (repository.FollowRepository, follow.Producer)
+
+		follower int64
+		followee int64
+
+		wantErr error
+	}{
+		{
+			name: "关注成功，发送事件",
+			mock: func(ctrl *gomock.Controller) (repository.FollowRepository, follow.Producer) {
+				repo := repomocks.NewMockFollowRepository(ctrl)
+				producer := evtmocks.NewMockProducer(ctrl)
+				repo.EXPECT().AddFollowRelation(gomock.Any(), domain.FollowRelation{
+					Follower: 123,
+					Followee: 456,
+				}).Return(nil)
+				producer.EXPECT().ProduceFollowEvent(gomock.Any()).
+					DoAndReturn(func(evt follow.FollowEvent) error {
+						assert.Equal(t, int64(123), evt.Follower)
+						assert.Equal(t, int64(456), evt.Followee)
+						assert.True(t, evt.Followed)
+						return nil
+					})
+				return repo, producer
+			},
+			follower: 123,
+			followee: 456,
+		},
+		{
+			name: "重复关注，不发送事件",
+			mock: func(ctrl *gomock.Controller) (repository.FollowRepository, follow.Producer) {
+				repo := repomocks.NewMockFollowRepository(ctrl)
+				producer := evtmocks.NewMockProducer(ctrl)
+				repo.EXPECT().AddFollowRelation(gomock.Any(), gomock.Any()).
+					Return(repository.ErrFollowRelationExists)
+				return repo, producer
+			},
+			follower: 123,
+			followee: 456,
+		},
+		{
+			name: "发送事件失败，关注依旧成功",
+			mock: func(ctrl *gomock.Controller) (repository.FollowRepository, follow.Producer) {
+				repo := repomocks.NewMockFollowRepository(ctrl)
+				producer := evtmocks.NewMockProducer(ctrl)
+				repo.EXPECT().AddFollowRelation(gomock.Any(), gomock.Any()).Return(nil)
+				producer.EXPECT().ProduceFollowEvent(gomock.Any()).
+					Return(errors.New("mock kafka 错误"))
+				return repo, producer
+			},
+			follower: 123,
+			followee: 456,
+		},
+		{
+			name: "关注自己",
+			mock: func(ctrl *gomock.Controller) (repository.FollowRepository, follow.Producer) {
+				return repomocks.NewMockFollowRepository(ctrl), evtmocks.NewMockProducer(ctrl)
+			},
+			follower: 123,
+			followee: 123,
+			wantErr:  ErrFollowSelf,
+		},
+		{
+			name: "数据库错误",
+			mock: func(ctrl *gomock.Controller) (repository.FollowRepository, follow.Producer) {
+				repo := repomocks.NewMockFollowRepository(ctrl)
+				producer := evtmocks.NewMockProducer(ctrl)
+				repo.EXPECT().AddFollowRelation(gomock.Any(), gomock.Any()).
+					Return(errors.New("mock db 错误"))
+				return repo, producer
+			},
+			follower: 123,
+			followee: 456,
+			wantErr:  errors.New("mock db 错误"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			repo, producer := tc.mock(ctrl)
+			svc := NewFollowService(repo, producer, logger.NewNopLogger())
+			err := svc.Follow(context.Background(), tc.follower, tc.followee)
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
+
+func Test_followService_CancelFollow(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) (repository.FollowRepository, follow.Producer)
+
+		wantErr error
+	}{
+		{
+			name: "取消关注成功",
+			mock: func(ctrl *gomock.Controller) (repository.FollowRepository, follow.Producer) {
+				repo := repomocks.NewMockFollowRepository(ctrl)
+				producer := evtmocks.NewMockProducer(ctrl)
+				repo.EXPECT().InactiveFollowRelation(gomock.Any(), int64(123), int64(456)).
+					Return(nil)
+				producer.EXPECT().ProduceFollowEvent(gomock.Any()).
+					DoAndReturn(func(evt follow.FollowEvent) error {
+						assert.False(t, evt.Followed)
+						return nil
+					})
+				return repo, producer
+			},
+		},
+		{
+			name: "本来就没有关注",
+			mock: func(ctrl *gomock.Controller) (repository.FollowRepository, follow.Producer) {
+				repo := repomocks.NewMockFollowRepository(ctrl)
+				producer := evtmocks.NewMockProducer(ctrl)
+				repo.EXPECT().InactiveFollowRelation(gomock.Any(), int64(123), int64(456)).
+					Return(repository.ErrFollowRelationNotFound)
+				return repo, producer
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			repo, producer := tc.mock(ctrl)
+			svc := NewFollowService(repo, producer, logger.NewNopLogger())
+			err := svc.CancelFollow(context.Background(), 123, 456)
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
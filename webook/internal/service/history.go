@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+	"github.com/wsqigo/basic-go/webook/internal/domain"
+	"github.com/wsqigo/basic-go/webook/internal/repository"
+	"time"
+)
+
+//go:generate mockgen -source=./history.go -package=svcmocks -destination=./mocks/history.mock.go HistoryRecordService
+type HistoryRecordService interface {
+	// List 我的阅读记录，(maxTime, maxId) 是上一页最后一条，maxTime 为零值表示第一页
+	List(ctx context.Context, uid int64, maxTime time.Time, maxId int64, limit int) ([]domain.HistoryRecord, error)
+	// Delete 删除一条或者多条阅读记录
+	Delete(ctx context.Context, uid int64, ids []int64) error
+	// Clear 清空阅读记录
+	Clear(ctx context.Context, uid int64) error
+	// DeleteExpired 分批删除阅读时间早于 before 的记录，返回一共删除的条数
+	DeleteExpired(ctx context.Context, before time.Time) (int64, error)
+}
+
+type historyRecordService struct {
+	repo repository.HistoryRecordRepository
+	// 清理过期记录的时候，一次删除多少条
+	batchSize int
+}
+
+func NewHistoryRecordService(repo repository.HistoryRecordRepository) HistoryRecordService {
+	return &historyRecordService{
+		repo:      repo,
+		batchSize: 1000,
+	}
+}
+
+func (h *historyRecordService) List(ctx context.Context, uid int64,
+	maxTime time.Time, maxId int64, limit int) ([]domain.HistoryRecord, error) {
+	return h.repo.GetRecords(ctx, uid, maxTime, maxId, limit)
+}
+
+func (h *historyRecordService) Delete(ctx context.Context, uid int64, ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return h.repo.DeleteRecords(ctx, uid, ids)
+}
+
+func (h *historyRecordService) Clear(ctx context.Context, uid int64) error {
+	return h.repo.DeleteAllRecords(ctx, uid)
+}
+
+func (h *historyRecordService) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	var total int64
+	for {
+		// 分批删除，避免一个大事务长时间锁表
+		cnt, err := h.repo.DeleteExpired(ctx, before, h.batchSize)
+		total += cnt
+		if err != nil {
+			return total, err
+		}
+		if cnt < int64(h.batchSize) {
+			return total, nil
+		}
+		if ctx.Err() != nil {
+			return total, ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/wsqigo/basic-go/webook/internal/repository"
+	repomocks "github.com/wsqigo/basic-go/webook/internal/repository/mocks"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+)
+
+func Test_historyRecordService_DeleteExpired(t *testing.T) {
+	before := time.UnixMilli(1000)
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) repository.HistoryRecordRepository
+
+		wantCnt int64
+		wantErr error
+	}{
+		{
+			name: "分批删除直到不足一批",
+			mock: func(ctrl *gomock.Controller) repository.HistoryRecordRepository {
+				repo := repomocks.NewMockHistoryRecordRepository(ctrl)
+				gomock.InOrder(
+					repo.EXPECT().DeleteExpired(gomock.Any(), before, 2).Return(int64(2), nil),
+					repo.EXPECT().DeleteExpired(gomock.Any(), before, 2).Return(int64(2), nil),
+					repo.EXPECT().DeleteExpired(gomock.Any(), before, 2).Return(int64(1), nil),
+				)
+				return repo
+			},
+			wantCnt: 5,
+		},
+		{
+			name: "没有过期记录",
+			mock: func(ctrl *gomock.Controller) repository.HistoryRecordRepository {
+				repo := repomocks.NewMockHistoryRecordRepository(ctrl)
+				repo.EXPECT().DeleteExpired(gomock.Any(), before, 2).Return(int64(0), nil)
+				return repo
+			},
+		},
+		{
+			name: "中途失败",
+			mock: func(ctrl *gomock.Controller) repository.HistoryRecordRepository {
+				repo := repomocks.NewMockHistoryRecordRepository(ctrl)
+				gomock.InOrder(
+					repo.EXPECT().DeleteExpired(gomock.Any(), before, 2).Return(int64(2), nil),
+					repo.EXPECT().DeleteExpired(gomock.Any(), before, 2).
+						Return(int64(0), errors.New("mock db error")),
+				)
+				return repo
+			},
+			wantCnt: 2,
+			wantErr: errors.New("mock db error"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc := &historyRecordService{
+				repo:      tc.mock(ctrl),
+				batchSize: 2,
+			}
+			cnt, err := svc.DeleteExpired(context.Background(), before)
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantCnt, cnt)
+		})
+	}
+}
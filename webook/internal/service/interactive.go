@@ -13,6 +13,7 @@ type InteractiveService interface {
 	Like(ctx context.Context, biz string, id int64, uid int64) error
 	CancelLike(ctx context.Context, biz string, id int64, uid int64) error
 	Collect(ctx context.Context, biz string, id int64, cid int64, uid int64) error
+	CancelCollect(ctx context.Context, biz string, id int64, uid int64) error
 	Get(ctx context.Context, biz string, id int64, uid int64) (domain.Interactive, error)
 	GetByIds(ctx context.Context, biz string, ids []int64) (map[int64]domain.Interactive, error)
 }
@@ -65,6 +66,10 @@ func (i *interactiveService) Collect(ctx context.Context, biz string, id int64,
 	return i.repo.AddCollectionItem(ctx, biz, id, cid, uid)
 }
 
+func (i *interactiveService) CancelCollect(ctx context.Context, biz string, id int64, uid int64) error {
+	return i.repo.DeleteCollectionItem(ctx, biz, id, uid)
+}
+
 func (i *interactiveService) IncrReadCnt(ctx context.Context, biz string, bizId int64) error {
 	return i.repo.IncrReadCnt(ctx, biz, bizId)
 }
@@ -2,74 +2,226 @@ package service
 
 import (
 	"context"
+	"errors"
 	"github.com/wsqigo/basic-go/webook/internal/domain"
 	"github.com/wsqigo/basic-go/webook/internal/repository"
 	"github.com/wsqigo/basic-go/webook/pkg/logger"
+	"sync"
 	"time"
 )
 
+var (
+	ErrInvalidJobExpression = errors.New("cron 表达式不合法")
+	ErrInvalidJobRetry      = errors.New("重试配置不合法")
+	ErrJobNameExists        = repository.ErrJobNameExists
+	ErrJobNotFound          = repository.ErrJobNotFound
+)
+
+//go:generate mockgen -source=./job.go -package=svcmocks -destination=./mocks/job.mock.go CronJobService
 type CronJobService interface {
 	Preempt(ctx context.Context) (domain.Job, error)
 	ResetNextTime(ctx context.Context, j domain.Job) error
+	// StartExecution 记录开始执行，返回执行记录
+	StartExecution(ctx context.Context, j domain.Job, nodeId string) (domain.JobExecution, error)
+	// FinishExecution 记录执行结果，runErr 为 nil 代表成功
+	FinishExecution(ctx context.Context, e domain.JobExecution, runErr error) error
+	// ListExecutions 查询任务的执行记录，按照时间倒序，maxId 为 0 表示第一页
+	ListExecutions(ctx context.Context, jid int64, maxId int64, limit int) ([]domain.JobExecution, error)
 	//Release(ctx context.Context, job domain.Job) error
-	// 暴露 job 的增删改查方法
+
+	// Create 校验表达式并且计算第一次调度的时间
+	Create(ctx context.Context, j domain.Job) (int64, error)
+	// Update 修改任务定义之后，会按照新的表达式重新计算下一次调度的时间
+	Update(ctx context.Context, j domain.Job) error
+	Pause(ctx context.Context, id int64) error
+	// Resume 恢复暂停了的任务，从现在开始计算下一次调度的时间
+	Resume(ctx context.Context, id int64) error
+	Delete(ctx context.Context, id int64) error
+	List(ctx context.Context, offset int, limit int) ([]domain.Job, error)
 }
 
 type cronJobService struct {
 	repo            repository.CronJobRepository
+	execRepo        repository.JobExecutionRepository
 	l               logger.LoggerV1
 	refreshInterval time.Duration
+	// 超过这么久没有续约，就认为执行的节点已经崩溃了，别的节点可以抢占
+	// 要比 refreshInterval 大好几倍，避免偶尔续约失败就被抢走
+	leaseTimeout time.Duration
 }
 
-func NewCronJobService(repo repository.CronJobRepository, l logger.LoggerV1) CronJobService {
+func NewCronJobService(repo repository.CronJobRepository,
+	execRepo repository.JobExecutionRepository, l logger.LoggerV1) CronJobService {
 	return &cronJobService{
 		repo:            repo,
+		execRepo:        execRepo,
 		l:               l,
 		refreshInterval: time.Minute,
+		leaseTimeout:    3 * time.Minute,
 	}
 }
 
 func (c *cronJobService) Preempt(ctx context.Context) (domain.Job, error) {
-	j, err := c.repo.Preempt(ctx)
+	j, err := c.repo.Preempt(ctx, c.leaseTimeout)
 	if err != nil {
 		return domain.Job{}, err
 	}
 
-	ticker := time.NewTicker(c.refreshInterval)
-	go func() {
-		for range ticker.C {
-			c.refresh(j.Id)
-		}
-	}()
+	refreshCtx, stopRefresh := context.WithCancel(context.Background())
+	leaseLost := make(chan struct{})
+	go c.keepAlive(refreshCtx, j, leaseLost)
+	j.LeaseLost = leaseLost
 	// 你抢占之后，你一直抢占着吗？
 	// 你要考虑一个释放的问题
+	var once sync.Once
 	j.CancelFunc = func() {
-		// 自己在这里释放掉
-		ticker.Stop()
-		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
-		defer cancel()
-		err := c.repo.Release(ctx, j.Id)
-		if err != nil {
-			c.l.Error("释放 job 失败",
-				logger.Error(err),
-				logger.Int64("jid", j.Id))
-		}
+		once.Do(func() {
+			// 自己在这里释放掉
+			stopRefresh()
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+			err := c.repo.Release(ctx, j.Id, j.Version)
+			if err != nil {
+				c.l.Error("释放 job 失败",
+					logger.Error(err),
+					logger.Int64("jid", j.Id))
+			}
+		})
 	}
 	return j, nil
 }
 
+// keepAlive 定时续约，直到 ctx 被取消，或者发现任务被别的节点抢走了
+func (c *cronJobService) keepAlive(ctx context.Context, j domain.Job, leaseLost chan struct{}) {
+	ticker := time.NewTicker(c.refreshInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if ctx.Err() != nil {
+				// 两个 case 同时满足的时候，select 是随机选的
+				return
+			}
+			err := c.refresh(j)
+			if err == repository.ErrJobLeaseLost {
+				c.l.Warn("任务被别的节点抢占了", logger.Int64("jid", j.Id))
+				close(leaseLost)
+				return
+			}
+		}
+	}
+}
+
 func (c *cronJobService) ResetNextTime(ctx context.Context, j domain.Job) error {
 	nextTime := j.NextTime()
+	if nextTime.IsZero() {
+		return ErrInvalidJobExpression
+	}
 	return c.repo.UpdateNextTime(ctx, j.Id, nextTime)
 }
 
-func (c *cronJobService) refresh(id int64) {
+func (c *cronJobService) StartExecution(ctx context.Context,
+	j domain.Job, nodeId string) (domain.JobExecution, error) {
+	e := domain.JobExecution{
+		Jid:       j.Id,
+		Executor:  j.Executor,
+		NodeId:    nodeId,
+		StartTime: time.Now(),
+		Status:    domain.JobExecutionStatusRunning,
+	}
+	id, err := c.execRepo.Create(ctx, e)
+	e.Id = id
+	return e, err
+}
+
+func (c *cronJobService) FinishExecution(ctx context.Context,
+	e domain.JobExecution, runErr error) error {
+	e.EndTime = time.Now()
+	e.Status = domain.JobExecutionStatusSuccess
+	if runErr != nil {
+		e.Status = domain.JobExecutionStatusFailed
+		e.ErrMsg = runErr.Error()
+	}
+	// 开始的时候没记录下来，就只更新任务上的最近一次执行结果
+	if e.Id > 0 {
+		err := c.execRepo.Finish(ctx, e)
+		if err != nil {
+			return err
+		}
+	}
+	return c.repo.UpdateLastRun(ctx, e.Jid, e.StartTime, e.ErrMsg)
+}
+
+func (c *cronJobService) ListExecutions(ctx context.Context,
+	jid int64, maxId int64, limit int) ([]domain.JobExecution, error) {
+	return c.execRepo.FindByJob(ctx, jid, maxId, limit)
+}
+
+func (c *cronJobService) Create(ctx context.Context, j domain.Job) (int64, error) {
+	if err := c.validate(j); err != nil {
+		return 0, err
+	}
+	j.Status = domain.JobStatusWaiting
+	j.NextRunTime = j.NextTime()
+	return c.repo.Create(ctx, j)
+}
+
+func (c *cronJobService) Update(ctx context.Context, j domain.Job) error {
+	if err := c.validate(j); err != nil {
+		return err
+	}
+	j.NextRunTime = j.NextTime()
+	return c.repo.Update(ctx, j)
+}
+
+func (c *cronJobService) validate(j domain.Job) error {
+	if err := j.ValidateExpression(); err != nil {
+		return ErrInvalidJobExpression
+	}
+	// 重试太多次没有意义，任务一直占着也会影响下一次调度
+	const maxRetry = 10
+	if j.RetryMax < 0 || j.RetryMax > maxRetry ||
+		(j.RetryMax > 0 && j.RetryInterval <= 0) {
+		return ErrInvalidJobRetry
+	}
+	return nil
+}
+
+func (c *cronJobService) Pause(ctx context.Context, id int64) error {
+	return c.repo.Pause(ctx, id)
+}
+
+func (c *cronJobService) Resume(ctx context.Context, id int64) error {
+	j, err := c.repo.FindById(ctx, id)
+	if err != nil {
+		return err
+	}
+	if j.Status != domain.JobStatusPaused {
+		// 本来就在调度
+		return nil
+	}
+	return c.repo.Resume(ctx, id, j.NextTime())
+}
+
+func (c *cronJobService) Delete(ctx context.Context, id int64) error {
+	return c.repo.Delete(ctx, id)
+}
+
+func (c *cronJobService) List(ctx context.Context, offset int, limit int) ([]domain.Job, error) {
+	return c.repo.List(ctx, offset, limit)
+}
+
+func (c *cronJobService) refresh(j domain.Job) error {
 	// 本质上就是更新一下时间
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
 	defer cancel()
-	err := c.repo.UpdateUtime(ctx, id)
-	if err != nil {
+	err := c.repo.UpdateUtime(ctx, j.Id, j.Version)
+	if err != nil && err != repository.ErrJobLeaseLost {
+		// 偶尔失败问题不大，只要在 leaseTimeout 之内续约成功就可以
 		c.l.Error("续约失败", logger.Error(err),
-			logger.Int64("jid", id))
+			logger.Int64("jid", j.Id))
 	}
+	return err
 }
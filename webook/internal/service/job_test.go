@@ -0,0 +1,315 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/wsqigo/basic-go/webook/internal/domain"
+	"github.com/wsqigo/basic-go/webook/internal/repository"
+	repomocks "github.com/wsqigo/basic-go/webook/internal/repository/mocks"
+	"github.com/wsqigo/basic-go/webook/pkg/logger"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+)
+
+func Test_cronJobService_Create(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) repository.CronJobRepository
+
+		job domain.Job
+
+		wantId  int64
+		wantErr error
+	}{
+		{
+			name: "创建成功",
+			mock: func(ctrl *gomock.Controller) repository.CronJobRepository {
+				repo := repomocks.NewMockCronJobRepository(ctrl)
+				repo.EXPECT().Create(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, j domain.Job) (int64, error) {
+						assert.Equal(t, domain.JobStatusWaiting, j.Status)
+						// 每分钟一次，下一次调度肯定在一分钟之内
+						assert.True(t, j.NextRunTime.After(time.Now()))
+						assert.True(t, j.NextRunTime.Before(time.Now().Add(time.Minute+time.Second)))
+						return 1, nil
+					})
+				return repo
+			},
+			job: domain.Job{
+				Name:       "ranking",
+				Executor:   "local",
+				Expression: "0 * * * * *",
+			},
+			wantId: 1,
+		},
+		{
+			name: "表达式不合法",
+			mock: func(ctrl *gomock.Controller) repository.CronJobRepository {
+				return repomocks.NewMockCronJobRepository(ctrl)
+			},
+			job: domain.Job{
+				Name:       "ranking",
+				Executor:   "local",
+				Expression: "every minute",
+			},
+			wantErr: ErrInvalidJobExpression,
+		},
+		{
+			name: "重试没有间隔",
+			mock: func(ctrl *gomock.Controller) repository.CronJobRepository {
+				return repomocks.NewMockCronJobRepository(ctrl)
+			},
+			job: domain.Job{
+				Name:       "ranking",
+				Executor:   "local",
+				Expression: "@every 1m",
+				RetryMax:   3,
+			},
+			wantErr: ErrInvalidJobRetry,
+		},
+		{
+			name: "名字重复",
+			mock: func(ctrl *gomock.Controller) repository.CronJobRepository {
+				repo := repomocks.NewMockCronJobRepository(ctrl)
+				repo.EXPECT().Create(gomock.Any(), gomock.Any()).
+					Return(int64(0), repository.ErrJobNameExists)
+				return repo
+			},
+			job: domain.Job{
+				Name:       "ranking",
+				Executor:   "local",
+				Expression: "@every 1m",
+			},
+			wantErr: ErrJobNameExists,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc := NewCronJobService(tc.mock(ctrl), nil, nil)
+			id, err := svc.Create(context.Background(), tc.job)
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantId, id)
+		})
+	}
+}
+
+func Test_cronJobService_Resume(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) repository.CronJobRepository
+
+		wantErr error
+	}{
+		{
+			name: "恢复暂停的任务",
+			mock: func(ctrl *gomock.Controller) repository.CronJobRepository {
+				repo := repomocks.NewMockCronJobRepository(ctrl)
+				repo.EXPECT().FindById(gomock.Any(), int64(1)).Return(domain.Job{
+					Id:         1,
+					Expression: "@every 1m",
+					Status:     domain.JobStatusPaused,
+				}, nil)
+				repo.EXPECT().Resume(gomock.Any(), int64(1), gomock.Any()).Return(nil)
+				return repo
+			},
+		},
+		{
+			name: "没有暂停，什么也不做",
+			mock: func(ctrl *gomock.Controller) repository.CronJobRepository {
+				repo := repomocks.NewMockCronJobRepository(ctrl)
+				repo.EXPECT().FindById(gomock.Any(), int64(1)).Return(domain.Job{
+					Id:         1,
+					Expression: "@every 1m",
+					Status:     domain.JobStatusRunning,
+				}, nil)
+				return repo
+			},
+		},
+		{
+			name: "任务不存在",
+			mock: func(ctrl *gomock.Controller) repository.CronJobRepository {
+				repo := repomocks.NewMockCronJobRepository(ctrl)
+				repo.EXPECT().FindById(gomock.Any(), int64(1)).
+					Return(domain.Job{}, repository.ErrJobNotFound)
+				return repo
+			},
+			wantErr: ErrJobNotFound,
+		},
+		{
+			name: "查询失败",
+			mock: func(ctrl *gomock.Controller) repository.CronJobRepository {
+				repo := repomocks.NewMockCronJobRepository(ctrl)
+				repo.EXPECT().FindById(gomock.Any(), int64(1)).
+					Return(domain.Job{}, errors.New("mock db error"))
+				return repo
+			},
+			wantErr: errors.New("mock db error"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc := NewCronJobService(tc.mock(ctrl), nil, nil)
+			err := svc.Resume(context.Background(), 1)
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
+
+func Test_cronJobService_FinishExecution(t *testing.T) {
+	start := time.UnixMilli(1000)
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) (repository.CronJobRepository, repository.JobExecutionRepository)
+
+		exec   domain.JobExecution
+		runErr error
+
+		wantErr error
+	}{
+		{
+			name: "执行成功",
+			mock: func(ctrl *gomock.Controller) (repository.CronJobRepository, repository.JobExecutionRepository) {
+				repo := repomocks.NewMockCronJobRepository(ctrl)
+				execRepo := repomocks.NewMockJobExecutionRepository(ctrl)
+				execRepo.EXPECT().Finish(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, e domain.JobExecution) error {
+						assert.Equal(t, int64(2), e.Id)
+						assert.Equal(t, domain.JobExecutionStatusSuccess, e.Status)
+						assert.Equal(t, "", e.ErrMsg)
+						assert.False(t, e.EndTime.IsZero())
+						return nil
+					})
+				repo.EXPECT().UpdateLastRun(gomock.Any(), int64(1), start, "").Return(nil)
+				return repo, execRepo
+			},
+			exec: domain.JobExecution{Id: 2, Jid: 1, StartTime: start},
+		},
+		{
+			name: "执行失败",
+			mock: func(ctrl *gomock.Controller) (repository.CronJobRepository, repository.JobExecutionRepository) {
+				repo := repomocks.NewMockCronJobRepository(ctrl)
+				execRepo := repomocks.NewMockJobExecutionRepository(ctrl)
+				execRepo.EXPECT().Finish(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, e domain.JobExecution) error {
+						assert.Equal(t, domain.JobExecutionStatusFailed, e.Status)
+						assert.Equal(t, "mock exec error", e.ErrMsg)
+						return nil
+					})
+				repo.EXPECT().UpdateLastRun(gomock.Any(), int64(1), start, "mock exec error").Return(nil)
+				return repo, execRepo
+			},
+			exec:   domain.JobExecution{Id: 2, Jid: 1, StartTime: start},
+			runErr: errors.New("mock exec error"),
+		},
+		{
+			name: "开始的时候没有记录下来",
+			mock: func(ctrl *gomock.Controller) (repository.CronJobRepository, repository.JobExecutionRepository) {
+				repo := repomocks.NewMockCronJobRepository(ctrl)
+				execRepo := repomocks.NewMockJobExecutionRepository(ctrl)
+				repo.EXPECT().UpdateLastRun(gomock.Any(), int64(1), start, "").Return(nil)
+				return repo, execRepo
+			},
+			exec: domain.JobExecution{Jid: 1, StartTime: start},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			repo, execRepo := tc.mock(ctrl)
+			svc := NewCronJobService(repo, execRepo, nil)
+			err := svc.FinishExecution(context.Background(), tc.exec, tc.runErr)
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
+
+func Test_cronJobService_Preempt(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) repository.CronJobRepository
+
+		// 等多久再检查续约的情况
+		wait          time.Duration
+		wantLeaseLost bool
+	}{
+		{
+			name: "一直续约成功",
+			mock: func(ctrl *gomock.Controller) repository.CronJobRepository {
+				repo := repomocks.NewMockCronJobRepository(ctrl)
+				repo.EXPECT().Preempt(gomock.Any(), time.Second).
+					Return(domain.Job{Id: 1, Version: 3}, nil)
+				repo.EXPECT().UpdateUtime(gomock.Any(), int64(1), 3).
+					Return(nil).MinTimes(1)
+				repo.EXPECT().Release(gomock.Any(), int64(1), 3).Return(nil)
+				return repo
+			},
+			wait: 50 * time.Millisecond,
+		},
+		{
+			name: "续约偶尔失败",
+			mock: func(ctrl *gomock.Controller) repository.CronJobRepository {
+				repo := repomocks.NewMockCronJobRepository(ctrl)
+				repo.EXPECT().Preempt(gomock.Any(), time.Second).
+					Return(domain.Job{Id: 1, Version: 3}, nil)
+				repo.EXPECT().UpdateUtime(gomock.Any(), int64(1), 3).
+					Return(errors.New("mock db error"))
+				repo.EXPECT().UpdateUtime(gomock.Any(), int64(1), 3).
+					Return(nil).AnyTimes()
+				repo.EXPECT().Release(gomock.Any(), int64(1), 3).Return(nil)
+				return repo
+			},
+			wait: 50 * time.Millisecond,
+		},
+		{
+			// 模拟我们这个节点卡住了太久，别的节点认为我们已经崩溃了，把任务抢走了
+			name: "任务被别的节点抢走",
+			mock: func(ctrl *gomock.Controller) repository.CronJobRepository {
+				repo := repomocks.NewMockCronJobRepository(ctrl)
+				repo.EXPECT().Preempt(gomock.Any(), time.Second).
+					Return(domain.Job{Id: 1, Version: 3}, nil)
+				repo.EXPECT().UpdateUtime(gomock.Any(), int64(1), 3).
+					Return(repository.ErrJobLeaseLost)
+				// 释放的时候带上的是自己的 version，DAO 里面不会影响新的节点
+				repo.EXPECT().Release(gomock.Any(), int64(1), 3).Return(nil)
+				return repo
+			},
+			wait:          50 * time.Millisecond,
+			wantLeaseLost: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc := &cronJobService{
+				repo:            tc.mock(ctrl),
+				l:               logger.NewNopLogger(),
+				refreshInterval: 10 * time.Millisecond,
+				leaseTimeout:    time.Second,
+			}
+			j, err := svc.Preempt(context.Background())
+			assert.NoError(t, err)
+			time.Sleep(tc.wait)
+			select {
+			case <-j.LeaseLost:
+				assert.True(t, tc.wantLeaseLost)
+			default:
+				assert.False(t, tc.wantLeaseLost)
+			}
+			j.CancelFunc()
+			// 重复调用不会重复释放
+			j.CancelFunc()
+		})
+	}
+}
@@ -0,0 +1,93 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./code_guard.go
+//
+// Generated by this command:
+//
+//	mockgen -destination=./mocks/code_guard.mock.go -package=svcmocks -source=./code_guard.go CodeSendGuard HumanVerifier
+//
+
+// Package svcmocks is a generated GoMock package.
+package svcmocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/wsqigo/basic-go/webook/internal/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockCodeSendGuard is a mock of CodeSendGuard interface.
+type MockCodeSendGuard struct {
+	ctrl     *gomock.Controller
+	recorder *MockCodeSendGuardMockRecorder
+}
+
+// MockCodeSendGuardMockRecorder is the mock recorder for MockCodeSendGuard.
+type MockCodeSendGuardMockRecorder struct {
+	mock *MockCodeSendGuard
+}
+
+// NewMockCodeSendGuard creates a new mock instance.
+func NewMockCodeSendGuard(ctrl *gomock.Controller) *MockCodeSendGuard {
+	mock := &MockCodeSendGuard{ctrl: ctrl}
+	mock.recorder = &MockCodeSendGuardMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCodeSendGuard) EXPECT() *MockCodeSendGuardMockRecorder {
+	return m.recorder
+}
+
+// Check mocks base method.
+func (m *MockCodeSendGuard) Check(ctx context.Context, req domain.CodeSendRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Check", ctx, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Check indicates an expected call of Check.
+func (mr *MockCodeSendGuardMockRecorder) Check(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Check", reflect.TypeOf((*MockCodeSendGuard)(nil).Check), ctx, req)
+}
+
+// MockHumanVerifier is a mock of HumanVerifier interface.
+type MockHumanVerifier struct {
+	ctrl     *gomock.Controller
+	recorder *MockHumanVerifierMockRecorder
+}
+
+// MockHumanVerifierMockRecorder is the mock recorder for MockHumanVerifier.
+type MockHumanVerifierMockRecorder struct {
+	mock *MockHumanVerifier
+}
+
+// NewMockHumanVerifier creates a new mock instance.
+func NewMockHumanVerifier(ctrl *gomock.Controller) *MockHumanVerifier {
+	mock := &MockHumanVerifier{ctrl: ctrl}
+	mock.recorder = &MockHumanVerifierMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHumanVerifier) EXPECT() *MockHumanVerifierMockRecorder {
+	return m.recorder
+}
+
+// Verify mocks base method.
+func (m *MockHumanVerifier) Verify(ctx context.Context, token, ip string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Verify", ctx, token, ip)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Verify indicates an expected call of Verify.
+func (mr *MockHumanVerifierMockRecorder) Verify(ctx, token, ip any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Verify", reflect.TypeOf((*MockHumanVerifier)(nil).Verify), ctx, token, ip)
+}
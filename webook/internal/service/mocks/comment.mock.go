@@ -0,0 +1,100 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./comment.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./comment.go -package=svcmocks -destination=./mocks/comment.mock.go CommentService
+//
+
+// Package svcmocks is a generated GoMock package.
+package svcmocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/wsqigo/basic-go/webook/internal/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockCommentService is a mock of CommentService interface.
+type MockCommentService struct {
+	ctrl     *gomock.Controller
+	recorder *MockCommentServiceMockRecorder
+}
+
+// MockCommentServiceMockRecorder is the mock recorder for MockCommentService.
+type MockCommentServiceMockRecorder struct {
+	mock *MockCommentService
+}
+
+// NewMockCommentService creates a new mock instance.
+func NewMockCommentService(ctrl *gomock.Controller) *MockCommentService {
+	mock := &MockCommentService{ctrl: ctrl}
+	mock.recorder = &MockCommentServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCommentService) EXPECT() *MockCommentServiceMockRecorder {
+	return m.recorder
+}
+
+// CreateComment mocks base method.
+func (m *MockCommentService) CreateComment(ctx context.Context, c domain.Comment) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateComment", ctx, c)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateComment indicates an expected call of CreateComment.
+func (mr *MockCommentServiceMockRecorder) CreateComment(ctx, c any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateComment", reflect.TypeOf((*MockCommentService)(nil).CreateComment), ctx, c)
+}
+
+// DeleteComment mocks base method.
+func (m *MockCommentService) DeleteComment(ctx context.Context, uid, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteComment", ctx, uid, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteComment indicates an expected call of DeleteComment.
+func (mr *MockCommentServiceMockRecorder) DeleteComment(ctx, uid, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteComment", reflect.TypeOf((*MockCommentService)(nil).DeleteComment), ctx, uid, id)
+}
+
+// GetCommentList mocks base method.
+func (m *MockCommentService) GetCommentList(ctx context.Context, biz string, bizId, minId int64, limit int) ([]domain.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCommentList", ctx, biz, bizId, minId, limit)
+	ret0, _ := ret[0].([]domain.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCommentList indicates an expected call of GetCommentList.
+func (mr *MockCommentServiceMockRecorder) GetCommentList(ctx, biz, bizId, minId, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCommentList", reflect.TypeOf((*MockCommentService)(nil).GetCommentList), ctx, biz, bizId, minId, limit)
+}
+
+// GetMoreReplies mocks base method.
+func (m *MockCommentService) GetMoreReplies(ctx context.Context, rid, maxId int64, limit int) ([]domain.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMoreReplies", ctx, rid, maxId, limit)
+	ret0, _ := ret[0].([]domain.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMoreReplies indicates an expected call of GetMoreReplies.
+func (mr *MockCommentServiceMockRecorder) GetMoreReplies(ctx, rid, maxId, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMoreReplies", reflect.TypeOf((*MockCommentService)(nil).GetMoreReplies), ctx, rid, maxId, limit)
+}
@@ -0,0 +1,71 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./feed.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./feed.go -package=svcmocks -destination=./mocks/feed.mock.go FeedService
+//
+
+// Package svcmocks is a generated GoMock package.
+package svcmocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	domain "github.com/wsqigo/basic-go/webook/internal/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockFeedService is a mock of FeedService interface.
+type MockFeedService struct {
+	ctrl     *gomock.Controller
+	recorder *MockFeedServiceMockRecorder
+}
+
+// MockFeedServiceMockRecorder is the mock recorder for MockFeedService.
+type MockFeedServiceMockRecorder struct {
+	mock *MockFeedService
+}
+
+// NewMockFeedService creates a new mock instance.
+func NewMockFeedService(ctrl *gomock.Controller) *MockFeedService {
+	mock := &MockFeedService{ctrl: ctrl}
+	mock.recorder = &MockFeedServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFeedService) EXPECT() *MockFeedServiceMockRecorder {
+	return m.recorder
+}
+
+// GetFeed mocks base method.
+func (m *MockFeedService) GetFeed(ctx context.Context, uid int64, maxTime time.Time, limit int) ([]domain.FeedItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFeed", ctx, uid, maxTime, limit)
+	ret0, _ := ret[0].([]domain.FeedItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFeed indicates an expected call of GetFeed.
+func (mr *MockFeedServiceMockRecorder) GetFeed(ctx, uid, maxTime, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFeed", reflect.TypeOf((*MockFeedService)(nil).GetFeed), ctx, uid, maxTime, limit)
+}
+
+// PushFeed mocks base method.
+func (m *MockFeedService) PushFeed(ctx context.Context, aid, author int64, ctime time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PushFeed", ctx, aid, author, ctime)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PushFeed indicates an expected call of PushFeed.
+func (mr *MockFeedServiceMockRecorder) PushFeed(ctx, aid, author, ctime any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PushFeed", reflect.TypeOf((*MockFeedService)(nil).PushFeed), ctx, aid, author, ctime)
+}
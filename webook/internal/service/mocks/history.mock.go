@@ -0,0 +1,100 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./history.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./history.go -package=svcmocks -destination=./mocks/history.mock.go HistoryRecordService
+//
+
+// Package svcmocks is a generated GoMock package.
+package svcmocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	domain "github.com/wsqigo/basic-go/webook/internal/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockHistoryRecordService is a mock of HistoryRecordService interface.
+type MockHistoryRecordService struct {
+	ctrl     *gomock.Controller
+	recorder *MockHistoryRecordServiceMockRecorder
+}
+
+// MockHistoryRecordServiceMockRecorder is the mock recorder for MockHistoryRecordService.
+type MockHistoryRecordServiceMockRecorder struct {
+	mock *MockHistoryRecordService
+}
+
+// NewMockHistoryRecordService creates a new mock instance.
+func NewMockHistoryRecordService(ctrl *gomock.Controller) *MockHistoryRecordService {
+	mock := &MockHistoryRecordService{ctrl: ctrl}
+	mock.recorder = &MockHistoryRecordServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHistoryRecordService) EXPECT() *MockHistoryRecordServiceMockRecorder {
+	return m.recorder
+}
+
+// Clear mocks base method.
+func (m *MockHistoryRecordService) Clear(ctx context.Context, uid int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Clear", ctx, uid)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Clear indicates an expected call of Clear.
+func (mr *MockHistoryRecordServiceMockRecorder) Clear(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Clear", reflect.TypeOf((*MockHistoryRecordService)(nil).Clear), ctx, uid)
+}
+
+// Delete mocks base method.
+func (m *MockHistoryRecordService) Delete(ctx context.Context, uid int64, ids []int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, uid, ids)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockHistoryRecordServiceMockRecorder) Delete(ctx, uid, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockHistoryRecordService)(nil).Delete), ctx, uid, ids)
+}
+
+// DeleteExpired mocks base method.
+func (m *MockHistoryRecordService) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpired", ctx, before)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpired indicates an expected call of DeleteExpired.
+func (mr *MockHistoryRecordServiceMockRecorder) DeleteExpired(ctx, before any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpired", reflect.TypeOf((*MockHistoryRecordService)(nil).DeleteExpired), ctx, before)
+}
+
+// List mocks base method.
+func (m *MockHistoryRecordService) List(ctx context.Context, uid int64, maxTime time.Time, maxId int64, limit int) ([]domain.HistoryRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, uid, maxTime, maxId, limit)
+	ret0, _ := ret[0].([]domain.HistoryRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockHistoryRecordServiceMockRecorder) List(ctx, uid, maxTime, maxId, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockHistoryRecordService)(nil).List), ctx, uid, maxTime, maxId, limit)
+}
@@ -40,6 +40,20 @@ func (m *MockInteractiveService) EXPECT() *MockInteractiveServiceMockRecorder {
 	return m.recorder
 }
 
+// CancelCollect mocks base method.
+func (m *MockInteractiveService) CancelCollect(ctx context.Context, biz string, id, uid int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelCollect", ctx, biz, id, uid)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CancelCollect indicates an expected call of CancelCollect.
+func (mr *MockInteractiveServiceMockRecorder) CancelCollect(ctx, biz, id, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelCollect", reflect.TypeOf((*MockInteractiveService)(nil).CancelCollect), ctx, biz, id, uid)
+}
+
 // CancelLike mocks base method.
 func (m *MockInteractiveService) CancelLike(ctx context.Context, biz string, id, uid int64) error {
 	m.ctrl.T.Helper()
@@ -84,18 +98,18 @@ func (mr *MockInteractiveServiceMockRecorder) Get(ctx, biz, id, uid any) *gomock
 }
 
 // GetByIds mocks base method.
-func (m *MockInteractiveService) GetByIds(ctx context.Context, s string, ids []int64) (map[int64]domain.Interactive, error) {
+func (m *MockInteractiveService) GetByIds(ctx context.Context, biz string, ids []int64) (map[int64]domain.Interactive, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByIds", ctx, s, ids)
+	ret := m.ctrl.Call(m, "GetByIds", ctx, biz, ids)
 	ret0, _ := ret[0].(map[int64]domain.Interactive)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetByIds indicates an expected call of GetByIds.
-func (mr *MockInteractiveServiceMockRecorder) GetByIds(ctx, s, ids any) *gomock.Call {
+func (mr *MockInteractiveServiceMockRecorder) GetByIds(ctx, biz, ids any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByIds", reflect.TypeOf((*MockInteractiveService)(nil).GetByIds), ctx, s, ids)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByIds", reflect.TypeOf((*MockInteractiveService)(nil).GetByIds), ctx, biz, ids)
 }
 
 // IncrReadCnt mocks base method.
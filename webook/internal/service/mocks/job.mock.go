@@ -0,0 +1,200 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./job.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./job.go -package=svcmocks -destination=./mocks/job.mock.go CronJobService
+//
+
+// Package svcmocks is a generated GoMock package.
+package svcmocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/wsqigo/basic-go/webook/internal/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockCronJobService is a mock of CronJobService interface.
+type MockCronJobService struct {
+	ctrl     *gomock.Controller
+	recorder *MockCronJobServiceMockRecorder
+}
+
+// MockCronJobServiceMockRecorder is the mock recorder for MockCronJobService.
+type MockCronJobServiceMockRecorder struct {
+	mock *MockCronJobService
+}
+
+// NewMockCronJobService creates a new mock instance.
+func NewMockCronJobService(ctrl *gomock.Controller) *MockCronJobService {
+	mock := &MockCronJobService{ctrl: ctrl}
+	mock.recorder = &MockCronJobServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCronJobService) EXPECT() *MockCronJobServiceMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockCronJobService) Create(ctx context.Context, j domain.Job) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, j)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockCronJobServiceMockRecorder) Create(ctx, j any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockCronJobService)(nil).Create), ctx, j)
+}
+
+// Delete mocks base method.
+func (m *MockCronJobService) Delete(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockCronJobServiceMockRecorder) Delete(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockCronJobService)(nil).Delete), ctx, id)
+}
+
+// FinishExecution mocks base method.
+func (m *MockCronJobService) FinishExecution(ctx context.Context, e domain.JobExecution, runErr error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FinishExecution", ctx, e, runErr)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FinishExecution indicates an expected call of FinishExecution.
+func (mr *MockCronJobServiceMockRecorder) FinishExecution(ctx, e, runErr any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinishExecution", reflect.TypeOf((*MockCronJobService)(nil).FinishExecution), ctx, e, runErr)
+}
+
+// List mocks base method.
+func (m *MockCronJobService) List(ctx context.Context, offset, limit int) ([]domain.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, offset, limit)
+	ret0, _ := ret[0].([]domain.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockCronJobServiceMockRecorder) List(ctx, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockCronJobService)(nil).List), ctx, offset, limit)
+}
+
+// ListExecutions mocks base method.
+func (m *MockCronJobService) ListExecutions(ctx context.Context, jid, maxId int64, limit int) ([]domain.JobExecution, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListExecutions", ctx, jid, maxId, limit)
+	ret0, _ := ret[0].([]domain.JobExecution)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListExecutions indicates an expected call of ListExecutions.
+func (mr *MockCronJobServiceMockRecorder) ListExecutions(ctx, jid, maxId, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListExecutions", reflect.TypeOf((*MockCronJobService)(nil).ListExecutions), ctx, jid, maxId, limit)
+}
+
+// Pause mocks base method.
+func (m *MockCronJobService) Pause(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Pause", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Pause indicates an expected call of Pause.
+func (mr *MockCronJobServiceMockRecorder) Pause(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Pause", reflect.TypeOf((*MockCronJobService)(nil).Pause), ctx, id)
+}
+
+// Preempt mocks base method.
+func (m *MockCronJobService) Preempt(ctx context.Context) (domain.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Preempt", ctx)
+	ret0, _ := ret[0].(domain.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Preempt indicates an expected call of Preempt.
+func (mr *MockCronJobServiceMockRecorder) Preempt(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Preempt", reflect.TypeOf((*MockCronJobService)(nil).Preempt), ctx)
+}
+
+// ResetNextTime mocks base method.
+func (m *MockCronJobService) ResetNextTime(ctx context.Context, j domain.Job) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetNextTime", ctx, j)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResetNextTime indicates an expected call of ResetNextTime.
+func (mr *MockCronJobServiceMockRecorder) ResetNextTime(ctx, j any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetNextTime", reflect.TypeOf((*MockCronJobService)(nil).ResetNextTime), ctx, j)
+}
+
+// Resume mocks base method.
+func (m *MockCronJobService) Resume(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Resume", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Resume indicates an expected call of Resume.
+func (mr *MockCronJobServiceMockRecorder) Resume(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Resume", reflect.TypeOf((*MockCronJobService)(nil).Resume), ctx, id)
+}
+
+// StartExecution mocks base method.
+func (m *MockCronJobService) StartExecution(ctx context.Context, j domain.Job, nodeId string) (domain.JobExecution, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartExecution", ctx, j, nodeId)
+	ret0, _ := ret[0].(domain.JobExecution)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StartExecution indicates an expected call of StartExecution.
+func (mr *MockCronJobServiceMockRecorder) StartExecution(ctx, j, nodeId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartExecution", reflect.TypeOf((*MockCronJobService)(nil).StartExecution), ctx, j, nodeId)
+}
+
+// Update mocks base method.
+func (m *MockCronJobService) Update(ctx context.Context, j domain.Job) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, j)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockCronJobServiceMockRecorder) Update(ctx, j any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockCronJobService)(nil).Update), ctx, j)
+}
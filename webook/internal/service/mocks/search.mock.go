@@ -0,0 +1,113 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./search.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./search.go -package=svcmocks -destination=./mocks/search.mock.go SearchService
+//
+
+// Package svcmocks is a generated GoMock package.
+package svcmocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/wsqigo/basic-go/webook/internal/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockSearchService is a mock of SearchService interface.
+type MockSearchService struct {
+	ctrl     *gomock.Controller
+	recorder *MockSearchServiceMockRecorder
+}
+
+// MockSearchServiceMockRecorder is the mock recorder for MockSearchService.
+type MockSearchServiceMockRecorder struct {
+	mock *MockSearchService
+}
+
+// NewMockSearchService creates a new mock instance.
+func NewMockSearchService(ctrl *gomock.Controller) *MockSearchService {
+	mock := &MockSearchService{ctrl: ctrl}
+	mock.recorder = &MockSearchServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSearchService) EXPECT() *MockSearchServiceMockRecorder {
+	return m.recorder
+}
+
+// Rebuild mocks base method.
+func (m *MockSearchService) Rebuild(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Rebuild", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Rebuild indicates an expected call of Rebuild.
+func (mr *MockSearchServiceMockRecorder) Rebuild(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rebuild", reflect.TypeOf((*MockSearchService)(nil).Rebuild), ctx)
+}
+
+// SearchArticle mocks base method.
+func (m *MockSearchService) SearchArticle(ctx context.Context, keywords string, offset, limit int) (domain.ArticleSearchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchArticle", ctx, keywords, offset, limit)
+	ret0, _ := ret[0].(domain.ArticleSearchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchArticle indicates an expected call of SearchArticle.
+func (mr *MockSearchServiceMockRecorder) SearchArticle(ctx, keywords, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchArticle", reflect.TypeOf((*MockSearchService)(nil).SearchArticle), ctx, keywords, offset, limit)
+}
+
+// SearchUser mocks base method.
+func (m *MockSearchService) SearchUser(ctx context.Context, keywords string, offset, limit int) (domain.UserSearchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchUser", ctx, keywords, offset, limit)
+	ret0, _ := ret[0].(domain.UserSearchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchUser indicates an expected call of SearchUser.
+func (mr *MockSearchServiceMockRecorder) SearchUser(ctx, keywords, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchUser", reflect.TypeOf((*MockSearchService)(nil).SearchUser), ctx, keywords, offset, limit)
+}
+
+// SyncArticle mocks base method.
+func (m *MockSearchService) SyncArticle(ctx context.Context, aid int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SyncArticle", ctx, aid)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SyncArticle indicates an expected call of SyncArticle.
+func (mr *MockSearchServiceMockRecorder) SyncArticle(ctx, aid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SyncArticle", reflect.TypeOf((*MockSearchService)(nil).SyncArticle), ctx, aid)
+}
+
+// SyncUser mocks base method.
+func (m *MockSearchService) SyncUser(ctx context.Context, uid int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SyncUser", ctx, uid)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SyncUser indicates an expected call of SyncUser.
+func (mr *MockSearchServiceMockRecorder) SyncUser(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SyncUser", reflect.TypeOf((*MockSearchService)(nil).SyncUser), ctx, uid)
+}
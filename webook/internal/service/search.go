@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"github.com/wsqigo/basic-go/webook/internal/domain"
+	"github.com/wsqigo/basic-go/webook/internal/repository"
+	"time"
+)
+
+//go:generate mockgen -source=./search.go -package=svcmocks -destination=./mocks/search.mock.go SearchService
+type SearchService interface {
+	SearchArticle(ctx context.Context, keywords string, offset, limit int) (domain.ArticleSearchResult, error)
+	SearchUser(ctx context.Context, keywords string, offset, limit int) (domain.UserSearchResult, error)
+	// SyncArticle 用线上库的数据更新索引，不再是已发表状态的文章会从索引里面删掉
+	// 发表和撤回都走这里，事件消费晚了也是按照数据库里面最新的状态更新
+	SyncArticle(ctx context.Context, aid int64) error
+	SyncUser(ctx context.Context, uid int64) error
+	// Rebuild 把所有已发表的文章和用户都写进索引
+	Rebuild(ctx context.Context) error
+}
+
+type searchService struct {
+	repo     repository.SearchRepository
+	artRepo  repository.ArticleRepository
+	userRepo repository.UserRepository
+
+	batchSize int
+}
+
+func NewSearchService(repo repository.SearchRepository,
+	artRepo repository.ArticleRepository,
+	userRepo repository.UserRepository) SearchService {
+	return &searchService{
+		repo:      repo,
+		artRepo:   artRepo,
+		userRepo:  userRepo,
+		batchSize: 100,
+	}
+}
+
+func (s *searchService) SearchArticle(ctx context.Context,
+	keywords string, offset, limit int) (domain.ArticleSearchResult, error) {
+	return s.repo.SearchArticle(ctx, keywords, offset, limit)
+}
+
+func (s *searchService) SearchUser(ctx context.Context,
+	keywords string, offset, limit int) (domain.UserSearchResult, error) {
+	return s.repo.SearchUser(ctx, keywords, offset, limit)
+}
+
+func (s *searchService) SyncArticle(ctx context.Context, aid int64) error {
+	art, err := s.artRepo.GetPubByID(ctx, aid)
+	if err == repository.ErrArticleNotFound {
+		// 数据库里面没有，也就没有更新时间，用现在的时间
+		return s.repo.DeleteArticle(ctx, aid, time.Now().UnixMilli())
+	}
+	if err != nil {
+		return err
+	}
+	if art.Status != domain.ArticleStatusPublished {
+		// 用撤回时候的更新时间当作版本，之后重新发表的数据能够写进来
+		return s.repo.DeleteArticle(ctx, aid, art.Utime.UnixMilli())
+	}
+	return s.repo.InputArticle(ctx, art)
+}
+
+func (s *searchService) SyncUser(ctx context.Context, uid int64) error {
+	u, err := s.userRepo.FindById(ctx, uid)
+	if err != nil {
+		return err
+	}
+	return s.repo.InputUser(ctx, u)
+}
+
+func (s *searchService) Rebuild(ctx context.Context) error {
+	err := s.rebuildArticles(ctx)
+	if err != nil {
+		return err
+	}
+	return s.rebuildUsers(ctx)
+}
+
+func (s *searchService) rebuildArticles(ctx context.Context) error {
+	now := time.Now()
+	offset := 0
+	for {
+		arts, err := s.artRepo.ListPub(ctx, now, offset, s.batchSize)
+		if err != nil {
+			return err
+		}
+		for _, art := range arts {
+			err = s.repo.InputArticle(ctx, art)
+			if err != nil {
+				return err
+			}
+		}
+		if len(arts) < s.batchSize {
+			return nil
+		}
+		offset += len(arts)
+	}
+}
+
+func (s *searchService) rebuildUsers(ctx context.Context) error {
+	var minId int64
+	for {
+		us, err := s.userRepo.ListAfter(ctx, minId, s.batchSize)
+		if err != nil {
+			return err
+		}
+		for _, u := range us {
+			err = s.repo.InputUser(ctx, u)
+			if err != nil {
+				return err
+			}
+		}
+		if len(us) < s.batchSize {
+			return nil
+		}
+		minId = us[len(us)-1].Id
+	}
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/wsqigo/basic-go/webook/internal/domain"
+	"github.com/wsqigo/basic-go/webook/internal/repository"
+	repomocks "github.com/wsqigo/basic-go/webook/internal/repository/mocks"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+)
+
+func Test_searchService_SyncArticle(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) (repository.SearchRepository, repository.ArticleRepository)
+
+		wantErr error
+	}{
+		{
+			name: "已发表，写入索引",
+			mock: func(ctrl *gomock.Controller) (repository.SearchRepository, repository.ArticleRepository) {
+				repo := repomocks.NewMockSearchRepository(ctrl)
+				artRepo := repomocks.NewMockArticleRepository(ctrl)
+				art := domain.Article{Id: 1, Title: "标题", Status: domain.ArticleStatusPublished}
+				artRepo.EXPECT().GetPubByID(gomock.Any(), int64(1)).Return(art, nil)
+				repo.EXPECT().InputArticle(gomock.Any(), art).Return(nil)
+				return repo, artRepo
+			},
+		},
+		{
+			name: "已撤回，从索引删除",
+			mock: func(ctrl *gomock.Controller) (repository.SearchRepository, repository.ArticleRepository) {
+				repo := repomocks.NewMockSearchRepository(ctrl)
+				artRepo := repomocks.NewMockArticleRepository(ctrl)
+				artRepo.EXPECT().GetPubByID(gomock.Any(), int64(1)).
+					Return(domain.Article{Id: 1, Status: domain.ArticleStatusPrivate, Utime: time.UnixMilli(101)}, nil)
+				repo.EXPECT().DeleteArticle(gomock.Any(), int64(1), int64(101)).Return(nil)
+				return repo, artRepo
+			},
+		},
+		{
+			name: "文章不存在，从索引删除",
+			mock: func(ctrl *gomock.Controller) (repository.SearchRepository, repository.ArticleRepository) {
+				repo := repomocks.NewMockSearchRepository(ctrl)
+				artRepo := repomocks.NewMockArticleRepository(ctrl)
+				artRepo.EXPECT().GetPubByID(gomock.Any(), int64(1)).
+					Return(domain.Article{}, repository.ErrArticleNotFound)
+				repo.EXPECT().DeleteArticle(gomock.Any(), int64(1), gomock.Any()).Return(nil)
+				return repo, artRepo
+			},
+		},
+		{
+			name: "查询文章失败",
+			mock: func(ctrl *gomock.Controller) (repository.SearchRepository, repository.ArticleRepository) {
+				repo := repomocks.NewMockSearchRepository(ctrl)
+				artRepo := repomocks.NewMockArticleRepository(ctrl)
+				artRepo.EXPECT().GetPubByID(gomock.Any(), int64(1)).
+					Return(domain.Article{}, errors.New("mock db error"))
+				return repo, artRepo
+			},
+			wantErr: errors.New("mock db error"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			repo, artRepo := tc.mock(ctrl)
+			svc := NewSearchService(repo, artRepo, nil)
+			err := svc.SyncArticle(context.Background(), 1)
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
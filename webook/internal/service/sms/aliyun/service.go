@@ -5,25 +5,48 @@ import (
 	"encoding/json"
 	"errors"
 	"github.com/aliyun/alibaba-cloud-sdk-go/services/dysmsapi"
+	"github.com/wsqigo/basic-go/webook/internal/service/sms/template"
 	"go.uber.org/zap"
 	"strings"
 )
 
+// ProviderName 模板配置里面用的服务商名字
+const ProviderName = "aliyun"
+
 type Service struct {
 	client   *dysmsapi.Client
 	signName string
+	tpls     *template.Registry
 }
 
+// Send tplId 是逻辑上的模板名字，发送之前解析成阿里云的模板 ID
 func (s *Service) Send(ctx context.Context, tplId string, args []string, numbers ...string) error {
+	ptpl, err := s.tpls.Resolve(tplId, ProviderName, args)
+	if err != nil {
+		return err
+	}
+	tpl, err := s.tpls.Get(tplId)
+	if err != nil {
+		return err
+	}
 	request := dysmsapi.CreateSendSmsRequest()
 	request.Scheme = "https"
 	request.SignName = s.signName
-	request.TemplateCode = tplId
+	if ptpl.SignName != "" {
+		request.SignName = ptpl.SignName
+	}
+	request.TemplateCode = ptpl.Id
 	request.PhoneNumbers = strings.Join(numbers, ",")
 
-	byteParams, err := json.Marshal(map[string]string{
-		"code": args[0],
-	})
+	// 阿里云按照名字传参数
+	params := make(map[string]string, len(args))
+	for i, p := range tpl.Params {
+		params[p.Name] = args[i]
+	}
+	byteParams, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
 	request.TemplateParam = string(byteParams)
 
 	response, err := s.client.SendSms(request)
@@ -40,9 +63,10 @@ func (s *Service) Send(ctx context.Context, tplId string, args []string, numbers
 	return nil
 }
 
-func NewService(client *dysmsapi.Client, signName string) *Service {
+func NewService(client *dysmsapi.Client, signName string, tpls *template.Registry) *Service {
 	return &Service{
 		client:   client,
 		signName: signName,
+		tpls:     tpls,
 	}
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"github.com/aliyun/alibaba-cloud-sdk-go/services/dysmsapi"
 	"github.com/stretchr/testify/assert"
+	"github.com/wsqigo/basic-go/webook/internal/service/sms/template"
 	"os"
 	"testing"
 )
@@ -17,7 +18,19 @@ func TestSender(t *testing.T) {
 		return
 	}
 
-	s := NewService(c, "量链科技")
+	tpls, err := template.NewRegistry([]template.Template{
+		{
+			Name:   "login_code",
+			Params: []template.Param{{Name: "code", Pattern: `^\d{6}$`}},
+			Providers: map[string]template.ProviderTemplate{
+				ProviderName: {Id: "SMS_186030080"},
+			},
+		},
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+	s := NewService(c, "量链科技", tpls)
 
 	testCases := []struct {
 		name    string
@@ -28,7 +41,7 @@ func TestSender(t *testing.T) {
 	}{
 		{
 			name:   "发送验证码",
-			tplId:  "SMS_186030080",
+			tplId:  "login_code",
 			params: []string{"123456"},
 			// 改成你的手机号
 			numbers: []string{"19124155294"},
@@ -0,0 +1,93 @@
+package async
+
+import (
+	"sync"
+	"time"
+)
+
+type HealthConfig struct {
+	// 根据最近多少次同步发送的结果来判断
+	WindowSize int `yaml:"windowSize"`
+	// 错误率超过这个值就转异步
+	ErrRate float64 `yaml:"errRate"`
+	// 平均响应时间超过这个值就转异步
+	Latency time.Duration `yaml:"latency"`
+	// 转异步之后持续多久，之后恢复同步发送重新判断
+	AsyncDuration time.Duration `yaml:"asyncDuration"`
+}
+
+func DefaultHealthConfig() HealthConfig {
+	return HealthConfig{
+		WindowSize:    100,
+		ErrRate:       0.3,
+		Latency:       500 * time.Millisecond,
+		AsyncDuration: time.Minute,
+	}
+}
+
+// HealthChecker 根据最近一批同步发送的错误率和平均响应时间，判断服务商是不是健康
+type HealthChecker struct {
+	cfg HealthConfig
+
+	lock sync.Mutex
+	// 环形缓冲，记录最近 WindowSize 次的结果
+	results []sendResult
+	idx     int
+	cnt     int
+	// 在这之前都转异步
+	asyncUntil time.Time
+}
+
+type sendResult struct {
+	duration time.Duration
+	failed   bool
+}
+
+func NewHealthChecker(cfg HealthConfig) *HealthChecker {
+	// 没有配置的话窗口是空的，上报的时候会除以 0
+	if cfg.WindowSize <= 0 {
+		cfg.WindowSize = DefaultHealthConfig().WindowSize
+	}
+	return &HealthChecker{
+		cfg:     cfg,
+		results: make([]sendResult, cfg.WindowSize),
+	}
+}
+
+// Healthy 不健康的时候要转异步
+func (h *HealthChecker) Healthy() bool {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	return !time.Now().Before(h.asyncUntil)
+}
+
+// Report 上报一次同步发送的结果
+func (h *HealthChecker) Report(duration time.Duration, err error) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.results[h.idx] = sendResult{duration: duration, failed: err != nil}
+	h.idx = (h.idx + 1) % len(h.results)
+	if h.cnt < len(h.results) {
+		h.cnt++
+	}
+	// 样本太少，判断不准
+	if h.cnt < len(h.results) {
+		return
+	}
+	var failed int
+	var total time.Duration
+	for _, res := range h.results {
+		total += res.duration
+		if res.failed {
+			failed++
+		}
+	}
+	errRate := float64(failed) / float64(h.cnt)
+	latency := total / time.Duration(h.cnt)
+	if errRate > h.cfg.ErrRate || latency > h.cfg.Latency {
+		h.asyncUntil = time.Now().Add(h.cfg.AsyncDuration)
+		// 恢复同步之后重新积累样本，不然马上又会转异步
+		h.idx = 0
+		h.cnt = 0
+	}
+}
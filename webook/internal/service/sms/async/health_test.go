@@ -0,0 +1,91 @@
+package async
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestHealthChecker_Report(t *testing.T) {
+	mockErr := errors.New("mock error")
+	testCases := []struct {
+		name      string
+		durations []time.Duration
+		errs      []error
+
+		wantHealthy bool
+	}{
+		{
+			name:        "样本不够",
+			durations:   []time.Duration{time.Second, time.Second},
+			errs:        []error{mockErr, mockErr},
+			wantHealthy: true,
+		},
+		{
+			name:        "健康",
+			durations:   []time.Duration{time.Millisecond, time.Millisecond, time.Millisecond, time.Millisecond},
+			errs:        []error{nil, mockErr, nil, nil},
+			wantHealthy: true,
+		},
+		{
+			name:        "错误率太高",
+			durations:   []time.Duration{time.Millisecond, time.Millisecond, time.Millisecond, time.Millisecond},
+			errs:        []error{nil, mockErr, mockErr, nil},
+			wantHealthy: false,
+		},
+		{
+			name:        "平均响应时间太长",
+			durations:   []time.Duration{time.Millisecond, time.Millisecond, time.Millisecond, time.Second},
+			errs:        []error{nil, nil, nil, nil},
+			wantHealthy: false,
+		},
+		{
+			name: "只看最近的样本",
+			durations: []time.Duration{time.Millisecond, time.Millisecond,
+				time.Millisecond, time.Millisecond, time.Millisecond, time.Millisecond},
+			// 第一次失败已经滑出去了，不然就是 50%
+			errs:        []error{mockErr, nil, nil, nil, mockErr, nil},
+			wantHealthy: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			h := NewHealthChecker(HealthConfig{
+				WindowSize:    4,
+				ErrRate:       0.3,
+				Latency:       100 * time.Millisecond,
+				AsyncDuration: time.Minute,
+			})
+			for i, d := range tc.durations {
+				h.Report(d, tc.errs[i])
+			}
+			assert.Equal(t, tc.wantHealthy, h.Healthy())
+		})
+	}
+}
+
+func TestHealthChecker_Recover(t *testing.T) {
+	h := NewHealthChecker(HealthConfig{
+		WindowSize:    2,
+		ErrRate:       0.3,
+		Latency:       100 * time.Millisecond,
+		AsyncDuration: 50 * time.Millisecond,
+	})
+	h.Report(time.Millisecond, errors.New("mock error"))
+	h.Report(time.Millisecond, errors.New("mock error"))
+	assert.False(t, h.Healthy())
+	time.Sleep(60 * time.Millisecond)
+	// 过了异步的时间，恢复同步
+	assert.True(t, h.Healthy())
+	// 样本重新积累，一次失败不会马上转异步
+	h.Report(time.Millisecond, errors.New("mock error"))
+	assert.True(t, h.Healthy())
+}
+
+func TestHealthChecker_ZeroConfig(t *testing.T) {
+	h := NewHealthChecker(HealthConfig{})
+	// 窗口大小用默认值，不能 panic
+	h.Report(time.Millisecond, nil)
+	assert.Equal(t, DefaultHealthConfig().WindowSize, len(h.results))
+}
@@ -2,113 +2,168 @@ package async
 
 import (
 	"context"
+	"errors"
 	"github.com/wsqigo/basic-go/webook/internal/domain"
 	"github.com/wsqigo/basic-go/webook/internal/repository"
 	"github.com/wsqigo/basic-go/webook/internal/service/sms"
 	"github.com/wsqigo/basic-go/webook/pkg/logger"
+	"sync"
 	"time"
 )
 
+type Config struct {
+	// 异步发送最多尝试多少次
+	RetryMax int `yaml:"retryMax"`
+	// 每个节点有多少个 goroutine 在异步发送
+	Workers int          `yaml:"workers"`
+	Health  HealthConfig `yaml:"health"`
+}
+
+func DefaultConfig() Config {
+	return Config{
+		RetryMax: 3,
+		Workers:  1,
+		Health:   DefaultHealthConfig(),
+	}
+}
+
 type Service struct {
 	svc sms.Service
 	// 转异步，存储发短信请求的 repository
-	repo repository.AsyncSmsRepository
-	l    logger.LoggerV1
+	repo   repository.AsyncSmsRepository
+	health *HealthChecker
+	cfg    Config
+	l      logger.LoggerV1
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
 }
 
 func NewService(svc sms.Service,
 	repo repository.AsyncSmsRepository,
+	cfg Config,
 	l logger.LoggerV1) *Service {
-	res := &Service{
-		svc:  svc,
-		repo: repo,
-		l:    l,
+	return &Service{
+		svc:    svc,
+		repo:   repo,
+		health: NewHealthChecker(cfg.Health),
+		cfg:    cfg,
+		l:      l,
 	}
-	go func() {
-		res.StartAsyncCycle()
-	}()
-	return res
 }
 
-// StartAsyncCycle 异步发送消息
-// 这里我们没有设计退出机制，是因为没啥必要
-// 因为程序停止的时候，它自然就停止了
-// 原理：这是最简单的抢占式调度
-func (s *Service) StartAsyncCycle() {
-	// 这个是我为了测试而引入的，防止你在运行测试的时候，会出现偶发性的失败
-	time.Sleep(3 * time.Second)
-	for {
-		s.AsyncSend()
+// Start 启动 Workers 个 goroutine 异步发送
+// 原理：这是最简单的抢占式调度，多个节点同时跑，一条短信也只有一个节点能抢到
+func (s *Service) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	for i := 0; i < s.cfg.Workers; i++ {
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.StartAsyncCycle(ctx)
+		}()
+	}
+}
+
+// Close 等正在发送的短信发送完毕
+func (s *Service) Close() error {
+	if s.cancel == nil {
+		return nil
 	}
+	s.cancel()
+	s.wg.Wait()
+	return nil
 }
 
-func (s *Service) AsyncSend() {
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+// StartAsyncCycle 异步发送消息，直到 ctx 取消
+func (s *Service) StartAsyncCycle(ctx context.Context) {
+	for ctx.Err() == nil {
+		s.AsyncSend(ctx)
+	}
+}
+
+func (s *Service) AsyncSend(ctx context.Context) {
+	pctx, cancel := context.WithTimeout(ctx, time.Second)
 	// 抢占一个异步发送的消息，确保在非常多个实例
 	// 比如 k8s 部署了三个 pod，一个请求，只有一个实例能拿到
-	as, err := s.repo.PreemptWaitingSMS(ctx)
+	as, err := s.repo.PreemptWaitingSMS(pctx)
 	cancel()
-	switch err {
-	case nil:
-		// 执行发送
-		// 这个也可以做成配置的
-		ctx, cancel = context.WithTimeout(context.Background(), time.Second)
+	switch {
+	case err == nil:
+		// 已经抢占到了，即便要退出也发送完，不然要等一分钟之后才会被再次抢占
+		sctx, cancel := context.WithTimeout(context.Background(), time.Second)
 		defer cancel()
-		err = s.svc.Send(ctx, as.TplId, as.Args, as.Numbers...)
+		err = s.svc.Send(sctx, as.TplId, as.Args, as.Numbers...)
 		if err != nil {
-			// 啥也不需要干
+			// 没有用完重试次数的，一分钟之后会被再次抢占
 			s.l.Error("执行异步发送短信失败",
 				logger.Error(err),
 				logger.Int64("id", as.Id))
 		}
 		res := err == nil
 		// 通知 repository 我这一次的执行结果
-		err = s.repo.ReportScheduleResult(ctx, as.Id, res)
+		// 发送可能用掉了几乎全部的时间，标记数据库要有自己的超时，
+		// 不然发送成功了却没标记上，一分钟之后又会再发一次
+		rctx, rcancel := context.WithTimeout(context.Background(), time.Second)
+		err = s.repo.ReportScheduleResult(rctx, as.Id, res)
+		rcancel()
 		if err != nil {
 			s.l.Error("执行异步发送短信成功，但是标记数据库失败",
 				logger.Error(err),
 				logger.Bool("res", res),
 				logger.Int64("id", as.Id))
 		}
-	case repository.ErrWaitingSMSNotFound:
+	case errors.Is(err, repository.ErrWaitingSMSNotFound):
 		// 睡一秒。这个你可以自己决定
-		time.Sleep(time.Second)
+		sleep(ctx, time.Second)
 	default:
 		// 正常来说应该是数据库那边出了问题
 		// 但是为了尽量运行，还是要继续的
-		// 你可以稍微睡眠，也可以补睡眠
 		// 睡眠的话可以帮你规避掉短时间的网络抖动问题
 		s.l.Error("抢占异步发送短信任务失败",
 			logger.Error(err))
-		time.Sleep(time.Second)
+		sleep(ctx, time.Second)
 	}
 }
 
 func (s *Service) Send(ctx context.Context, tplId string, args []string, numbers ...string) error {
 	if s.needAsync() {
-		err := s.repo.Add(ctx, domain.AsyncSms{
-			TplId:   tplId,
-			Args:    args,
-			Numbers: numbers,
-			// 设置可以重试三次
-			RetryMax: 3,
+		return s.repo.Add(ctx, domain.AsyncSms{
+			TplId:    tplId,
+			Args:     args,
+			Numbers:  numbers,
+			RetryMax: s.cfg.RetryMax,
 		})
-		return err
 	}
-	return s.svc.Send(ctx, tplId, args, numbers...)
+	start := time.Now()
+	err := s.svc.Send(ctx, tplId, args, numbers...)
+	s.health.Report(time.Since(start), err)
+	return err
+}
+
+// DeleteFinished 删除 before 之前就已经发送成功或者彻底失败的记录
+func (s *Service) DeleteFinished(ctx context.Context, before time.Time) (int64, error) {
+	const batchSize = 1000
+	var total int64
+	for {
+		cnt, err := s.repo.DeleteFinished(ctx, before, batchSize)
+		total += cnt
+		if err != nil || cnt < batchSize {
+			return total, err
+		}
+	}
 }
 
-// 提前引导你们，开始思考系统容错问题
-// 你们面试装逼，赢得竞争优势就靠这一类的东西
+// needAsync 最近一段时间错误率或者平均响应时间超过阈值，就在接下来的一段时间里面转异步
+// 一段时间之后恢复同步发送，重新判断
 func (s *Service) needAsync() bool {
-	// 这边就是你要设计的，各种判定要不要触发异步的方案
-	// 1. 基于响应时间的，平均响应时间
-	// 1.1 使用绝对阈值，比如说直接发送的时候，（连续一段时间，或者连续N个请求）响应时间超过了 500ms，然后后续请求转异步
-	// 1.2 变化趋势，比如说当前一秒钟内的所有请求的响应时间比上一秒钟增长了 X%，就转异步
-	// 2. 基于错误率：一段时间内，收到 err 的请求比率大于 X%，转异步
+	return !s.health.Healthy()
+}
 
-	// 什么时候退出异步
-	// 1. 进入异步 N 分钟后
-	// 2. 保留 1% 的流量（或者更少），继续同步发送，判定响应时间/错误率
-	return true
+func sleep(ctx context.Context, d time.Duration) {
+	select {
+	case <-ctx.Done():
+	case <-time.After(d):
+	}
 }
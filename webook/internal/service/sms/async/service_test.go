@@ -0,0 +1,109 @@
+package async
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/wsqigo/basic-go/webook/internal/domain"
+	"github.com/wsqigo/basic-go/webook/internal/repository"
+	repomocks "github.com/wsqigo/basic-go/webook/internal/repository/mocks"
+	"github.com/wsqigo/basic-go/webook/internal/service/sms"
+	smsmocks "github.com/wsqigo/basic-go/webook/internal/service/sms/mocks"
+	"github.com/wsqigo/basic-go/webook/pkg/logger"
+	"go.uber.org/mock/gomock"
+	"testing"
+)
+
+func TestService_AsyncSend(t *testing.T) {
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) (sms.Service, repository.AsyncSmsRepository)
+	}{
+		{
+			name: "发送成功",
+			mock: func(ctrl *gomock.Controller) (sms.Service, repository.AsyncSmsRepository) {
+				svc := smsmocks.NewMockService(ctrl)
+				repo := repomocks.NewMockAsyncSmsRepository(ctrl)
+				repo.EXPECT().PreemptWaitingSMS(gomock.Any()).Return(domain.AsyncSms{
+					Id: 1, TplId: "tpl", Args: []string{"123"}, Numbers: []string{"152"},
+				}, nil)
+				svc.EXPECT().Send(gomock.Any(), "tpl", []string{"123"}, "152").Return(nil)
+				repo.EXPECT().ReportScheduleResult(gomock.Any(), int64(1), true).Return(nil)
+				return svc, repo
+			},
+		},
+		{
+			name: "发送失败",
+			mock: func(ctrl *gomock.Controller) (sms.Service, repository.AsyncSmsRepository) {
+				svc := smsmocks.NewMockService(ctrl)
+				repo := repomocks.NewMockAsyncSmsRepository(ctrl)
+				repo.EXPECT().PreemptWaitingSMS(gomock.Any()).Return(domain.AsyncSms{
+					Id: 1, TplId: "tpl", Args: []string{"123"}, Numbers: []string{"152"},
+				}, nil)
+				svc.EXPECT().Send(gomock.Any(), "tpl", []string{"123"}, "152").
+					Return(errors.New("mock provider error"))
+				repo.EXPECT().ReportScheduleResult(gomock.Any(), int64(1), false).Return(nil)
+				return svc, repo
+			},
+		},
+		{
+			name: "发送用完了超时，标记数据库还有时间",
+			mock: func(ctrl *gomock.Controller) (sms.Service, repository.AsyncSmsRepository) {
+				svc := smsmocks.NewMockService(ctrl)
+				repo := repomocks.NewMockAsyncSmsRepository(ctrl)
+				repo.EXPECT().PreemptWaitingSMS(gomock.Any()).Return(domain.AsyncSms{
+					Id: 1, TplId: "tpl", Args: []string{"123"}, Numbers: []string{"152"},
+				}, nil)
+				svc.EXPECT().Send(gomock.Any(), "tpl", []string{"123"}, "152").
+					DoAndReturn(func(ctx context.Context, tplId string, args []string, numbers ...string) error {
+						<-ctx.Done()
+						return nil
+					})
+				repo.EXPECT().ReportScheduleResult(gomock.Any(), int64(1), true).
+					DoAndReturn(func(ctx context.Context, id int64, res bool) error {
+						if ctx.Err() != nil {
+							ctrl.T.Errorf("标记数据库的时候已经超时了 %v", ctx.Err())
+						}
+						return ctx.Err()
+					})
+				return svc, repo
+			},
+		},
+		{
+			name: "没有待发送的",
+			mock: func(ctrl *gomock.Controller) (sms.Service, repository.AsyncSmsRepository) {
+				svc := smsmocks.NewMockService(ctrl)
+				repo := repomocks.NewMockAsyncSmsRepository(ctrl)
+				repo.EXPECT().PreemptWaitingSMS(gomock.Any()).
+					Return(domain.AsyncSms{}, repository.ErrWaitingSMSNotFound)
+				return svc, repo
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc, repo := tc.mock(ctrl)
+			s := NewService(svc, repo, DefaultConfig(), logger.NewNopLogger())
+			// 取消的 ctx 不用睡眠
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			s.AsyncSend(ctx)
+		})
+	}
+}
+
+func TestService_StartClose(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	repo := repomocks.NewMockAsyncSmsRepository(ctrl)
+	repo.EXPECT().PreemptWaitingSMS(gomock.Any()).
+		Return(domain.AsyncSms{}, repository.ErrWaitingSMSNotFound).AnyTimes()
+	cfg := DefaultConfig()
+	cfg.Workers = 3
+	s := NewService(smsmocks.NewMockService(ctrl), repo, cfg, logger.NewNopLogger())
+	s.Start()
+	// 在睡眠的 worker 也要马上退出
+	assert.NoError(t, s.Close())
+}
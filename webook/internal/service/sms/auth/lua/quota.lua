@@ -0,0 +1,19 @@
+-- 调用方当天的配额
+local key = KEYS[1]
+-- 这次要发多少条
+local n = tonumber(ARGV[1])
+-- 一天最多多少条
+local limit = tonumber(ARGV[2])
+-- 过期时间，毫秒
+local expiration = tonumber(ARGV[3])
+
+local cnt = tonumber(redis.call('GET', key) or '0')
+if cnt + n > limit then
+    -- 不够的话一条都不扣
+    return 1
+end
+if redis.call('INCRBY', key, n) == n then
+    -- 当天第一次发送
+    redis.call('PEXPIRE', key, expiration)
+end
+return 0
@@ -0,0 +1,55 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./quota.go
+//
+// Generated by this command:
+//
+//	mockgen -destination=./mocks/quota.mock.go -package=authmocks -source=./quota.go Quota
+//
+
+// Package authmocks is a generated GoMock package.
+package authmocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockQuota is a mock of Quota interface.
+type MockQuota struct {
+	ctrl     *gomock.Controller
+	recorder *MockQuotaMockRecorder
+}
+
+// MockQuotaMockRecorder is the mock recorder for MockQuota.
+type MockQuotaMockRecorder struct {
+	mock *MockQuota
+}
+
+// NewMockQuota creates a new mock instance.
+func NewMockQuota(ctrl *gomock.Controller) *MockQuota {
+	mock := &MockQuota{ctrl: ctrl}
+	mock.recorder = &MockQuotaMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockQuota) EXPECT() *MockQuotaMockRecorder {
+	return m.recorder
+}
+
+// Take mocks base method.
+func (m *MockQuota) Take(ctx context.Context, caller string, n int) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Take", ctx, caller, n)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Take indicates an expected call of Take.
+func (mr *MockQuotaMockRecorder) Take(ctx, caller, n any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Take", reflect.TypeOf((*MockQuota)(nil).Take), ctx, caller, n)
+}
@@ -0,0 +1,44 @@
+package auth
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"github.com/redis/go-redis/v9"
+	"time"
+)
+
+//go:embed lua/quota.lua
+var luaQuota string
+
+// Quota 调用方的配额，一次发给多少个号码就扣多少
+//
+//go:generate mockgen -destination=./mocks/quota.mock.go -package=authmocks -source=./quota.go Quota
+type Quota interface {
+	// Take 扣掉 n 条配额，配额不够返回 false，这时候一条都不扣
+	Take(ctx context.Context, caller string, n int) (bool, error)
+}
+
+// RedisDailyQuota 按照自然日计算，每天零点重新开始
+type RedisDailyQuota struct {
+	cmd   redis.Cmdable
+	limit int
+}
+
+func NewRedisDailyQuota(cmd redis.Cmdable, limit int) Quota {
+	return &RedisDailyQuota{
+		cmd:   cmd,
+		limit: limit,
+	}
+}
+
+func (q *RedisDailyQuota) Take(ctx context.Context, caller string, n int) (bool, error) {
+	key := fmt.Sprintf("sms_api:quota:%s:%s", caller, time.Now().Format("20060102"))
+	// key 里面带了日期，过期时间只是为了清理掉前一天的 key
+	res, err := q.cmd.Eval(ctx, luaQuota, []string{key},
+		n, q.limit, (48 * time.Hour).Milliseconds()).Int()
+	if err != nil {
+		return false, err
+	}
+	return res == 0, nil
+}
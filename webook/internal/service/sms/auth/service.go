@@ -2,29 +2,138 @@ package auth
 
 import (
 	"context"
-	"github.com/golang-jwt/jwt/v5"
+	"errors"
+	"fmt"
 	"github.com/wsqigo/basic-go/webook/internal/service/sms"
+	"github.com/wsqigo/basic-go/webook/internal/service/sms/template"
+	"github.com/wsqigo/basic-go/webook/pkg/logger"
 )
 
+var (
+	ErrUnknownCaller      = errors.New("未知的调用方")
+	ErrTemplateNotAllowed = errors.New("调用方不能使用这个模板")
+	ErrQuotaExceeded      = errors.New("调用方的短信配额用完了")
+	ErrTooManyNumbers     = errors.New("一次发送的手机号码太多")
+	ErrEmptyNumbers       = errors.New("没有手机号码")
+)
+
+const maxNumbersPerRequest = 100
+
+// Service 给其它服务用的发送短信的接口，调用方通过 token 证明自己的身份
+//
+//go:generate mockgen -destination=./mocks/service.mock.go -package=authmocks -source=./service.go Service
+type Service interface {
+	Send(ctx context.Context, token string, tpl string, args []string, numbers ...string) error
+}
+
+// Caller 调用方，一般是一个业务团队
+type Caller struct {
+	// 允许使用的逻辑模板
+	Templates []string
+	// 按照手机号码的个数计算配额
+	Quota Quota
+}
+
 type SMSService struct {
-	svc sms.Service
-	key []byte
+	svc     sms.Service
+	tokens  *TokenManager
+	tpls    *template.Registry
+	callers map[string]Caller
+	l       logger.LoggerV1
+}
+
+func NewSMSService(svc sms.Service, tokens *TokenManager, tpls *template.Registry,
+	callers map[string]Caller, l logger.LoggerV1) Service {
+	return &SMSService{
+		svc:     svc,
+		tokens:  tokens,
+		tpls:    tpls,
+		callers: callers,
+		l:       l,
+	}
+}
+
+// Send 校验 token，模板，参数和配额之后走 webook 的服务商链路发送
+// 每一次调用都会记录审计日志，手机号码只记录掩码
+func (s *SMSService) Send(ctx context.Context, token string, tpl string, args []string, numbers ...string) error {
+	name, err := s.tokens.Parse(token)
+	if err != nil {
+		s.l.Warn("短信接口 token 不对", logger.String("tpl", tpl), logger.Error(err))
+		return err
+	}
+	err = s.check(ctx, name, tpl, args, numbers)
+	if err != nil {
+		s.l.Warn("短信接口拒绝发送", s.auditFields(name, tpl, numbers, err)...)
+		return err
+	}
+	err = s.svc.Send(ctx, tpl, args, numbers...)
+	if err != nil {
+		s.l.Error("短信接口发送失败", s.auditFields(name, tpl, numbers, err)...)
+		return err
+	}
+	s.l.Info("短信接口发送", s.auditFields(name, tpl, numbers, nil)...)
+	return nil
 }
 
-func (s *SMSService) Send(ctx context.Context, tplToken string, args []string, numbers ...string) error {
-	var claims SMSClaims
-	_, err := jwt.ParseWithClaims(tplToken, &claims, func(token *jwt.Token) (interface{}, error) {
-		return s.key, nil
-	})
+func (s *SMSService) check(ctx context.Context, name string, tpl string, args []string, numbers []string) error {
+	caller, ok := s.callers[name]
+	if !ok {
+		return fmt.Errorf("%w %s", ErrUnknownCaller, name)
+	}
+	if len(numbers) == 0 {
+		return ErrEmptyNumbers
+	}
+	if len(numbers) > maxNumbersPerRequest {
+		return ErrTooManyNumbers
+	}
+	if !caller.allow(tpl) {
+		return fmt.Errorf("%w %s", ErrTemplateNotAllowed, tpl)
+	}
+	// 参数不对的话，就算转了异步也发不出去，所以在这里就拦下来
+	err := s.tpls.Validate(tpl, args)
 	if err != nil {
 		return err
 	}
+	// 一次性扣掉所有号码的配额
+	ok, err = caller.Quota.Take(ctx, name, len(numbers))
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrQuotaExceeded
+	}
+	return nil
+}
 
-	return s.svc.Send(ctx, claims.Tpl, args, numbers...)
+func (s *SMSService) auditFields(caller string, tpl string, numbers []string, err error) []logger.Field {
+	masked := make([]string, 0, len(numbers))
+	for _, n := range numbers {
+		masked = append(masked, maskPhone(n))
+	}
+	fields := []logger.Field{
+		logger.String("caller", caller),
+		logger.String("tpl", tpl),
+		{Key: "numbers", Val: masked},
+	}
+	if err != nil {
+		fields = append(fields, logger.Error(err))
+	}
+	return fields
 }
 
-type SMSClaims struct {
-	jwt.RegisteredClaims
-	Tpl string
-	// 额外加字段
+func (c Caller) allow(tpl string) bool {
+	for _, t := range c.Templates {
+		if t == tpl {
+			return true
+		}
+	}
+	return false
+}
+
+// maskPhone 只保留前三位和后四位
+func maskPhone(phone string) string {
+	if len(phone) < 7 {
+		return "****"
+	}
+	return phone[:3] + "****" + phone[len(phone)-4:]
 }
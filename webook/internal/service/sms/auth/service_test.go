@@ -0,0 +1,219 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wsqigo/basic-go/webook/internal/service/sms"
+	authmocks "github.com/wsqigo/basic-go/webook/internal/service/sms/auth/mocks"
+	smsmocks "github.com/wsqigo/basic-go/webook/internal/service/sms/mocks"
+	"github.com/wsqigo/basic-go/webook/internal/service/sms/template"
+	"github.com/wsqigo/basic-go/webook/pkg/logger"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+)
+
+func TestTokenManager_Rotate(t *testing.T) {
+	oldKey := Key{Id: "k1", Secret: "old secret 0123456789abcdef0123456789"}
+	newKey := Key{Id: "k2", Secret: "new secret 0123456789abcdef0123456789"}
+	before, err := NewTokenManager([]Key{oldKey}, "k1")
+	require.NoError(t, err)
+	oldToken, err := before.Issue("order", time.Hour)
+	require.NoError(t, err)
+
+	// 加上新密钥并且切换，旧的 token 还能用
+	rotating, err := NewTokenManager([]Key{oldKey, newKey}, "k2")
+	require.NoError(t, err)
+	newToken, err := rotating.Issue("order", time.Hour)
+	require.NoError(t, err)
+	caller, err := rotating.Parse(oldToken)
+	require.NoError(t, err)
+	assert.Equal(t, "order", caller)
+	caller, err = rotating.Parse(newToken)
+	require.NoError(t, err)
+	assert.Equal(t, "order", caller)
+
+	// 删掉旧密钥之后，旧的 token 就不能用了
+	after, err := NewTokenManager([]Key{newKey}, "k2")
+	require.NoError(t, err)
+	_, err = after.Parse(oldToken)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+	_, err = after.Parse(newToken)
+	assert.NoError(t, err)
+
+	expired, err := after.Issue("order", -time.Minute)
+	require.NoError(t, err)
+	_, err = after.Parse(expired)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+
+	_, err = NewTokenManager([]Key{oldKey}, "k2")
+	assert.ErrorIs(t, err, ErrUnknownKey)
+}
+
+func TestNewTokenManager_InvalidKey(t *testing.T) {
+	testCases := []struct {
+		name string
+		key  Key
+	}{
+		{name: "没有密钥", key: Key{Id: "k1"}},
+		{name: "配置文件里面的占位符", key: Key{Id: "k1", Secret: "换成你自己的密钥"}},
+		{name: "没有 ID", key: Key{Secret: "secret 0123456789abcdef0123456789"}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewTokenManager([]Key{tc.key}, tc.key.Id)
+			assert.ErrorIs(t, err, ErrInvalidKey)
+		})
+	}
+}
+
+func TestSMSService_Send(t *testing.T) {
+	tokens, err := NewTokenManager([]Key{{Id: "k1", Secret: "secret 0123456789abcdef0123456789"}}, "k1")
+	require.NoError(t, err)
+	tpls, err := template.NewRegistry([]template.Template{
+		{
+			Name:   "order_shipped",
+			Params: []template.Param{{Name: "no", MaxLen: 10}},
+		},
+		{
+			Name:   "login_code",
+			Params: []template.Param{{Name: "code", Pattern: `^\d{6}$`}},
+		},
+	})
+	require.NoError(t, err)
+	orderToken, err := tokens.Issue("order", time.Hour)
+	require.NoError(t, err)
+	unknownToken, err := tokens.Issue("unknown", time.Hour)
+	require.NoError(t, err)
+	mockErr := errors.New("mock error")
+
+	testCases := []struct {
+		name    string
+		mock    func(ctrl *gomock.Controller) (sms.Service, Quota)
+		token   string
+		tpl     string
+		args    []string
+		numbers []string
+
+		wantErr error
+	}{
+		{
+			name: "发送成功",
+			mock: func(ctrl *gomock.Controller) (sms.Service, Quota) {
+				svc := smsmocks.NewMockService(ctrl)
+				quota := authmocks.NewMockQuota(ctrl)
+				quota.EXPECT().Take(gomock.Any(), "order", 2).Return(true, nil)
+				svc.EXPECT().Send(gomock.Any(), "order_shipped", []string{"A123"},
+					"15212345678", "15212345679").Return(nil)
+				return svc, quota
+			},
+			token:   orderToken,
+			tpl:     "order_shipped",
+			args:    []string{"A123"},
+			numbers: []string{"15212345678", "15212345679"},
+		},
+		{
+			name: "token 不对",
+			mock: func(ctrl *gomock.Controller) (sms.Service, Quota) {
+				return smsmocks.NewMockService(ctrl), authmocks.NewMockQuota(ctrl)
+			},
+			token:   "abc",
+			tpl:     "order_shipped",
+			args:    []string{"A123"},
+			numbers: []string{"15212345678"},
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name: "未知的调用方",
+			mock: func(ctrl *gomock.Controller) (sms.Service, Quota) {
+				return smsmocks.NewMockService(ctrl), authmocks.NewMockQuota(ctrl)
+			},
+			token:   unknownToken,
+			tpl:     "order_shipped",
+			args:    []string{"A123"},
+			numbers: []string{"15212345678"},
+			wantErr: ErrUnknownCaller,
+		},
+		{
+			name: "不能使用的模板",
+			mock: func(ctrl *gomock.Controller) (sms.Service, Quota) {
+				return smsmocks.NewMockService(ctrl), authmocks.NewMockQuota(ctrl)
+			},
+			token:   orderToken,
+			tpl:     "login_code",
+			args:    []string{"123456"},
+			numbers: []string{"15212345678"},
+			wantErr: ErrTemplateNotAllowed,
+		},
+		{
+			name: "参数不对",
+			mock: func(ctrl *gomock.Controller) (sms.Service, Quota) {
+				return smsmocks.NewMockService(ctrl), authmocks.NewMockQuota(ctrl)
+			},
+			token:   orderToken,
+			tpl:     "order_shipped",
+			args:    []string{"A1234567890"},
+			numbers: []string{"15212345678"},
+			wantErr: template.ErrInvalidArgs,
+		},
+		{
+			name: "没有手机号码",
+			mock: func(ctrl *gomock.Controller) (sms.Service, Quota) {
+				return smsmocks.NewMockService(ctrl), authmocks.NewMockQuota(ctrl)
+			},
+			token:   orderToken,
+			tpl:     "order_shipped",
+			args:    []string{"A123"},
+			wantErr: ErrEmptyNumbers,
+		},
+		{
+			name: "配额用完",
+			mock: func(ctrl *gomock.Controller) (sms.Service, Quota) {
+				quota := authmocks.NewMockQuota(ctrl)
+				quota.EXPECT().Take(gomock.Any(), "order", 2).Return(false, nil)
+				return smsmocks.NewMockService(ctrl), quota
+			},
+			token:   orderToken,
+			tpl:     "order_shipped",
+			args:    []string{"A123"},
+			numbers: []string{"15212345678", "15212345679"},
+			wantErr: ErrQuotaExceeded,
+		},
+		{
+			name: "发送失败",
+			mock: func(ctrl *gomock.Controller) (sms.Service, Quota) {
+				svc := smsmocks.NewMockService(ctrl)
+				quota := authmocks.NewMockQuota(ctrl)
+				quota.EXPECT().Take(gomock.Any(), "order", 1).Return(true, nil)
+				svc.EXPECT().Send(gomock.Any(), "order_shipped", []string{"A123"},
+					"15212345678").Return(mockErr)
+				return svc, quota
+			},
+			token:   orderToken,
+			tpl:     "order_shipped",
+			args:    []string{"A123"},
+			numbers: []string{"15212345678"},
+			wantErr: mockErr,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc, quota := tc.mock(ctrl)
+			s := NewSMSService(svc, tokens, tpls, map[string]Caller{
+				"order": {Templates: []string{"order_shipped"}, Quota: quota},
+			}, logger.NewNopLogger())
+			err := s.Send(context.Background(), tc.token, tc.tpl, tc.args, tc.numbers...)
+			assert.ErrorIs(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestMaskPhone(t *testing.T) {
+	assert.Equal(t, "152****5678", maskPhone("15212345678"))
+	assert.Equal(t, "****", maskPhone("123"))
+}
@@ -0,0 +1,92 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt/v5"
+	"time"
+)
+
+var (
+	ErrInvalidToken = errors.New("短信接口的 token 不对")
+	ErrUnknownKey   = errors.New("未知的签名密钥")
+	ErrInvalidKey   = errors.New("签名密钥不对")
+)
+
+// minSecretLen HS256 的密钥至少要 32 个字节
+const minSecretLen = 32
+
+// Key 签名用的密钥
+type Key struct {
+	Id     string
+	Secret string
+}
+
+// SMSClaims 调用方的身份，允许用的模板和配额在服务端配置，这样改了配置不需要重新发 token
+type SMSClaims struct {
+	jwt.RegisteredClaims
+}
+
+// TokenManager 签发和校验调用方的 token
+// 轮换密钥的时候，先加上新的密钥并且切换 active，
+// 等旧密钥签发的 token 都过期之后再把旧密钥删掉
+type TokenManager struct {
+	keys   map[string][]byte
+	active string
+}
+
+func NewTokenManager(keys []Key, active string) (*TokenManager, error) {
+	m := make(map[string][]byte, len(keys))
+	for _, k := range keys {
+		if k.Id == "" {
+			return nil, fmt.Errorf("%w 密钥 ID 不能为空", ErrInvalidKey)
+		}
+		if len(k.Secret) < minSecretLen {
+			return nil, fmt.Errorf("%w 密钥 %s 至少要 %d 个字节", ErrInvalidKey, k.Id, minSecretLen)
+		}
+		m[k.Id] = []byte(k.Secret)
+	}
+	if _, ok := m[active]; !ok {
+		return nil, fmt.Errorf("%w %s", ErrUnknownKey, active)
+	}
+	return &TokenManager{
+		keys:   m,
+		active: active,
+	}, nil
+}
+
+// Issue 用当前的密钥给调用方签发 token
+func (t *TokenManager) Issue(caller string, expiration time.Duration) (string, error) {
+	now := time.Now()
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, SMSClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   caller,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(expiration)),
+		},
+	})
+	// 校验的时候按照 kid 找密钥
+	token.Header["kid"] = t.active
+	return token.SignedString(t.keys[t.active])
+}
+
+// Parse 校验 token，返回调用方
+func (t *TokenManager) Parse(tokenStr string) (string, error) {
+	var claims SMSClaims
+	token, err := jwt.ParseWithClaims(tokenStr, &claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		key, ok := t.keys[kid]
+		if !ok {
+			return nil, fmt.Errorf("%w %s", ErrUnknownKey, kid)
+		}
+		return key, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithExpirationRequired())
+	if err != nil {
+		return "", fmt.Errorf("%w %w", ErrInvalidToken, err)
+	}
+	if !token.Valid || claims.Subject == "" {
+		return "", ErrInvalidToken
+	}
+	return claims.Subject, nil
+}
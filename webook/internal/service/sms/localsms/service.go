@@ -5,6 +5,9 @@ import (
 	"log"
 )
 
+// ProviderName 模板配置里面用的服务商名字
+const ProviderName = "local"
+
 type Service struct {
 }
 
@@ -0,0 +1,204 @@
+package router
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// State 熔断器的状态
+type State int32
+
+const (
+	// StateClosed 正常发送
+	StateClosed State = iota
+	// StateOpen 熔断了，不会选中这个服务商
+	StateOpen
+	// StateHalfOpen 熔断一段时间之后，放少量请求过去探测
+	StateHalfOpen
+)
+
+func (s State) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half_open"
+	default:
+		return "unknown"
+	}
+}
+
+type BreakerConfig struct {
+	// 根据最近多少次发送的结果来判断
+	WindowSize int `yaml:"windowSize"`
+	// 错误率超过这个值就熔断
+	ErrRate float64 `yaml:"errRate"`
+	// 响应时间的分位数，比如说 0.99
+	Percentile float64 `yaml:"percentile"`
+	// 分位数超过这个值就熔断
+	Latency time.Duration `yaml:"latency"`
+	// 熔断之后多久开始探测
+	OpenDuration time.Duration `yaml:"openDuration"`
+	// 半开的时候连续成功多少次就恢复，同时也是最多放过去的探测请求数量
+	Probes int `yaml:"probes"`
+}
+
+func DefaultBreakerConfig() BreakerConfig {
+	return BreakerConfig{
+		WindowSize:   100,
+		ErrRate:      0.3,
+		Percentile:   0.99,
+		Latency:      time.Second,
+		OpenDuration: 30 * time.Second,
+		Probes:       3,
+	}
+}
+
+// breaker 按照滑动窗口里面的错误率和响应时间分位数熔断
+type breaker struct {
+	cfg BreakerConfig
+
+	lock  sync.Mutex
+	state State
+	// 每次状态变化都加一，上一个状态放过去的请求，结果回来的时候直接丢弃
+	gen uint64
+	// 环形缓冲，closed 状态下最近的发送结果
+	results []result
+	idx     int
+	cnt     int
+	// 进入 open 状态的时间
+	openedAt time.Time
+	// 半开状态下，已经放过去还没返回的探测请求
+	probing int
+	// 半开状态下，连续成功的探测请求
+	succeeded int
+	// 状态变化的时候回调，用来上报监控
+	onChange func(from, to State)
+}
+
+type result struct {
+	duration time.Duration
+	failed   bool
+}
+
+func newBreaker(cfg BreakerConfig, onChange func(from, to State)) *breaker {
+	// 没有配置的话窗口是空的，探测请求也一个都放不过去
+	def := DefaultBreakerConfig()
+	if cfg.WindowSize <= 0 {
+		cfg.WindowSize = def.WindowSize
+	}
+	if cfg.Probes <= 0 {
+		cfg.Probes = def.Probes
+	}
+	return &breaker{
+		cfg:      cfg,
+		results:  make([]result, cfg.WindowSize),
+		onChange: onChange,
+	}
+}
+
+// allow 能不能把请求发给这个服务商，返回 true 的必须带上 gen 调用 report
+func (b *breaker) allow() (uint64, bool) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	switch b.state {
+	case StateClosed:
+		return b.gen, true
+	case StateOpen:
+		if time.Since(b.openedAt) < b.cfg.OpenDuration {
+			return 0, false
+		}
+		b.setState(StateHalfOpen)
+		b.probing = 0
+		b.succeeded = 0
+		fallthrough
+	default:
+		if b.probing >= b.cfg.Probes {
+			return 0, false
+		}
+		b.probing++
+		return b.gen, true
+	}
+}
+
+func (b *breaker) report(gen uint64, duration time.Duration, err error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if gen != b.gen {
+		// 状态已经变了，比如说熔断之前放过去的请求，或者上一轮的探测请求
+		return
+	}
+	switch b.state {
+	case StateHalfOpen:
+		b.probing--
+		if err != nil {
+			b.open()
+			return
+		}
+		b.succeeded++
+		if b.succeeded >= b.cfg.Probes {
+			// 恢复之后重新积累样本
+			b.idx = 0
+			b.cnt = 0
+			b.setState(StateClosed)
+		}
+	case StateClosed:
+		b.results[b.idx] = result{duration: duration, failed: err != nil}
+		b.idx = (b.idx + 1) % len(b.results)
+		if b.cnt < len(b.results) {
+			b.cnt++
+		}
+		// 样本太少，判断不准
+		if b.cnt == len(b.results) && !b.healthy() {
+			b.open()
+		}
+	}
+}
+
+func (b *breaker) healthy() bool {
+	var failed int
+	durations := make([]time.Duration, 0, len(b.results))
+	for _, res := range b.results {
+		if res.failed {
+			failed++
+		}
+		durations = append(durations, res.duration)
+	}
+	if float64(failed)/float64(len(b.results)) > b.cfg.ErrRate {
+		return false
+	}
+	sort.Slice(durations, func(i, j int) bool {
+		return durations[i] < durations[j]
+	})
+	idx := int(float64(len(durations)) * b.cfg.Percentile)
+	if idx >= len(durations) {
+		idx = len(durations) - 1
+	}
+	return durations[idx] <= b.cfg.Latency
+}
+
+func (b *breaker) open() {
+	b.openedAt = time.Now()
+	b.setState(StateOpen)
+}
+
+func (b *breaker) setState(state State) {
+	if b.state == state {
+		return
+	}
+	from := b.state
+	b.state = state
+	b.gen++
+	if b.onChange != nil {
+		b.onChange(from, state)
+	}
+}
+
+func (b *breaker) State() State {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.state
+}
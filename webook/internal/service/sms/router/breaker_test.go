@@ -0,0 +1,142 @@
+package router
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestBreaker_Report(t *testing.T) {
+	mockErr := errors.New("mock error")
+	testCases := []struct {
+		name      string
+		durations []time.Duration
+		errs      []error
+
+		wantState State
+	}{
+		{
+			name:      "样本不够",
+			durations: []time.Duration{time.Second, time.Second, time.Second},
+			errs:      []error{mockErr, mockErr, mockErr},
+			wantState: StateClosed,
+		},
+		{
+			name:      "健康",
+			durations: []time.Duration{time.Millisecond, time.Millisecond, time.Millisecond, time.Millisecond},
+			errs:      []error{nil, mockErr, nil, nil},
+			wantState: StateClosed,
+		},
+		{
+			name:      "错误率太高",
+			durations: []time.Duration{time.Millisecond, time.Millisecond, time.Millisecond, time.Millisecond},
+			errs:      []error{nil, mockErr, mockErr, nil},
+			wantState: StateOpen,
+		},
+		{
+			name:      "分位数太高",
+			durations: []time.Duration{time.Millisecond, time.Millisecond, time.Millisecond, time.Second},
+			errs:      []error{nil, nil, nil, nil},
+			wantState: StateOpen,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			b := newBreaker(testBreakerConfig(), nil)
+			for i, d := range tc.durations {
+				gen, ok := b.allow()
+				assert.True(t, ok)
+				b.report(gen, d, tc.errs[i])
+			}
+			assert.Equal(t, tc.wantState, b.State())
+		})
+	}
+}
+
+func TestBreaker_HalfOpen(t *testing.T) {
+	var changes []State
+	b := newBreaker(testBreakerConfig(), func(from, to State) {
+		changes = append(changes, to)
+	})
+	trip(b)
+	_, ok := b.allow()
+	assert.False(t, ok)
+
+	time.Sleep(60 * time.Millisecond)
+	// 半开，最多放两个探测请求
+	gen1, ok := b.allow()
+	assert.True(t, ok)
+	gen2, ok := b.allow()
+	assert.True(t, ok)
+	_, ok = b.allow()
+	assert.False(t, ok)
+	assert.Equal(t, StateHalfOpen, b.State())
+	// 探测失败，重新熔断
+	b.report(gen1, time.Millisecond, nil)
+	b.report(gen2, time.Millisecond, errors.New("mock error"))
+	assert.Equal(t, StateOpen, b.State())
+
+	time.Sleep(60 * time.Millisecond)
+	gen, ok := b.allow()
+	assert.True(t, ok)
+	b.report(gen, time.Millisecond, nil)
+	gen, ok = b.allow()
+	assert.True(t, ok)
+	b.report(gen, time.Millisecond, nil)
+	// 连续成功，恢复
+	assert.Equal(t, StateClosed, b.State())
+	assert.Equal(t, []State{StateOpen, StateHalfOpen, StateOpen,
+		StateHalfOpen, StateClosed}, changes)
+}
+
+func testBreakerConfig() BreakerConfig {
+	return BreakerConfig{
+		WindowSize:   4,
+		ErrRate:      0.3,
+		Percentile:   0.9,
+		Latency:      100 * time.Millisecond,
+		OpenDuration: 50 * time.Millisecond,
+		Probes:       2,
+	}
+}
+
+func TestBreaker_StaleProbe(t *testing.T) {
+	b := newBreaker(testBreakerConfig(), nil)
+	trip(b)
+	time.Sleep(60 * time.Millisecond)
+	gen1, _ := b.allow()
+	gen2, _ := b.allow()
+	// 第一个探测失败，重新熔断
+	b.report(gen1, time.Millisecond, errors.New("mock error"))
+	time.Sleep(60 * time.Millisecond)
+	gen3, ok := b.allow()
+	assert.True(t, ok)
+	// 上一轮的探测请求现在才返回，不能算到这一轮里面
+	b.report(gen2, time.Millisecond, nil)
+	assert.Equal(t, 1, b.probing)
+	assert.Equal(t, 0, b.succeeded)
+	_, ok = b.allow()
+	assert.True(t, ok)
+	_, ok = b.allow()
+	assert.False(t, ok)
+	b.report(gen3, time.Millisecond, nil)
+	assert.Equal(t, StateHalfOpen, b.State())
+}
+
+func TestBreaker_ZeroConfig(t *testing.T) {
+	b := newBreaker(BreakerConfig{}, nil)
+	// 窗口大小和探测数量用默认值，不能 panic
+	gen, ok := b.allow()
+	assert.True(t, ok)
+	b.report(gen, time.Millisecond, nil)
+	assert.Equal(t, DefaultBreakerConfig().WindowSize, len(b.results))
+	assert.Equal(t, DefaultBreakerConfig().Probes, b.cfg.Probes)
+}
+
+func trip(b *breaker) {
+	for i := 0; i < b.cfg.WindowSize; i++ {
+		gen, _ := b.allow()
+		b.report(gen, time.Millisecond, errors.New("mock error"))
+	}
+}
@@ -0,0 +1,170 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/wsqigo/basic-go/webook/internal/service/sms"
+	"github.com/wsqigo/basic-go/webook/internal/service/sms/template"
+	"github.com/wsqigo/basic-go/webook/pkg/logger"
+	"math/rand"
+	"strconv"
+	"time"
+)
+
+var ErrNoAvailableProvider = errors.New("没有可用的短信服务商")
+
+// Provider 一个短信服务商，Weight 越大分到的流量越多
+type Provider struct {
+	Name   string
+	Svc    sms.Service
+	Weight int
+}
+
+// Router 按照权重在健康的服务商之间分配流量
+// 每个服务商有自己的熔断器，熔断的服务商不会被选中，一段时间之后放少量请求去探测
+// 发送失败了，换一个服务商再试，直到没有可用的服务商
+// 只会选配置了对应模板的服务商，不然切换过去会发错模板
+type Router struct {
+	providers []*provider
+	tpls      *template.Registry
+	l         logger.LoggerV1
+
+	stateVector *prometheus.GaugeVec
+	sendVector  *prometheus.CounterVec
+}
+
+type provider struct {
+	Provider
+	breaker *breaker
+}
+
+func NewRouter(providers []Provider, tpls *template.Registry,
+	cfg BreakerConfig, l logger.LoggerV1) *Router {
+	r := &Router{
+		tpls: tpls,
+		l:    l,
+		stateVector: registerGauge(prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "geekbang_daming",
+			Subsystem: "webook",
+			Name:      "sms_provider_state",
+			Help:      "短信服务商熔断器的状态，0 正常，1 熔断，2 半开",
+		}, []string{"provider"})),
+		sendVector: registerCounter(prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "geekbang_daming",
+			Subsystem: "webook",
+			Name:      "sms_provider_send",
+			Help:      "每个短信服务商的发送次数",
+		}, []string{"provider", "state", "success"})),
+	}
+	for _, p := range providers {
+		name := p.Name
+		r.stateVector.WithLabelValues(name).Set(float64(StateClosed))
+		r.providers = append(r.providers, &provider{
+			Provider: p,
+			breaker: newBreaker(cfg, func(from, to State) {
+				r.stateVector.WithLabelValues(name).Set(float64(to))
+				r.l.Warn("短信服务商状态变化",
+					logger.String("provider", name),
+					logger.String("from", from.String()),
+					logger.String("to", to.String()))
+			}),
+		})
+	}
+	return r
+}
+
+func (r *Router) Send(ctx context.Context, tplId string, args []string, numbers ...string) error {
+	// 参数不对的话，换哪个服务商都没用
+	err := r.tpls.Validate(tplId, args)
+	if err != nil {
+		return err
+	}
+	tried := make(map[*provider]struct{}, len(r.providers))
+	err = ErrNoAvailableProvider
+	for len(tried) < len(r.providers) {
+		p, gen := r.pick(tplId, tried)
+		if p == nil {
+			break
+		}
+		tried[p] = struct{}{}
+		state := p.breaker.State()
+		start := time.Now()
+		err = p.Svc.Send(ctx, tplId, args, numbers...)
+		p.breaker.report(gen, time.Since(start), err)
+		r.sendVector.WithLabelValues(p.Name, state.String(),
+			strconv.FormatBool(err == nil)).Inc()
+		if err == nil {
+			return nil
+		}
+		r.l.Error("短信服务商发送失败",
+			logger.String("provider", p.Name),
+			logger.Error(err))
+		if ctx.Err() != nil {
+			// 主动取消或者超时了，换服务商也没用
+			return err
+		}
+	}
+	return err
+}
+
+// pick 在没试过并且支持这个模板的服务商里面，按照权重选一个熔断器放行的
+// 返回的 gen 要在 report 的时候带上
+func (r *Router) pick(tplId string, tried map[*provider]struct{}) (*provider, uint64) {
+	candidates := make([]*provider, 0, len(r.providers))
+	total := 0
+	for _, p := range r.providers {
+		if _, ok := tried[p]; ok || p.Weight <= 0 {
+			continue
+		}
+		if _, err := r.tpls.ProviderTemplate(tplId, p.Name); err != nil {
+			continue
+		}
+		candidates = append(candidates, p)
+		total += p.Weight
+	}
+	for len(candidates) > 0 {
+		n := rand.Intn(total)
+		idx := 0
+		for ; idx < len(candidates)-1; idx++ {
+			n -= candidates[idx].Weight
+			if n < 0 {
+				break
+			}
+		}
+		p := candidates[idx]
+		if gen, ok := p.breaker.allow(); ok {
+			return p, gen
+		}
+		// 熔断了，从剩下的里面再选
+		total -= p.Weight
+		candidates = append(candidates[:idx], candidates[idx+1:]...)
+	}
+	return nil, 0
+}
+
+// State 服务商当前的熔断状态，用来排查问题
+func (r *Router) State(name string) (State, bool) {
+	for _, p := range r.providers {
+		if p.Name == name {
+			return p.breaker.State(), true
+		}
+	}
+	return StateClosed, false
+}
+
+func registerGauge(vector *prometheus.GaugeVec) *prometheus.GaugeVec {
+	err := prometheus.Register(vector)
+	if are, ok := err.(prometheus.AlreadyRegisteredError); ok {
+		return are.ExistingCollector.(*prometheus.GaugeVec)
+	}
+	return vector
+}
+
+func registerCounter(vector *prometheus.CounterVec) *prometheus.CounterVec {
+	err := prometheus.Register(vector)
+	if are, ok := err.(prometheus.AlreadyRegisteredError); ok {
+		return are.ExistingCollector.(*prometheus.CounterVec)
+	}
+	return vector
+}
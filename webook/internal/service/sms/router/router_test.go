@@ -0,0 +1,164 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	smsmocks "github.com/wsqigo/basic-go/webook/internal/service/sms/mocks"
+	"github.com/wsqigo/basic-go/webook/internal/service/sms/template"
+	"github.com/wsqigo/basic-go/webook/pkg/logger"
+	"go.uber.org/mock/gomock"
+	"testing"
+)
+
+func TestRouter_Send(t *testing.T) {
+	mockErr := errors.New("mock error")
+	testCases := []struct {
+		name string
+		mock func(ctrl *gomock.Controller) []Provider
+		// 发送之前熔断哪些服务商
+		open []string
+		args []string
+
+		wantErr error
+	}{
+		{
+			name: "发送成功",
+			mock: func(ctrl *gomock.Controller) []Provider {
+				svc := smsmocks.NewMockService(ctrl)
+				svc.EXPECT().Send(gomock.Any(), "tpl", []string{"123"}, "152").Return(nil)
+				return []Provider{{Name: "tencent", Svc: svc, Weight: 1}}
+			},
+		},
+		{
+			name: "失败之后换一个服务商",
+			mock: func(ctrl *gomock.Controller) []Provider {
+				svc0 := smsmocks.NewMockService(ctrl)
+				svc0.EXPECT().Send(gomock.Any(), "tpl", []string{"123"}, "152").
+					Return(errors.New("mock error")).MaxTimes(1)
+				svc1 := smsmocks.NewMockService(ctrl)
+				svc1.EXPECT().Send(gomock.Any(), "tpl", []string{"123"}, "152").
+					Return(errors.New("mock error")).MaxTimes(1)
+				svc2 := smsmocks.NewMockService(ctrl)
+				svc2.EXPECT().Send(gomock.Any(), "tpl", []string{"123"}, "152").Return(nil)
+				// 权重为 0 的不会被选中
+				return []Provider{{Name: "tencent", Svc: svc0, Weight: 1},
+					{Name: "aliyun", Svc: svc1, Weight: 1},
+					{Name: "local", Svc: svc2, Weight: 1},
+					{Name: "zero", Svc: smsmocks.NewMockService(ctrl), Weight: 0}}
+			},
+		},
+		{
+			name: "跳过熔断的服务商",
+			mock: func(ctrl *gomock.Controller) []Provider {
+				svc1 := smsmocks.NewMockService(ctrl)
+				svc1.EXPECT().Send(gomock.Any(), "tpl", []string{"123"}, "152").Return(nil)
+				return []Provider{{Name: "tencent", Svc: smsmocks.NewMockService(ctrl), Weight: 100},
+					{Name: "aliyun", Svc: svc1, Weight: 1}}
+			},
+			open: []string{"tencent"},
+		},
+		{
+			name: "全部熔断",
+			mock: func(ctrl *gomock.Controller) []Provider {
+				return []Provider{{Name: "tencent", Svc: smsmocks.NewMockService(ctrl), Weight: 1}}
+			},
+			open:    []string{"tencent"},
+			wantErr: ErrNoAvailableProvider,
+		},
+		{
+			name: "跳过没有配置模板的服务商",
+			mock: func(ctrl *gomock.Controller) []Provider {
+				svc1 := smsmocks.NewMockService(ctrl)
+				svc1.EXPECT().Send(gomock.Any(), "tpl", []string{"123"}, "152").Return(nil)
+				return []Provider{{Name: "unknown", Svc: smsmocks.NewMockService(ctrl), Weight: 100},
+					{Name: "aliyun", Svc: svc1, Weight: 1}}
+			},
+		},
+		{
+			name: "参数不对，不会发送",
+			mock: func(ctrl *gomock.Controller) []Provider {
+				return []Provider{{Name: "tencent", Svc: smsmocks.NewMockService(ctrl), Weight: 1}}
+			},
+			args:    []string{"abc"},
+			wantErr: template.ErrInvalidArgs,
+		},
+		{
+			name: "全部失败",
+			mock: func(ctrl *gomock.Controller) []Provider {
+				svc := smsmocks.NewMockService(ctrl)
+				svc.EXPECT().Send(gomock.Any(), "tpl", []string{"123"}, "152").
+					Return(mockErr)
+				return []Provider{{Name: "tencent", Svc: svc, Weight: 1}}
+			},
+			wantErr: mockErr,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			r := NewRouter(tc.mock(ctrl), testRegistry(t), testBreakerConfig(), logger.NewNopLogger())
+			for _, p := range r.providers {
+				for _, name := range tc.open {
+					if p.Name == name {
+						trip(p.breaker)
+					}
+				}
+			}
+			args := tc.args
+			if args == nil {
+				args = []string{"123"}
+			}
+			err := r.Send(context.Background(), "tpl", args, "152")
+			if tc.wantErr == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, tc.wantErr)
+		})
+	}
+}
+
+func testRegistry(t *testing.T) *template.Registry {
+	r, err := template.NewRegistry([]template.Template{
+		{
+			Name:   "tpl",
+			Params: []template.Param{{Name: "code", Pattern: `^\d+$`}},
+			Providers: map[string]template.ProviderTemplate{
+				"tencent": {Id: "1"},
+				"aliyun":  {Id: "SMS_1"},
+				"local":   {Id: "tpl"},
+			},
+		},
+	})
+	require.NoError(t, err)
+	return r
+}
+
+func TestRouter_Weight(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	svc0 := smsmocks.NewMockService(ctrl)
+	svc1 := smsmocks.NewMockService(ctrl)
+	var cnt0, cnt1 int
+	svc0.EXPECT().Send(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, tplId string, args []string, numbers ...string) error {
+			cnt0++
+			return nil
+		}).AnyTimes()
+	svc1.EXPECT().Send(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, tplId string, args []string, numbers ...string) error {
+			cnt1++
+			return nil
+		}).AnyTimes()
+	r := NewRouter([]Provider{{Name: "tencent", Svc: svc0, Weight: 3},
+		{Name: "aliyun", Svc: svc1, Weight: 1}}, testRegistry(t), testBreakerConfig(), logger.NewNopLogger())
+	for i := 0; i < 4000; i++ {
+		assert.NoError(t, r.Send(context.Background(), "tpl", []string{"123"}, "152"))
+	}
+	// 大概是 3:1
+	assert.InDelta(t, 3000, cnt0, 200)
+	assert.InDelta(t, 1000, cnt1, 200)
+}
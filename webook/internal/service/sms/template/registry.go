@@ -0,0 +1,130 @@
+package template
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"unicode/utf8"
+)
+
+var (
+	ErrUnknownTemplate = errors.New("未知的短信模板")
+	// ErrUnsupportedProvider 服务商没有配置这个模板，不能发给它
+	ErrUnsupportedProvider = errors.New("服务商不支持这个短信模板")
+	ErrInvalidArgs         = errors.New("短信模板参数不对")
+)
+
+// Template 逻辑上的短信模板，业务方只用 Name
+// 不同服务商的模板 ID 和签名都不一样，发送之前由服务商自己解析
+type Template struct {
+	Name string `yaml:"name"`
+	// 按照顺序对应发送时候的 args
+	Params []Param `yaml:"params"`
+	// 服务商名字到服务商模板的映射
+	Providers map[string]ProviderTemplate `yaml:"providers"`
+}
+
+type Param struct {
+	// 阿里云之类按照名字传参数的服务商要用
+	Name string `yaml:"name"`
+	// 最多多少个字符，0 就是不限制
+	MaxLen int `yaml:"maxLen"`
+	// 正则表达式，空的就是不限制
+	Pattern string `yaml:"pattern"`
+}
+
+type ProviderTemplate struct {
+	Id string `yaml:"id"`
+	// 空的话用服务商默认的签名
+	SignName string `yaml:"signName"`
+}
+
+type Registry struct {
+	templates map[string]Template
+	// 预先编译好的正则表达式，和 Params 一一对应
+	patterns map[string][]*regexp.Regexp
+}
+
+// NewRegistry 模板配置有问题的话直接返回 error，不要等到发送的时候才发现
+func NewRegistry(templates []Template) (*Registry, error) {
+	r := &Registry{
+		templates: make(map[string]Template, len(templates)),
+		patterns:  make(map[string][]*regexp.Regexp, len(templates)),
+	}
+	for _, tpl := range templates {
+		if tpl.Name == "" {
+			return nil, errors.New("短信模板缺少名字")
+		}
+		if _, ok := r.templates[tpl.Name]; ok {
+			return nil, fmt.Errorf("短信模板 %s 重复了", tpl.Name)
+		}
+		patterns := make([]*regexp.Regexp, 0, len(tpl.Params))
+		for _, p := range tpl.Params {
+			if p.Pattern == "" {
+				patterns = append(patterns, nil)
+				continue
+			}
+			reg, err := regexp.Compile(p.Pattern)
+			if err != nil {
+				return nil, fmt.Errorf("短信模板 %s 参数 %s 的正则表达式不对 %w",
+					tpl.Name, p.Name, err)
+			}
+			patterns = append(patterns, reg)
+		}
+		r.templates[tpl.Name] = tpl
+		r.patterns[tpl.Name] = patterns
+	}
+	return r, nil
+}
+
+func (r *Registry) Get(name string) (Template, error) {
+	tpl, ok := r.templates[name]
+	if !ok {
+		return Template{}, fmt.Errorf("%w %s", ErrUnknownTemplate, name)
+	}
+	return tpl, nil
+}
+
+// Validate 参数的个数，长度和格式都要对得上
+func (r *Registry) Validate(name string, args []string) error {
+	tpl, err := r.Get(name)
+	if err != nil {
+		return err
+	}
+	if len(args) != len(tpl.Params) {
+		return fmt.Errorf("%w 模板 %s 需要 %d 个参数，实际 %d 个",
+			ErrInvalidArgs, name, len(tpl.Params), len(args))
+	}
+	patterns := r.patterns[name]
+	for i, p := range tpl.Params {
+		if p.MaxLen > 0 && utf8.RuneCountInString(args[i]) > p.MaxLen {
+			return fmt.Errorf("%w 模板 %s 参数 %s 太长", ErrInvalidArgs, name, p.Name)
+		}
+		if patterns[i] != nil && !patterns[i].MatchString(args[i]) {
+			return fmt.Errorf("%w 模板 %s 参数 %s 格式不对", ErrInvalidArgs, name, p.Name)
+		}
+	}
+	return nil
+}
+
+// Resolve 找到服务商那边的模板，同时校验参数
+func (r *Registry) Resolve(name string, provider string, args []string) (ProviderTemplate, error) {
+	err := r.Validate(name, args)
+	if err != nil {
+		return ProviderTemplate{}, err
+	}
+	return r.ProviderTemplate(name, provider)
+}
+
+func (r *Registry) ProviderTemplate(name string, provider string) (ProviderTemplate, error) {
+	tpl, err := r.Get(name)
+	if err != nil {
+		return ProviderTemplate{}, err
+	}
+	pt, ok := tpl.Providers[provider]
+	if !ok {
+		return ProviderTemplate{}, fmt.Errorf("%w 模板 %s 服务商 %s",
+			ErrUnsupportedProvider, name, provider)
+	}
+	return pt, nil
+}
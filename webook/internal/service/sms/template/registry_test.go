@@ -0,0 +1,91 @@
+package template
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestRegistry_Resolve(t *testing.T) {
+	r, err := NewRegistry([]Template{
+		{
+			Name: "login_code",
+			Params: []Param{
+				{Name: "code", MaxLen: 6, Pattern: `^\d+$`},
+			},
+			Providers: map[string]ProviderTemplate{
+				"tencent": {Id: "123"},
+				"aliyun":  {Id: "SMS_123", SignName: "签名"},
+			},
+		},
+	})
+	require.NoError(t, err)
+	testCases := []struct {
+		name     string
+		tplName  string
+		provider string
+		args     []string
+
+		wantTpl ProviderTemplate
+		wantErr error
+	}{
+		{
+			name:     "解析成功",
+			tplName:  "login_code",
+			provider: "aliyun",
+			args:     []string{"123456"},
+			wantTpl:  ProviderTemplate{Id: "SMS_123", SignName: "签名"},
+		},
+		{
+			name:     "未知的模板",
+			tplName:  "unknown",
+			provider: "aliyun",
+			args:     []string{"123456"},
+			wantErr:  ErrUnknownTemplate,
+		},
+		{
+			name:     "服务商没有配置",
+			tplName:  "login_code",
+			provider: "local",
+			args:     []string{"123456"},
+			wantErr:  ErrUnsupportedProvider,
+		},
+		{
+			name:     "参数个数不对",
+			tplName:  "login_code",
+			provider: "tencent",
+			args:     []string{"123456", "1"},
+			wantErr:  ErrInvalidArgs,
+		},
+		{
+			name:     "参数太长",
+			tplName:  "login_code",
+			provider: "tencent",
+			args:     []string{"1234567"},
+			wantErr:  ErrInvalidArgs,
+		},
+		{
+			name:     "参数格式不对",
+			tplName:  "login_code",
+			provider: "tencent",
+			args:     []string{" 12345"},
+			wantErr:  ErrInvalidArgs,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tpl, err := r.Resolve(tc.tplName, tc.provider, tc.args)
+			assert.ErrorIs(t, err, tc.wantErr)
+			assert.Equal(t, tc.wantTpl, tpl)
+		})
+	}
+}
+
+func TestNewRegistry(t *testing.T) {
+	_, err := NewRegistry([]Template{{Name: "a"}, {Name: "a"}})
+	assert.Error(t, err)
+	_, err = NewRegistry([]Template{{Name: "a", Params: []Param{{Name: "code", Pattern: "("}}}})
+	assert.Error(t, err)
+	_, err = NewRegistry([]Template{{}})
+	assert.Error(t, err)
+}
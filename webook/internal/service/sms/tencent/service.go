@@ -6,21 +6,34 @@ import (
 	"github.com/ecodeclub/ekit"
 	"github.com/ecodeclub/ekit/slice"
 	sms "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/sms/v20210111"
+	"github.com/wsqigo/basic-go/webook/internal/service/sms/template"
 	"go.uber.org/zap"
 )
 
+// ProviderName 模板配置里面用的服务商名字
+const ProviderName = "tencent"
+
 type Service struct {
 	client   *sms.Client
 	appId    *string
 	signName *string
+	tpls     *template.Registry
 }
 
+// Send tplId 是逻辑上的模板名字，发送之前解析成腾讯的模板 ID
 func (s *Service) Send(ctx context.Context, tplId string, args []string, numbers ...string) error {
+	tpl, err := s.tpls.Resolve(tplId, ProviderName, args)
+	if err != nil {
+		return err
+	}
 	request := sms.NewSendSmsRequest()
 	request.SetContext(ctx)
 	request.SmsSdkAppId = s.appId
 	request.SignName = s.signName
-	request.TemplateId = ekit.ToPtr[string](tplId)
+	if tpl.SignName != "" {
+		request.SignName = ekit.ToPtr[string](tpl.SignName)
+	}
+	request.TemplateId = ekit.ToPtr[string](tpl.Id)
 	request.TemplateParamSet = s.toPtrSlice(args)
 	request.PhoneNumberSet = s.toPtrSlice(numbers)
 
@@ -52,10 +65,11 @@ func (s *Service) toPtrSlice(data []string) []*string {
 	})
 }
 
-func NewService(client *sms.Client, appId string, signName string) *Service {
+func NewService(client *sms.Client, appId string, signName string, tpls *template.Registry) *Service {
 	return &Service{
 		client:   client,
 		appId:    &appId,
 		signName: &signName,
+		tpls:     tpls,
 	}
 }
@@ -6,6 +6,7 @@ import (
 	"github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common"
 	"github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/profile"
 	sms "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/sms/v20210111"
+	"github.com/wsqigo/basic-go/webook/internal/service/sms/template"
 	"os"
 	"testing"
 )
@@ -27,7 +28,19 @@ func TestSender(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	s := NewService(client, "1400088380", "量链科技")
+	tpls, err := template.NewRegistry([]template.Template{
+		{
+			Name:   "stage",
+			Params: []template.Param{{Name: "stage"}, {Name: "a"}, {Name: "b"}, {Name: "c"}},
+			Providers: map[string]template.ProviderTemplate{
+				ProviderName: {Id: "1574317"},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := NewService(client, "1400088380", "量链科技", tpls)
 
 	testCases := []struct {
 		name    string
@@ -38,7 +51,7 @@ func TestSender(t *testing.T) {
 	}{
 		{
 			name:   "发送验证码",
-			tplId:  "1574317",
+			tplId:  "stage",
 			params: []string{"stage", "1", "1", "1"},
 			// 改成你的手机号码
 			numbers: []string{"19124155294"},
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"github.com/wsqigo/basic-go/webook/internal/domain"
+	"github.com/wsqigo/basic-go/webook/internal/events/user"
 	"github.com/wsqigo/basic-go/webook/internal/repository"
 	"go.uber.org/zap"
 	"golang.org/x/crypto/bcrypt"
@@ -26,13 +27,15 @@ type UserService interface {
 }
 
 type userService struct {
-	repo   repository.UserRepository
-	logger *zap.Logger
+	repo     repository.UserRepository
+	producer user.Producer
+	logger   *zap.Logger
 }
 
-func NewUserService(repo repository.UserRepository) UserService {
+func NewUserService(repo repository.UserRepository, producer user.Producer) UserService {
 	return &userService{
-		repo: repo,
+		repo:     repo,
+		producer: producer,
 	}
 }
 
@@ -63,8 +66,21 @@ func (svc *userService) Login(ctx context.Context, email, password string) (doma
 	return u, err
 }
 
-func (svc *userService) UpdateNonSensitiveInfo(ctx context.Context, user domain.User) error {
-	return svc.repo.UpdateNonZeroFields(ctx, user)
+func (svc *userService) UpdateNonSensitiveInfo(ctx context.Context, u domain.User) error {
+	err := svc.repo.UpdateNonZeroFields(ctx, u)
+	if err != nil {
+		return err
+	}
+	go func() {
+		// 搜索之类的下游靠这个消息来同步用户资料
+		er := svc.producer.ProduceProfileEvent(user.ProfileEvent{Uid: u.Id})
+		if er != nil {
+			zap.L().Error("发送 ProfileEvent 失败",
+				zap.Int64("uid", u.Id),
+				zap.Error(er))
+		}
+	}()
+	return nil
 }
 
 func (svc *userService) FindById(ctx context.Context, userId int64) (domain.User, error) {
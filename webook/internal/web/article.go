@@ -53,6 +53,7 @@ func (h *ArticleHandler) RegisterRoutes(server *gin.Engine) {
 	// 传入一个参数，true 就是点赞, false 就是不点赞
 	pub.POST("/like", h.Like)
 	pub.POST("/collect", h.Collect)
+	pub.POST("/cancel_collect", h.CancelCollect)
 }
 
 // Edit 接收 Article 输入，输入一个 ID，文章的 ID
@@ -311,6 +312,7 @@ func (h *ArticleHandler) PubDetail(ctx *gin.Context) {
 			ReadCnt:    inter.ReadCnt,
 			LikeCnt:    inter.LikeCnt,
 			CollectCnt: inter.CollectCnt,
+			CommentCnt: inter.CommentCnt,
 			Liked:      inter.Liked,
 			Collected:  inter.Collected,
 
@@ -380,3 +382,25 @@ func (h *ArticleHandler) Collect(ctx *gin.Context) {
 		Msg: "OK",
 	})
 }
+
+func (h *ArticleHandler) CancelCollect(ctx *gin.Context) {
+	var req ArticleCancelCollectReq
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	uc := ctx.MustGet("user").(jwt.UserClaims)
+	err := h.interSvc.CancelCollect(ctx, h.biz, req.Id, uc.Uid)
+	if err != nil {
+		ctx.JSON(http.StatusOK, ginx.Result{
+			Code: 5, Msg: "系统错误",
+		})
+		h.l.Error("取消收藏失败",
+			logger.Error(err),
+			logger.Int64("uid", uc.Uid),
+			logger.Int64("aid", req.Id))
+		return
+	}
+	ctx.JSON(http.StatusOK, ginx.Result{
+		Msg: "OK",
+	})
+}
@@ -14,6 +14,7 @@ type ArticleVo struct {
 	ReadCnt    int64 `json:"readCnt"`
 	LikeCnt    int64 `json:"likeCnt"`
 	CollectCnt int64 `json:"collectCnt"`
+	CommentCnt int64 `json:"commentCnt"`
 	Liked      bool  `json:"liked"`
 	Collected  bool  `json:"collected"`
 }
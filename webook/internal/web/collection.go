@@ -0,0 +1,204 @@
+package web
+
+import (
+	"github.com/ecodeclub/ekit/slice"
+	"github.com/gin-gonic/gin"
+	"github.com/wsqigo/basic-go/webook/internal/domain"
+	"github.com/wsqigo/basic-go/webook/internal/service"
+	"github.com/wsqigo/basic-go/webook/internal/web/jwt"
+	"github.com/wsqigo/basic-go/webook/pkg/ginx"
+	"github.com/wsqigo/basic-go/webook/pkg/logger"
+	"net/http"
+	"time"
+)
+
+// CollectionHandler 收藏夹
+type CollectionHandler struct {
+	svc service.CollectionService
+	l   logger.LoggerV1
+}
+
+func NewCollectionHandler(svc service.CollectionService, l logger.LoggerV1) *CollectionHandler {
+	return &CollectionHandler{
+		svc: svc,
+		l:   l,
+	}
+}
+
+func (h *CollectionHandler) RegisterRoutes(server *gin.Engine) {
+	g := server.Group("/collections")
+	g.POST("/create", h.Create)
+	g.POST("/rename", h.Rename)
+	g.POST("/delete", h.Delete)
+	g.POST("/list", h.List)
+	g.POST("/items", h.Items)
+	g.POST("/move", h.Move)
+}
+
+func (h *CollectionHandler) Create(ctx *gin.Context) {
+	var req CollectionCreateReq
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	if req.Name == "" {
+		ctx.JSON(http.StatusOK, ginx.Result{
+			Code: 4,
+			Msg:  "收藏夹名字不能为空",
+		})
+		return
+	}
+	uc := ctx.MustGet("user").(jwt.UserClaims)
+	id, err := h.svc.Create(ctx, domain.Collection{
+		Uid:  uc.Uid,
+		Name: req.Name,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusOK, ginx.Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.l.Error("创建收藏夹失败",
+			logger.Int64("uid", uc.Uid),
+			logger.Error(err))
+		return
+	}
+	ctx.JSON(http.StatusOK, ginx.Result{
+		Data: id,
+	})
+}
+
+func (h *CollectionHandler) Rename(ctx *gin.Context) {
+	var req CollectionRenameReq
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	if req.Name == "" {
+		ctx.JSON(http.StatusOK, ginx.Result{
+			Code: 4,
+			Msg:  "收藏夹名字不能为空",
+		})
+		return
+	}
+	uc := ctx.MustGet("user").(jwt.UserClaims)
+	err := h.svc.Rename(ctx, uc.Uid, req.Id, req.Name)
+	h.writeResult(ctx, err, "重命名收藏夹失败", uc.Uid, req.Id)
+}
+
+func (h *CollectionHandler) Delete(ctx *gin.Context) {
+	var req CollectionDeleteReq
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	uc := ctx.MustGet("user").(jwt.UserClaims)
+	err := h.svc.Delete(ctx, uc.Uid, req.Id)
+	h.writeResult(ctx, err, "删除收藏夹失败", uc.Uid, req.Id)
+}
+
+func (h *CollectionHandler) List(ctx *gin.Context) {
+	var page Page
+	if err := ctx.Bind(&page); err != nil {
+		return
+	}
+	uc := ctx.MustGet("user").(jwt.UserClaims)
+	cols, err := h.svc.List(ctx, uc.Uid, page.Offset, page.Limit)
+	if err != nil {
+		ctx.JSON(http.StatusOK, ginx.Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.l.Error("查询收藏夹列表失败",
+			logger.Int64("uid", uc.Uid),
+			logger.Int("offset", page.Offset),
+			logger.Int("limit", page.Limit),
+			logger.Error(err))
+		return
+	}
+	ctx.JSON(http.StatusOK, ginx.Result{
+		Data: slice.Map(cols, func(idx int, src domain.Collection) CollectionVo {
+			return CollectionVo{
+				Id:      src.Id,
+				Name:    src.Name,
+				ItemCnt: src.ItemCnt,
+				Ctime:   src.Ctime.Format(time.DateTime),
+				Utime:   src.Utime.Format(time.DateTime),
+			}
+		}),
+	})
+}
+
+func (h *CollectionHandler) Items(ctx *gin.Context) {
+	var req CollectionItemsReq
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	uc := ctx.MustGet("user").(jwt.UserClaims)
+	items, err := h.svc.ListItems(ctx, uc.Uid, req.Cid, req.Offset, req.Limit)
+	switch err {
+	case nil:
+		ctx.JSON(http.StatusOK, ginx.Result{
+			Data: slice.Map(items, func(idx int, src domain.CollectionItem) CollectionItemVo {
+				return CollectionItemVo{
+					Cid:   src.Cid,
+					Biz:   src.Biz,
+					BizId: src.BizId,
+					Ctime: src.Ctime.Format(time.DateTime),
+				}
+			}),
+		})
+	case service.ErrCollectionNotFound:
+		ctx.JSON(http.StatusOK, ginx.Result{
+			Code: 4,
+			Msg:  "收藏夹不存在",
+		})
+	default:
+		ctx.JSON(http.StatusOK, ginx.Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.l.Error("查询收藏夹内容失败",
+			logger.Int64("uid", uc.Uid),
+			logger.Int64("cid", req.Cid),
+			logger.Error(err))
+	}
+}
+
+func (h *CollectionHandler) Move(ctx *gin.Context) {
+	var req CollectionMoveReq
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	uc := ctx.MustGet("user").(jwt.UserClaims)
+	err := h.svc.MoveItem(ctx, uc.Uid, req.Biz, req.BizId, req.Cid)
+	if err == service.ErrCollectionItemNotFound {
+		ctx.JSON(http.StatusOK, ginx.Result{
+			Code: 4,
+			Msg:  "没有收藏过",
+		})
+		return
+	}
+	h.writeResult(ctx, err, "移动收藏失败", uc.Uid, req.Cid)
+}
+
+func (h *CollectionHandler) writeResult(ctx *gin.Context, err error,
+	msg string, uid int64, cid int64) {
+	switch err {
+	case nil:
+		ctx.JSON(http.StatusOK, ginx.Result{
+			Msg: "OK",
+		})
+	case service.ErrCollectionNotFound:
+		ctx.JSON(http.StatusOK, ginx.Result{
+			Code: 4,
+			Msg:  "收藏夹不存在",
+		})
+	default:
+		ctx.JSON(http.StatusOK, ginx.Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.l.Error(msg,
+			logger.Int64("uid", uid),
+			logger.Int64("cid", cid),
+			logger.Error(err))
+	}
+}
@@ -0,0 +1,47 @@
+package web
+
+type CollectionVo struct {
+	Id      int64  `json:"id"`
+	Name    string `json:"name"`
+	ItemCnt int64  `json:"itemCnt"`
+	Ctime   string `json:"ctime"`
+	Utime   string `json:"utime"`
+}
+
+type CollectionItemVo struct {
+	Cid   int64  `json:"cid"`
+	Biz   string `json:"biz"`
+	BizId int64  `json:"bizId"`
+	Ctime string `json:"ctime"`
+}
+
+type CollectionCreateReq struct {
+	Name string `json:"name"`
+}
+
+type CollectionRenameReq struct {
+	Id   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+type CollectionDeleteReq struct {
+	Id int64 `json:"id"`
+}
+
+type CollectionItemsReq struct {
+	// 0 代表默认收藏夹
+	Cid    int64 `json:"cid"`
+	Offset int   `json:"offset"`
+	Limit  int   `json:"limit"`
+}
+
+type CollectionMoveReq struct {
+	Biz   string `json:"biz"`
+	BizId int64  `json:"bizId"`
+	// 目标收藏夹
+	Cid int64 `json:"cid"`
+}
+
+type ArticleCancelCollectReq struct {
+	Id int64 `json:"id"`
+}
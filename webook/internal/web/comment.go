@@ -0,0 +1,194 @@
+package web
+
+import (
+	"github.com/ecodeclub/ekit/slice"
+	"github.com/gin-gonic/gin"
+	"github.com/wsqigo/basic-go/webook/internal/domain"
+	"github.com/wsqigo/basic-go/webook/internal/service"
+	"github.com/wsqigo/basic-go/webook/internal/web/jwt"
+	"github.com/wsqigo/basic-go/webook/pkg/ginx"
+	"github.com/wsqigo/basic-go/webook/pkg/logger"
+	"net/http"
+	"time"
+)
+
+type CommentHandler struct {
+	svc service.CommentService
+	l   logger.LoggerV1
+}
+
+func NewCommentHandler(svc service.CommentService, l logger.LoggerV1) *CommentHandler {
+	return &CommentHandler{
+		svc: svc,
+		l:   l,
+	}
+}
+
+func (h *CommentHandler) RegisterRoutes(server *gin.Engine) {
+	g := server.Group("/comments")
+	g.POST("/create", h.Create)
+	// 理论上来说应该用 GET 的，同样是懒得处理类型转化
+	g.POST("/list", h.List)
+	g.POST("/replies", h.Replies)
+	g.POST("/delete", h.Delete)
+}
+
+func (h *CommentHandler) Create(ctx *gin.Context) {
+	var req CommentCreateReq
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	if req.Content == "" || req.Biz == "" || req.BizId <= 0 {
+		ctx.JSON(http.StatusOK, ginx.Result{
+			Code: 4,
+			Msg:  "参数错误",
+		})
+		return
+	}
+	uc := ctx.MustGet("user").(jwt.UserClaims)
+	cmt := domain.Comment{
+		Commentator: domain.User{Id: uc.Uid},
+		Biz:         req.Biz,
+		BizId:       req.BizId,
+		Content:     req.Content,
+	}
+	if req.ParentId > 0 {
+		cmt.ParentComment = &domain.Comment{Id: req.ParentId}
+	}
+	id, err := h.svc.CreateComment(ctx, cmt)
+	switch err {
+	case nil:
+		ctx.JSON(http.StatusOK, ginx.Result{
+			Data: id,
+		})
+	case service.ErrInvalidComment:
+		ctx.JSON(http.StatusOK, ginx.Result{
+			Code: 4,
+			Msg:  "回复的评论不存在",
+		})
+	default:
+		ctx.JSON(http.StatusOK, ginx.Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.l.Error("发表评论失败",
+			logger.Int64("uid", uc.Uid),
+			logger.String("biz", req.Biz),
+			logger.Int64("biz_id", req.BizId),
+			logger.Error(err))
+	}
+}
+
+func (h *CommentHandler) List(ctx *gin.Context) {
+	var req CommentListReq
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	req.Limit = h.normalizeLimit(req.Limit)
+	cmts, err := h.svc.GetCommentList(ctx, req.Biz, req.BizId, req.MinId, req.Limit)
+	if err != nil {
+		ctx.JSON(http.StatusOK, ginx.Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.l.Error("查询评论列表失败",
+			logger.String("biz", req.Biz),
+			logger.Int64("biz_id", req.BizId),
+			logger.Int64("min_id", req.MinId),
+			logger.Error(err))
+		return
+	}
+	ctx.JSON(http.StatusOK, ginx.Result{
+		Data: h.toVos(cmts),
+	})
+}
+
+func (h *CommentHandler) Replies(ctx *gin.Context) {
+	var req CommentRepliesReq
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	req.Limit = h.normalizeLimit(req.Limit)
+	cmts, err := h.svc.GetMoreReplies(ctx, req.Rid, req.MaxId, req.Limit)
+	if err != nil {
+		ctx.JSON(http.StatusOK, ginx.Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.l.Error("查询回复失败",
+			logger.Int64("rid", req.Rid),
+			logger.Int64("max_id", req.MaxId),
+			logger.Error(err))
+		return
+	}
+	ctx.JSON(http.StatusOK, ginx.Result{
+		Data: h.toVos(cmts),
+	})
+}
+
+func (h *CommentHandler) Delete(ctx *gin.Context) {
+	var req CommentDeleteReq
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	uc := ctx.MustGet("user").(jwt.UserClaims)
+	err := h.svc.DeleteComment(ctx, uc.Uid, req.Id)
+	switch err {
+	case nil:
+		ctx.JSON(http.StatusOK, ginx.Result{
+			Msg: "OK",
+		})
+	case service.ErrCommentPermissionDenied:
+		// 有人在搞鬼
+		ctx.JSON(http.StatusOK, ginx.Result{
+			Code: 4,
+			Msg:  "没有权限",
+		})
+		h.l.Warn("非法删除评论",
+			logger.Int64("uid", uc.Uid),
+			logger.Int64("cid", req.Id))
+	default:
+		ctx.JSON(http.StatusOK, ginx.Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.l.Error("删除评论失败",
+			logger.Int64("uid", uc.Uid),
+			logger.Int64("cid", req.Id),
+			logger.Error(err))
+	}
+}
+
+func (h *CommentHandler) normalizeLimit(limit int) int {
+	if limit <= 0 || limit > 100 {
+		return 20
+	}
+	return limit
+}
+
+func (h *CommentHandler) toVos(cmts []domain.Comment) []CommentVo {
+	return slice.Map(cmts, func(idx int, src domain.Comment) CommentVo {
+		return h.toVo(src)
+	})
+}
+
+func (h *CommentHandler) toVo(cmt domain.Comment) CommentVo {
+	res := CommentVo{
+		Id:      cmt.Id,
+		Uid:     cmt.Commentator.Id,
+		Biz:     cmt.Biz,
+		BizId:   cmt.BizId,
+		Content: cmt.Content,
+		Ctime:   cmt.Ctime.Format(time.DateTime),
+	}
+	if cmt.RootComment != nil {
+		res.RootId = cmt.RootComment.Id
+	}
+	if cmt.ParentComment != nil {
+		res.ParentId = cmt.ParentComment.Id
+	}
+	if len(cmt.Children) > 0 {
+		res.Children = h.toVos(cmt.Children)
+	}
+	return res
+}
@@ -0,0 +1,41 @@
+package web
+
+type CommentVo struct {
+	Id       int64  `json:"id"`
+	Uid      int64  `json:"uid"`
+	Biz      string `json:"biz"`
+	BizId    int64  `json:"bizId"`
+	Content  string `json:"content"`
+	RootId   int64  `json:"rootId,omitempty"`
+	ParentId int64  `json:"parentId,omitempty"`
+	Ctime    string `json:"ctime"`
+	// 根评论下预加载的回复
+	Children []CommentVo `json:"children,omitempty"`
+}
+
+type CommentCreateReq struct {
+	Biz     string `json:"biz"`
+	BizId   int64  `json:"bizId"`
+	Content string `json:"content"`
+	// 回复的是哪一条评论，0 代表根评论
+	ParentId int64 `json:"parentId"`
+}
+
+type CommentListReq struct {
+	Biz   string `json:"biz"`
+	BizId int64  `json:"bizId"`
+	// 上一页最后一条评论的 ID，第一页传 0
+	MinId int64 `json:"minId"`
+	Limit int   `json:"limit"`
+}
+
+type CommentRepliesReq struct {
+	Rid int64 `json:"rid"`
+	// 已经加载的最后一条回复的 ID
+	MaxId int64 `json:"maxId"`
+	Limit int   `json:"limit"`
+}
+
+type CommentDeleteReq struct {
+	Id int64 `json:"id"`
+}
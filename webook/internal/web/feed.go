@@ -0,0 +1,74 @@
+package web
+
+import (
+	"github.com/ecodeclub/ekit/slice"
+	"github.com/gin-gonic/gin"
+	"github.com/wsqigo/basic-go/webook/internal/domain"
+	"github.com/wsqigo/basic-go/webook/internal/service"
+	"github.com/wsqigo/basic-go/webook/internal/web/jwt"
+	"github.com/wsqigo/basic-go/webook/pkg/ginx"
+	"github.com/wsqigo/basic-go/webook/pkg/logger"
+	"net/http"
+	"time"
+)
+
+// FeedHandler 关注的人发表的文章
+type FeedHandler struct {
+	svc service.FeedService
+	l   logger.LoggerV1
+}
+
+func NewFeedHandler(svc service.FeedService, l logger.LoggerV1) *FeedHandler {
+	return &FeedHandler{
+		svc: svc,
+		l:   l,
+	}
+}
+
+func (h *FeedHandler) RegisterRoutes(server *gin.Engine) {
+	g := server.Group("/feed")
+	g.POST("/list", h.List)
+}
+
+func (h *FeedHandler) List(ctx *gin.Context) {
+	var req FeedListReq
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	if req.Limit <= 0 || req.Limit > 100 {
+		req.Limit = 20
+	}
+	maxTime := time.Now()
+	if req.MaxTime > 0 {
+		maxTime = time.UnixMilli(req.MaxTime)
+	}
+	uc := ctx.MustGet("user").(jwt.UserClaims)
+	items, err := h.svc.GetFeed(ctx, uc.Uid, maxTime, req.Limit)
+	if err != nil {
+		ctx.JSON(http.StatusOK, ginx.Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.l.Error("查询 feed 失败",
+			logger.Int64("uid", uc.Uid),
+			logger.Int64("max_time", req.MaxTime),
+			logger.Error(err))
+		return
+	}
+	ctx.JSON(http.StatusOK, ginx.Result{
+		Data: slice.Map(items, func(idx int, src domain.FeedItem) FeedItemVo {
+			return FeedItemVo{
+				ArticleVo: ArticleVo{
+					Id:         src.Article.Id,
+					Title:      src.Article.Title,
+					Abstract:   src.Article.Abstract(),
+					AuthorId:   src.Article.Author.Id,
+					AuthorName: src.Article.Author.Name,
+					Ctime:      src.Article.Ctime.Format(time.DateTime),
+					Utime:      src.Article.Utime.Format(time.DateTime),
+				},
+				FeedTime: src.Ctime.UnixMilli(),
+			}
+		}),
+	})
+}
@@ -0,0 +1,13 @@
+package web
+
+type FeedListReq struct {
+	// 上一页最后一条的 feedTime，毫秒数，第一页传 0
+	MaxTime int64 `json:"maxTime"`
+	Limit   int   `json:"limit"`
+}
+
+type FeedItemVo struct {
+	ArticleVo
+	// 翻页的时候用这个作为下一页的 maxTime
+	FeedTime int64 `json:"feedTime"`
+}
@@ -0,0 +1,198 @@
+package web
+
+import (
+	"github.com/ecodeclub/ekit/slice"
+	"github.com/gin-gonic/gin"
+	"github.com/wsqigo/basic-go/webook/internal/domain"
+	"github.com/wsqigo/basic-go/webook/internal/service"
+	"github.com/wsqigo/basic-go/webook/internal/web/jwt"
+	"github.com/wsqigo/basic-go/webook/pkg/ginx"
+	"github.com/wsqigo/basic-go/webook/pkg/logger"
+	"net/http"
+	"time"
+)
+
+type FollowHandler struct {
+	svc service.FollowService
+	l   logger.LoggerV1
+}
+
+func NewFollowHandler(svc service.FollowService, l logger.LoggerV1) *FollowHandler {
+	return &FollowHandler{
+		svc: svc,
+		l:   l,
+	}
+}
+
+func (h *FollowHandler) RegisterRoutes(server *gin.Engine) {
+	g := server.Group("/follow")
+	g.POST("/follow", h.Follow)
+	g.POST("/cancel", h.CancelFollow)
+	g.POST("/followees", h.Followees)
+	g.POST("/followers", h.Followers)
+	g.POST("/statics", h.Statics)
+}
+
+func (h *FollowHandler) Follow(ctx *gin.Context) {
+	var req FollowReq
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	uc := ctx.MustGet("user").(jwt.UserClaims)
+	err := h.svc.Follow(ctx, uc.Uid, req.Followee)
+	switch err {
+	case nil:
+		ctx.JSON(http.StatusOK, ginx.Result{
+			Msg: "OK",
+		})
+	case service.ErrFollowSelf:
+		ctx.JSON(http.StatusOK, ginx.Result{
+			Code: 4,
+			Msg:  "不能关注自己",
+		})
+	default:
+		ctx.JSON(http.StatusOK, ginx.Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.l.Error("关注失败",
+			logger.Int64("follower", uc.Uid),
+			logger.Int64("followee", req.Followee),
+			logger.Error(err))
+	}
+}
+
+func (h *FollowHandler) CancelFollow(ctx *gin.Context) {
+	var req FollowReq
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	uc := ctx.MustGet("user").(jwt.UserClaims)
+	err := h.svc.CancelFollow(ctx, uc.Uid, req.Followee)
+	if err != nil {
+		ctx.JSON(http.StatusOK, ginx.Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.l.Error("取消关注失败",
+			logger.Int64("follower", uc.Uid),
+			logger.Int64("followee", req.Followee),
+			logger.Error(err))
+		return
+	}
+	ctx.JSON(http.StatusOK, ginx.Result{
+		Msg: "OK",
+	})
+}
+
+// Followees 关注列表
+func (h *FollowHandler) Followees(ctx *gin.Context) {
+	var req FollowListReq
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	uid := h.uid(ctx, req.Uid)
+	rs, err := h.svc.GetFollowee(ctx, uid, req.MaxId, h.normalizeLimit(req.Limit))
+	if err != nil {
+		ctx.JSON(http.StatusOK, ginx.Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.l.Error("查询关注列表失败",
+			logger.Int64("uid", uid),
+			logger.Int64("max_id", req.MaxId),
+			logger.Error(err))
+		return
+	}
+	ctx.JSON(http.StatusOK, ginx.Result{
+		Data: h.toVos(rs),
+	})
+}
+
+// Followers 粉丝列表
+func (h *FollowHandler) Followers(ctx *gin.Context) {
+	var req FollowListReq
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	uid := h.uid(ctx, req.Uid)
+	rs, err := h.svc.GetFollower(ctx, uid, req.MaxId, h.normalizeLimit(req.Limit))
+	if err != nil {
+		ctx.JSON(http.StatusOK, ginx.Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.l.Error("查询粉丝列表失败",
+			logger.Int64("uid", uid),
+			logger.Int64("max_id", req.MaxId),
+			logger.Error(err))
+		return
+	}
+	ctx.JSON(http.StatusOK, ginx.Result{
+		Data: h.toVos(rs),
+	})
+}
+
+func (h *FollowHandler) Statics(ctx *gin.Context) {
+	var req FollowStaticsReq
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	uc := ctx.MustGet("user").(jwt.UserClaims)
+	uid := h.uid(ctx, req.Uid)
+	statics, err := h.svc.GetFollowStatics(ctx, uid)
+	if err != nil {
+		ctx.JSON(http.StatusOK, ginx.Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.l.Error("查询关注数据失败",
+			logger.Int64("uid", uid),
+			logger.Error(err))
+		return
+	}
+	vo := FollowStaticsVo{
+		Uid:       uid,
+		Followers: statics.Followers,
+		Followees: statics.Followees,
+	}
+	if uid != uc.Uid {
+		// 这个查询失败了不影响主体数据
+		vo.Followed, err = h.svc.Followed(ctx, uc.Uid, uid)
+		if err != nil {
+			h.l.Error("查询关注关系失败",
+				logger.Int64("follower", uc.Uid),
+				logger.Int64("followee", uid),
+				logger.Error(err))
+		}
+	}
+	ctx.JSON(http.StatusOK, ginx.Result{
+		Data: vo,
+	})
+}
+
+// uid 没有指定就是查自己的
+func (h *FollowHandler) uid(ctx *gin.Context, uid int64) int64 {
+	if uid > 0 {
+		return uid
+	}
+	return ctx.MustGet("user").(jwt.UserClaims).Uid
+}
+
+func (h *FollowHandler) normalizeLimit(limit int) int {
+	if limit <= 0 || limit > 100 {
+		return 20
+	}
+	return limit
+}
+
+func (h *FollowHandler) toVos(rs []domain.FollowRelation) []FollowRelationVo {
+	return slice.Map(rs, func(idx int, src domain.FollowRelation) FollowRelationVo {
+		return FollowRelationVo{
+			Id:       src.Id,
+			Follower: src.Follower,
+			Followee: src.Followee,
+			Ctime:    src.Ctime.Format(time.DateTime),
+		}
+	})
+}
@@ -0,0 +1,34 @@
+package web
+
+type FollowReq struct {
+	// 被关注的人
+	Followee int64 `json:"followee"`
+}
+
+type FollowListReq struct {
+	// 查谁的关注列表或者粉丝列表，0 代表自己
+	Uid int64 `json:"uid"`
+	// 上一页最后一条的 ID，第一页传 0
+	MaxId int64 `json:"maxId"`
+	Limit int   `json:"limit"`
+}
+
+type FollowStaticsReq struct {
+	// 0 代表自己
+	Uid int64 `json:"uid"`
+}
+
+type FollowRelationVo struct {
+	Id       int64  `json:"id"`
+	Follower int64  `json:"follower"`
+	Followee int64  `json:"followee"`
+	Ctime    string `json:"ctime"`
+}
+
+type FollowStaticsVo struct {
+	Uid       int64 `json:"uid"`
+	Followers int64 `json:"followers"`
+	Followees int64 `json:"followees"`
+	// 当前登录用户有没有关注 uid
+	Followed bool `json:"followed"`
+}
@@ -0,0 +1,121 @@
+package web
+
+import (
+	"github.com/ecodeclub/ekit/slice"
+	"github.com/gin-gonic/gin"
+	"github.com/wsqigo/basic-go/webook/internal/domain"
+	"github.com/wsqigo/basic-go/webook/internal/service"
+	"github.com/wsqigo/basic-go/webook/internal/web/jwt"
+	"github.com/wsqigo/basic-go/webook/pkg/ginx"
+	"github.com/wsqigo/basic-go/webook/pkg/logger"
+	"net/http"
+	"time"
+)
+
+// HistoryHandler 阅读记录
+type HistoryHandler struct {
+	svc service.HistoryRecordService
+	l   logger.LoggerV1
+}
+
+func NewHistoryHandler(svc service.HistoryRecordService, l logger.LoggerV1) *HistoryHandler {
+	return &HistoryHandler{
+		svc: svc,
+		l:   l,
+	}
+}
+
+func (h *HistoryHandler) RegisterRoutes(server *gin.Engine) {
+	g := server.Group("/history")
+	g.POST("/list", h.List)
+	g.POST("/delete", h.Delete)
+	g.POST("/batch_delete", h.BatchDelete)
+	g.POST("/clear", h.Clear)
+}
+
+func (h *HistoryHandler) List(ctx *gin.Context) {
+	var req HistoryListReq
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	if req.Limit <= 0 || req.Limit > 100 {
+		req.Limit = 20
+	}
+	var maxTime time.Time
+	if req.MaxTime > 0 {
+		maxTime = time.UnixMilli(req.MaxTime)
+	}
+	uc := ctx.MustGet("user").(jwt.UserClaims)
+	rs, err := h.svc.List(ctx, uc.Uid, maxTime, req.MaxId, req.Limit)
+	if err != nil {
+		ctx.JSON(http.StatusOK, ginx.Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.l.Error("查询阅读记录失败",
+			logger.Int64("uid", uc.Uid),
+			logger.Int64("max_time", req.MaxTime),
+			logger.Int64("max_id", req.MaxId),
+			logger.Error(err))
+		return
+	}
+	ctx.JSON(http.StatusOK, ginx.Result{
+		Data: slice.Map(rs, func(idx int, src domain.HistoryRecord) HistoryRecordVo {
+			return HistoryRecordVo{
+				Id:       src.Id,
+				Biz:      src.Biz,
+				BizId:    src.BizId,
+				ReadTime: src.ReadTime.UnixMilli(),
+			}
+		}),
+	})
+}
+
+func (h *HistoryHandler) Delete(ctx *gin.Context) {
+	var req HistoryDeleteReq
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	uc := ctx.MustGet("user").(jwt.UserClaims)
+	err := h.svc.Delete(ctx, uc.Uid, []int64{req.Id})
+	h.writeResult(ctx, err, "删除阅读记录失败", uc.Uid)
+}
+
+func (h *HistoryHandler) BatchDelete(ctx *gin.Context) {
+	var req HistoryBatchDeleteReq
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	if len(req.Ids) > 100 {
+		ctx.JSON(http.StatusOK, ginx.Result{
+			Code: 4,
+			Msg:  "一次最多删除 100 条",
+		})
+		return
+	}
+	uc := ctx.MustGet("user").(jwt.UserClaims)
+	err := h.svc.Delete(ctx, uc.Uid, req.Ids)
+	h.writeResult(ctx, err, "批量删除阅读记录失败", uc.Uid)
+}
+
+func (h *HistoryHandler) Clear(ctx *gin.Context) {
+	uc := ctx.MustGet("user").(jwt.UserClaims)
+	err := h.svc.Clear(ctx, uc.Uid)
+	h.writeResult(ctx, err, "清空阅读记录失败", uc.Uid)
+}
+
+func (h *HistoryHandler) writeResult(ctx *gin.Context, err error, msg string, uid int64) {
+	if err != nil {
+		ctx.JSON(http.StatusOK, ginx.Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.l.Error(msg,
+			logger.Int64("uid", uid),
+			logger.Error(err))
+		return
+	}
+	ctx.JSON(http.StatusOK, ginx.Result{
+		Msg: "OK",
+	})
+}
@@ -0,0 +1,24 @@
+package web
+
+type HistoryListReq struct {
+	// 上一页最后一条的 readTime 和 id，第一页都传 0
+	MaxTime int64 `json:"maxTime"`
+	MaxId   int64 `json:"maxId"`
+	Limit   int   `json:"limit"`
+}
+
+type HistoryDeleteReq struct {
+	Id int64 `json:"id"`
+}
+
+type HistoryBatchDeleteReq struct {
+	Ids []int64 `json:"ids"`
+}
+
+type HistoryRecordVo struct {
+	Id    int64  `json:"id"`
+	Biz   string `json:"biz"`
+	BizId int64  `json:"bizId"`
+	// 最近一次阅读的时间，毫秒数，翻页的时候用这个作为下一页的 maxTime
+	ReadTime int64 `json:"readTime"`
+}
@@ -0,0 +1,267 @@
+package web
+
+import (
+	"github.com/ecodeclub/ekit/slice"
+	"github.com/gin-gonic/gin"
+	"github.com/wsqigo/basic-go/webook/internal/domain"
+	"github.com/wsqigo/basic-go/webook/internal/service"
+	"github.com/wsqigo/basic-go/webook/internal/web/jwt"
+	"github.com/wsqigo/basic-go/webook/pkg/ginx"
+	"github.com/wsqigo/basic-go/webook/pkg/logger"
+	"net/http"
+	"time"
+)
+
+// CronJobHandler 管理后台用的定时任务接口，只有管理员可以调用
+type CronJobHandler struct {
+	svc service.CronJobService
+	l   logger.LoggerV1
+	// 管理员的 uid
+	admins map[int64]struct{}
+}
+
+func NewCronJobHandler(svc service.CronJobService, l logger.LoggerV1, admins []int64) *CronJobHandler {
+	set := make(map[int64]struct{}, len(admins))
+	for _, uid := range admins {
+		set[uid] = struct{}{}
+	}
+	return &CronJobHandler{
+		svc:    svc,
+		l:      l,
+		admins: set,
+	}
+}
+
+func (h *CronJobHandler) RegisterRoutes(server *gin.Engine) {
+	g := server.Group("/admin/jobs", h.checkAdmin)
+	g.POST("/create", h.Create)
+	g.POST("/update", h.Update)
+	g.POST("/pause", h.Pause)
+	g.POST("/resume", h.Resume)
+	g.POST("/delete", h.Delete)
+	g.POST("/list", h.List)
+	g.POST("/executions", h.Executions)
+}
+
+func (h *CronJobHandler) checkAdmin(ctx *gin.Context) {
+	uc := ctx.MustGet("user").(jwt.UserClaims)
+	if _, ok := h.admins[uc.Uid]; !ok {
+		ctx.AbortWithStatus(http.StatusForbidden)
+		h.l.Warn("非管理员访问定时任务接口",
+			logger.Int64("uid", uc.Uid))
+		return
+	}
+}
+
+func (h *CronJobHandler) Create(ctx *gin.Context) {
+	var req JobReq
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	if req.Name == "" || req.Executor == "" {
+		ctx.JSON(http.StatusOK, ginx.Result{
+			Code: 4,
+			Msg:  "任务名字和执行器不能为空",
+		})
+		return
+	}
+	id, err := h.svc.Create(ctx, h.toDomain(req))
+	if err != nil {
+		h.writeErr(ctx, err, "创建定时任务失败", req.Id)
+		return
+	}
+	ctx.JSON(http.StatusOK, ginx.Result{
+		Data: id,
+	})
+}
+
+func (h *CronJobHandler) Update(ctx *gin.Context) {
+	var req JobReq
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	if req.Name == "" || req.Executor == "" {
+		ctx.JSON(http.StatusOK, ginx.Result{
+			Code: 4,
+			Msg:  "任务名字和执行器不能为空",
+		})
+		return
+	}
+	err := h.svc.Update(ctx, h.toDomain(req))
+	h.writeResult(ctx, err, "更新定时任务失败", req.Id)
+}
+
+func (h *CronJobHandler) Pause(ctx *gin.Context) {
+	var req JobIdReq
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	err := h.svc.Pause(ctx, req.Id)
+	h.writeResult(ctx, err, "暂停定时任务失败", req.Id)
+}
+
+func (h *CronJobHandler) Resume(ctx *gin.Context) {
+	var req JobIdReq
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	err := h.svc.Resume(ctx, req.Id)
+	h.writeResult(ctx, err, "恢复定时任务失败", req.Id)
+}
+
+func (h *CronJobHandler) Delete(ctx *gin.Context) {
+	var req JobIdReq
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	err := h.svc.Delete(ctx, req.Id)
+	h.writeResult(ctx, err, "删除定时任务失败", req.Id)
+}
+
+func (h *CronJobHandler) List(ctx *gin.Context) {
+	var page Page
+	if err := ctx.Bind(&page); err != nil {
+		return
+	}
+	if page.Limit <= 0 || page.Limit > 100 {
+		page.Limit = 20
+	}
+	jobs, err := h.svc.List(ctx, page.Offset, page.Limit)
+	if err != nil {
+		ctx.JSON(http.StatusOK, ginx.Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.l.Error("查询定时任务列表失败",
+			logger.Int("offset", page.Offset),
+			logger.Int("limit", page.Limit),
+			logger.Error(err))
+		return
+	}
+	ctx.JSON(http.StatusOK, ginx.Result{
+		Data: slice.Map(jobs, func(idx int, src domain.Job) JobVo {
+			return h.toVo(src)
+		}),
+	})
+}
+
+func (h *CronJobHandler) Executions(ctx *gin.Context) {
+	var req JobExecutionListReq
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	if req.Limit <= 0 || req.Limit > 100 {
+		req.Limit = 20
+	}
+	es, err := h.svc.ListExecutions(ctx, req.Jid, req.MaxId, req.Limit)
+	if err != nil {
+		ctx.JSON(http.StatusOK, ginx.Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.l.Error("查询任务执行记录失败",
+			logger.Int64("jid", req.Jid),
+			logger.Int64("max_id", req.MaxId),
+			logger.Error(err))
+		return
+	}
+	ctx.JSON(http.StatusOK, ginx.Result{
+		Data: slice.Map(es, func(idx int, src domain.JobExecution) JobExecutionVo {
+			vo := JobExecutionVo{
+				Id:        src.Id,
+				Jid:       src.Jid,
+				Executor:  src.Executor,
+				NodeId:    src.NodeId,
+				StartTime: src.StartTime.Format(time.DateTime),
+				Status:    src.Status.String(),
+				ErrMsg:    src.ErrMsg,
+			}
+			if !src.EndTime.IsZero() {
+				vo.EndTime = src.EndTime.Format(time.DateTime)
+				vo.Duration = src.EndTime.Sub(src.StartTime).Milliseconds()
+			}
+			return vo
+		}),
+	})
+}
+
+func (h *CronJobHandler) writeResult(ctx *gin.Context, err error, msg string, id int64) {
+	if err != nil {
+		h.writeErr(ctx, err, msg, id)
+		return
+	}
+	ctx.JSON(http.StatusOK, ginx.Result{
+		Msg: "OK",
+	})
+}
+
+func (h *CronJobHandler) writeErr(ctx *gin.Context, err error, msg string, id int64) {
+	switch err {
+	case service.ErrInvalidJobExpression:
+		ctx.JSON(http.StatusOK, ginx.Result{
+			Code: 4,
+			Msg:  "cron 表达式不合法",
+		})
+	case service.ErrInvalidJobRetry:
+		ctx.JSON(http.StatusOK, ginx.Result{
+			Code: 4,
+			Msg:  "重试配置不合法",
+		})
+	case service.ErrJobNameExists:
+		ctx.JSON(http.StatusOK, ginx.Result{
+			Code: 4,
+			Msg:  "任务名字已经存在",
+		})
+	case service.ErrJobNotFound:
+		ctx.JSON(http.StatusOK, ginx.Result{
+			Code: 4,
+			Msg:  "任务不存在",
+		})
+	default:
+		ctx.JSON(http.StatusOK, ginx.Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.l.Error(msg,
+			logger.Int64("jid", id),
+			logger.Error(err))
+	}
+}
+
+func (h *CronJobHandler) toDomain(req JobReq) domain.Job {
+	return domain.Job{
+		Id:            req.Id,
+		Name:          req.Name,
+		Executor:      req.Executor,
+		Expression:    req.Expression,
+		Cfg:           req.Cfg,
+		RetryMax:      req.RetryMax,
+		RetryInterval: time.Duration(req.RetryInterval) * time.Millisecond,
+	}
+}
+
+func (h *CronJobHandler) toVo(j domain.Job) JobVo {
+	vo := JobVo{
+		Id:            j.Id,
+		Name:          j.Name,
+		Executor:      j.Executor,
+		Expression:    j.Expression,
+		Cfg:           j.Cfg,
+		RetryMax:      j.RetryMax,
+		RetryInterval: j.RetryInterval.Milliseconds(),
+		Status:        j.Status.String(),
+		NextTime:      j.NextRunTime.Format(time.DateTime),
+		LastRunStatus: "never",
+		LastRunErr:    j.LastRunErr,
+		Ctime:         j.Ctime.Format(time.DateTime),
+		Utime:         j.Utime.Format(time.DateTime),
+	}
+	if !j.LastRunTime.IsZero() {
+		vo.LastRunTime = j.LastRunTime.Format(time.DateTime)
+		vo.LastRunStatus = "success"
+		if j.LastRunErr != "" {
+			vo.LastRunStatus = "failed"
+		}
+	}
+	return vo
+}
@@ -0,0 +1,62 @@
+package web
+
+type JobReq struct {
+	// 更新的时候才需要
+	Id   int64  `json:"id"`
+	Name string `json:"name"`
+	// 执行器的名字，比如 local
+	Executor string `json:"executor"`
+	// cron 表达式，支持秒级，比如 0 */5 * * * *
+	Expression string `json:"expression"`
+	Cfg        string `json:"cfg"`
+	// 失败之后最多重试几次，0 表示不重试
+	RetryMax int `json:"retryMax"`
+	// 重试间隔，毫秒
+	RetryInterval int64 `json:"retryInterval"`
+}
+
+type JobIdReq struct {
+	Id int64 `json:"id"`
+}
+
+type JobVo struct {
+	Id         int64  `json:"id"`
+	Name       string `json:"name"`
+	Executor   string `json:"executor"`
+	Expression string `json:"expression"`
+	Cfg        string `json:"cfg"`
+	RetryMax   int    `json:"retryMax"`
+	// 毫秒
+	RetryInterval int64 `json:"retryInterval"`
+	// waiting, running 或者 paused
+	Status   string `json:"status"`
+	NextTime string `json:"nextTime"`
+	// 最近一次执行的情况，没有执行过的话 LastRunStatus 是 never
+	LastRunTime   string `json:"lastRunTime"`
+	LastRunStatus string `json:"lastRunStatus"`
+	LastRunErr    string `json:"lastRunErr"`
+	Ctime         string `json:"ctime"`
+	Utime         string `json:"utime"`
+}
+
+type JobExecutionListReq struct {
+	Jid int64 `json:"jid"`
+	// 上一页最后一条的 ID，第一页传 0
+	MaxId int64 `json:"maxId"`
+	Limit int   `json:"limit"`
+}
+
+type JobExecutionVo struct {
+	Id        int64  `json:"id"`
+	Jid       int64  `json:"jid"`
+	Executor  string `json:"executor"`
+	NodeId    string `json:"nodeId"`
+	StartTime string `json:"startTime"`
+	// 还没执行完是空字符串
+	EndTime string `json:"endTime"`
+	// 执行耗时，毫秒
+	Duration int64 `json:"duration"`
+	// running, success 或者 failed
+	Status string `json:"status"`
+	ErrMsg string `json:"errMsg"`
+}
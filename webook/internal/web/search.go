@@ -0,0 +1,121 @@
+package web
+
+import (
+	"github.com/ecodeclub/ekit/slice"
+	"github.com/gin-gonic/gin"
+	"github.com/wsqigo/basic-go/webook/internal/domain"
+	"github.com/wsqigo/basic-go/webook/internal/service"
+	"github.com/wsqigo/basic-go/webook/pkg/ginx"
+	"github.com/wsqigo/basic-go/webook/pkg/logger"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type SearchHandler struct {
+	svc service.SearchService
+	l   logger.LoggerV1
+}
+
+func NewSearchHandler(svc service.SearchService, l logger.LoggerV1) *SearchHandler {
+	return &SearchHandler{
+		svc: svc,
+		l:   l,
+	}
+}
+
+func (h *SearchHandler) RegisterRoutes(server *gin.Engine) {
+	// /search?q=关键字&type=article&offset=0&limit=20
+	// type 可以是 article 或者 user，默认是 article
+	server.GET("/search", h.Search)
+}
+
+func (h *SearchHandler) Search(ctx *gin.Context) {
+	q := strings.TrimSpace(ctx.Query("q"))
+	if q == "" {
+		ctx.JSON(http.StatusOK, ginx.Result{
+			Code: 4,
+			Msg:  "搜索关键字不能为空",
+		})
+		return
+	}
+	// 太长的关键字没有意义，还会拖慢搜索
+	if qs := []rune(q); len(qs) > 64 {
+		q = string(qs[:64])
+	}
+	offset, _ := strconv.Atoi(ctx.Query("offset"))
+	if offset < 0 {
+		offset = 0
+	}
+	limit, _ := strconv.Atoi(ctx.Query("limit"))
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	typ := ctx.DefaultQuery("type", "article")
+	switch typ {
+	case "article":
+		h.searchArticle(ctx, q, offset, limit)
+	case "user":
+		h.searchUser(ctx, q, offset, limit)
+	default:
+		ctx.JSON(http.StatusOK, ginx.Result{
+			Code: 4,
+			Msg:  "不支持的搜索类型",
+		})
+	}
+}
+
+func (h *SearchHandler) searchArticle(ctx *gin.Context, q string, offset, limit int) {
+	res, err := h.svc.SearchArticle(ctx, q, offset, limit)
+	if err != nil {
+		ctx.JSON(http.StatusOK, ginx.Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.l.Error("搜索文章失败",
+			logger.String("q", q),
+			logger.Error(err))
+		return
+	}
+	ctx.JSON(http.StatusOK, ginx.Result{
+		Data: SearchResultVo[SearchArticleVo]{
+			Total: res.Total,
+			Items: slice.Map(res.Articles, func(idx int, src domain.SearchArticle) SearchArticleVo {
+				return SearchArticleVo{
+					Id:       src.Id,
+					AuthorId: src.AuthorId,
+					Title:    src.Title,
+					Abstract: src.Abstract,
+					Utime:    src.Utime.Format(time.DateTime),
+				}
+			}),
+		},
+	})
+}
+
+func (h *SearchHandler) searchUser(ctx *gin.Context, q string, offset, limit int) {
+	res, err := h.svc.SearchUser(ctx, q, offset, limit)
+	if err != nil {
+		ctx.JSON(http.StatusOK, ginx.Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+		h.l.Error("搜索用户失败",
+			logger.String("q", q),
+			logger.Error(err))
+		return
+	}
+	ctx.JSON(http.StatusOK, ginx.Result{
+		Data: SearchResultVo[SearchUserVo]{
+			Total: res.Total,
+			Items: slice.Map(res.Users, func(idx int, src domain.SearchUser) SearchUserVo {
+				return SearchUserVo{
+					Id:       src.Id,
+					Nickname: src.Nickname,
+					AboutMe:  src.AboutMe,
+				}
+			}),
+		},
+	})
+}
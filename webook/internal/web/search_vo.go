@@ -0,0 +1,21 @@
+package web
+
+type SearchResultVo[T any] struct {
+	Total int `json:"total"`
+	Items []T `json:"items"`
+}
+
+// SearchArticleVo 标题和摘要里面命中的部分会用 <em></em> 包起来
+type SearchArticleVo struct {
+	Id       int64  `json:"id"`
+	AuthorId int64  `json:"authorId"`
+	Title    string `json:"title"`
+	Abstract string `json:"abstract"`
+	Utime    string `json:"utime"`
+}
+
+type SearchUserVo struct {
+	Id       int64  `json:"id"`
+	Nickname string `json:"nickname"`
+	AboutMe  string `json:"aboutMe"`
+}
@@ -0,0 +1,67 @@
+package web
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/wsqigo/basic-go/webook/internal/errs"
+	"github.com/wsqigo/basic-go/webook/internal/service/sms/auth"
+	"github.com/wsqigo/basic-go/webook/internal/service/sms/template"
+	"github.com/wsqigo/basic-go/webook/pkg/ginx"
+	"net/http"
+	"strings"
+)
+
+// SMSHandler 给内部其它服务用的发送短信的接口，只在内网暴露
+// 调用方在 Authorization 头部带上我们签发的 token
+type SMSHandler struct {
+	svc auth.Service
+}
+
+func NewSMSHandler(svc auth.Service) *SMSHandler {
+	return &SMSHandler{
+		svc: svc,
+	}
+}
+
+func (h *SMSHandler) RegisterRoutes(server *gin.Engine) {
+	g := server.Group("/sms")
+	g.POST("/send", h.Send)
+}
+
+func (h *SMSHandler) Send(ctx *gin.Context) {
+	var req SMSSendReq
+	if err := ctx.Bind(&req); err != nil {
+		return
+	}
+	// Bearer xxxx
+	token := strings.TrimPrefix(ctx.GetHeader("Authorization"), "Bearer ")
+	err := h.svc.Send(ctx, token, req.Tpl, req.Args, req.Numbers...)
+	switch {
+	case err == nil:
+		ctx.JSON(http.StatusOK, ginx.Result{Msg: "发送成功"})
+	case errors.Is(err, auth.ErrInvalidToken), errors.Is(err, auth.ErrUnknownCaller):
+		ctx.AbortWithStatus(http.StatusUnauthorized)
+	case errors.Is(err, auth.ErrTemplateNotAllowed):
+		ctx.JSON(http.StatusOK, ginx.Result{
+			Code: errs.SMSAPITemplateNotAllowed,
+			Msg:  "不能使用这个模板",
+		})
+	case errors.Is(err, auth.ErrQuotaExceeded):
+		ctx.JSON(http.StatusOK, ginx.Result{
+			Code: errs.SMSAPIQuotaExceeded,
+			Msg:  "短信配额用完了",
+		})
+	case errors.Is(err, template.ErrInvalidArgs), errors.Is(err, template.ErrUnknownTemplate),
+		errors.Is(err, auth.ErrEmptyNumbers), errors.Is(err, auth.ErrTooManyNumbers):
+		ctx.JSON(http.StatusOK, ginx.Result{
+			Code: errs.SMSAPIInvalidInput,
+			Msg:  err.Error(),
+		})
+	default:
+		// 审计日志里面已经记录了调用方和错误
+		ctx.JSON(http.StatusOK, ginx.Result{
+			Code: 5,
+			Msg:  "系统错误",
+		})
+	}
+}
@@ -0,0 +1,8 @@
+package web
+
+type SMSSendReq struct {
+	// 逻辑模板的名字，比如说 login_code
+	Tpl     string   `json:"tpl"`
+	Args    []string `json:"args"`
+	Numbers []string `json:"numbers"`
+}
@@ -19,6 +19,8 @@ const (
 	emailRegexPattern    = `\w+([-+.]\w+)*@\w+([-.]\w+)*\.\w+([-.]\w+)*$`
 	passwordRegexPattern = `^(?=.*[A-Za-z])(?=.*\d)(?=.*[$@$!%*#?&])[A-Za-z\d$@$!%*#?&]{8,}$`
 	bizLogin             = "login"
+	// 客户端在这个 header 里面上报设备 ID
+	headerDeviceId = "X-Device-Id"
 )
 
 // UserHandler 用户路由
@@ -26,15 +28,18 @@ type UserHandler struct {
 	jwt2.Handler
 	svc            service.UserService
 	codeSvc        service.CodeService
+	codeGuard      service.CodeSendGuard
 	emailEexReg    *regexp.Regexp
 	passwordRegExp *regexp.Regexp
 }
 
 func NewUserHandler(svc service.UserService,
-	hdl jwt2.Handler, codeSvc service.CodeService) *UserHandler {
+	hdl jwt2.Handler, codeSvc service.CodeService,
+	codeGuard service.CodeSendGuard) *UserHandler {
 	return &UserHandler{
 		svc:            svc,
 		codeSvc:        codeSvc,
+		codeGuard:      codeGuard,
 		emailEexReg:    regexp.MustCompile(emailRegexPattern, regexp.None),
 		passwordRegExp: regexp.MustCompile(passwordRegexPattern, regexp.None),
 		Handler:        hdl,
@@ -120,7 +125,19 @@ func (h *UserHandler) SendSMSLoginCode(ctx *gin.Context,
 		}, nil
 	}
 
-	err := h.codeSvc.Send(ctx, bizLogin, req.Phone)
+	// 防刷，检查没通过就不会走到服务商那里
+	err := h.codeGuard.Check(ctx, domain.CodeSendRequest{
+		Biz:        bizLogin,
+		Phone:      req.Phone,
+		IP:         ctx.ClientIP(),
+		DeviceId:   ctx.GetHeader(headerDeviceId),
+		HumanToken: req.HumanToken,
+	})
+	if err != nil {
+		return h.codeGuardResult(err)
+	}
+
+	err = h.codeSvc.Send(ctx, bizLogin, req.Phone)
 	switch err {
 	case nil:
 		return ginx.Result{
@@ -145,6 +162,27 @@ func (h *UserHandler) SendSMSLoginCode(ctx *gin.Context,
 	}
 }
 
+func (h *UserHandler) codeGuardResult(err error) (ginx.Result, error) {
+	var res ginx.Result
+	switch err {
+	case service.ErrCodeSendPhoneBlocked:
+		res = ginx.Result{Code: errs.SMSPhoneBlocked, Msg: "该手机号码暂不支持验证码登录"}
+	case service.ErrCodeSendHumanCheck:
+		res = ginx.Result{Code: errs.SMSHumanCheckFailed, Msg: "请先完成人机校验"}
+	case service.ErrCodeSendPhoneLimited:
+		res = ginx.Result{Code: errs.SMSPhoneTooMany, Msg: "该手机号码今天发送的验证码太多了，请明天再试"}
+	case service.ErrCodeSendIPLimited:
+		res = ginx.Result{Code: errs.SMSIPTooMany, Msg: "短信发送太频繁，请稍后再试"}
+	case service.ErrCodeSendDeviceLimited:
+		res = ginx.Result{Code: errs.SMSDeviceTooMany, Msg: "短信发送太频繁，请稍后再试"}
+	default:
+		return ginx.Result{Code: 5, Msg: "系统错误"}, err
+	}
+	// 偶尔触发是正常的，频繁出现就代表有人在刷接口
+	zap.L().Warn("拦截发送验证码的请求", zap.Error(err))
+	return res, nil
+}
+
 // SignUp 用户注册接口
 func (h *UserHandler) SignUp(ctx *gin.Context, req SignUpReq) (ginx.Result, error) {
 	isEmail, err := h.emailEexReg.MatchString(req.Email)
@@ -27,4 +27,6 @@ type UserEditReq struct {
 
 type SendSMSCodeReq struct {
 	Phone string `json:"phone"`
+	// 人机校验之后拿到的凭证
+	HumanToken string `json:"humanToken"`
 }
@@ -0,0 +1,34 @@
+package ioc
+
+import (
+	"fmt"
+	"github.com/spf13/viper"
+	"github.com/wsqigo/basic-go/webook/internal/repository/dao"
+	"github.com/wsqigo/basic-go/webook/pkg/logger"
+	"gorm.io/gorm"
+)
+
+// InitArticleDAO 根据配置来决定文章存在哪里，默认是 MySQL
+func InitArticleDAO(db *gorm.DB, l logger.LoggerV1) dao.ArticleDAO {
+	type Config struct {
+		// gorm, mongodb 或者 s3
+		DAO string `yaml:"dao"`
+	}
+	cfg := Config{DAO: "gorm"}
+	err := viper.UnmarshalKey("article", &cfg)
+	if err != nil {
+		panic(fmt.Errorf("初始化配置失败 %v", err))
+	}
+	switch cfg.DAO {
+	case "mongodb":
+		// 只有用到的时候才去连 MongoDB
+		return dao.NewArticleMongoDBDAO(InitMongoDB(), InitSnowflakeNode(), dao.NewGORMOutboxDAO(db))
+	case "s3":
+		// 元数据在 MySQL，内容在对象存储
+		return dao.NewArticleS3DAO(db, InitOSS(), l)
+	case "gorm":
+		return dao.NewArticleGORMDAO(db)
+	default:
+		panic(fmt.Errorf("未知的文章存储 %s", cfg.DAO))
+	}
+}
@@ -1,20 +1,81 @@
 package ioc
 
 import (
+	"context"
 	rlock "github.com/gotomicro/redis-lock"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/robfig/cron/v3"
+	"github.com/spf13/viper"
+	"github.com/wsqigo/basic-go/webook/internal/events/article"
 	"github.com/wsqigo/basic-go/webook/internal/job"
+	"github.com/wsqigo/basic-go/webook/internal/repository/cache"
 	"github.com/wsqigo/basic-go/webook/internal/service"
+	"github.com/wsqigo/basic-go/webook/internal/web"
 	"github.com/wsqigo/basic-go/webook/pkg/logger"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
 	"time"
 )
 
-func InitRankingJob(svc service.RankingService, client *rlock.Client, l logger.LoggerV1) *job.RankingJob {
-	return job.NewRankingJob(svc, l, client, 30*time.Second)
+func InitRankingJob(svc service.RankingService, client *rlock.Client,
+	balancer *job.LoadBalancer, l logger.LoggerV1) *job.RankingJob {
+	return job.NewRankingJob(svc, l, client, balancer, 30*time.Second)
 }
 
-func InitJobs(l logger.LoggerV1, rjob *job.RankingJob) *cron.Cron {
+// InitLoadBalancer 只有负载最低的 topK 个节点会去执行任务
+func InitLoadBalancer(c cache.NodeLoadCache, l logger.LoggerV1) *job.LoadBalancer {
+	type Config struct {
+		TopK     int64         `yaml:"topK"`
+		Interval time.Duration `yaml:"interval"`
+	}
+	cfg := Config{
+		TopK:     3,
+		Interval: 10 * time.Second,
+	}
+	err := viper.UnmarshalKey("job.loadBalancer", &cfg)
+	if err != nil {
+		panic(err)
+	}
+	return job.NewLoadBalancer(c, cfg.TopK, cfg.Interval, job.GoroutineLoad, l)
+}
+
+func InitHistoryCleanJob(svc service.HistoryRecordService, l logger.LoggerV1) *job.HistoryCleanJob {
+	type Config struct {
+		// 阅读记录保留多少天
+		RetentionDays int `yaml:"retentionDays"`
+	}
+	cfg := Config{
+		RetentionDays: 180,
+	}
+	err := viper.UnmarshalKey("history", &cfg)
+	if err != nil {
+		panic(err)
+	}
+	return job.NewHistoryCleanJob(svc, l,
+		time.Duration(cfg.RetentionDays)*24*time.Hour, 10*time.Minute)
+}
+
+func InitOutboxRelayJob(relay *article.OutboxRelay, client *rlock.Client, l logger.LoggerV1) *job.OutboxRelayJob {
+	return job.NewOutboxRelayJob(relay, client, l, 100)
+}
+
+func InitOutboxCleanJob(relay *article.OutboxRelay, l logger.LoggerV1) *job.OutboxCleanJob {
+	type Config struct {
+		// 投递之后保留多久
+		Retention time.Duration `yaml:"retention"`
+	}
+	cfg := Config{
+		Retention: 7 * 24 * time.Hour,
+	}
+	err := viper.UnmarshalKey("outbox", &cfg)
+	if err != nil {
+		panic(err)
+	}
+	return job.NewOutboxCleanJob(relay, l, cfg.Retention)
+}
+
+func InitJobs(l logger.LoggerV1, rjob *job.RankingJob, hjob *job.HistoryCleanJob,
+	ojob *job.OutboxRelayJob, ocjob *job.OutboxCleanJob, sjob *job.AsyncSmsCleanJob) *cron.Cron {
 	builder := job.NewCronJobBuilder(l, prometheus.SummaryOpts{
 		Namespace: "geekbang_daming",
 		Subsystem: "webook",
@@ -28,10 +89,93 @@ func InitJobs(l logger.LoggerV1, rjob *job.RankingJob) *cron.Cron {
 			0.999: 0.0001,
 		},
 	})
+	tracer := otel.Tracer("webook/internal/job")
 	expr := cron.New(cron.WithSeconds())
-	_, err := expr.AddJob("@every 1m", builder.Build(rjob))
+	// 超时加上重试，一次可能跑好几分钟，上一次还没跑完就跳过
+	_, err := expr.AddJob("@every 1m", cron.NewChain(cron.SkipIfStillRunning(cron.DiscardLogger)).
+		Then(builder.Build(decorateJob(rjob, l, tracer, 50*time.Second))))
+	if err != nil {
+		panic(err)
+	}
+	// 每天凌晨三点清理过期的阅读记录
+	_, err = expr.AddJob("0 0 3 * * *",
+		builder.Build(decorateJob(hjob, l, tracer, 30*time.Minute)))
+	if err != nil {
+		panic(err)
+	}
+	// 每秒投递一次 outbox，上一次还没跑完就跳过，避免同一个节点上并发投递
+	// 多个节点之间靠分布式锁，只有一个节点投递
+	_, err = expr.AddJob("* * * * * *", cron.NewChain(cron.SkipIfStillRunning(cron.DiscardLogger)).
+		Then(builder.Build(decorateJob(ojob, l, tracer, 30*time.Second))))
+	if err != nil {
+		panic(err)
+	}
+	// 每天凌晨四点清理已经投递的 outbox 事件
+	_, err = expr.AddJob("0 0 4 * * *",
+		builder.Build(decorateJob(ocjob, l, tracer, 30*time.Minute)))
+	if err != nil {
+		panic(err)
+	}
+	// 每天凌晨五点清理已经结束的异步短信
+	_, err = expr.AddJob("0 0 5 * * *",
+		builder.Build(decorateJob(sjob, l, tracer, 30*time.Minute)))
 	if err != nil {
 		panic(err)
 	}
 	return expr
 }
+
+// decorateJob 从里到外依次是：单次运行超时，指数退避重试，连续失败告警，链路追踪
+// 所以一个 span 里面包含了所有的重试，告警也是在重试都失败之后才计数
+func decorateJob(j job.Job, l logger.LoggerV1, tracer trace.Tracer, timeout time.Duration) job.Job {
+	j = job.NewTimeoutJob(j, timeout)
+	j = job.NewRetryJob(j, l, time.Second, 10*time.Second, 3)
+	j = job.NewAlertJob(j, 3, func(ctx context.Context, name string, cnt int, err error) {
+		// 这里可以换成钉钉，短信之类的告警
+		l.Error("定时任务连续失败",
+			logger.String("name", name),
+			logger.Int("cnt", cnt),
+			logger.Error(err))
+	})
+	return job.NewTraceJob(j, tracer)
+}
+
+func InitCronJobHandler(svc service.CronJobService, l logger.LoggerV1) *web.CronJobHandler {
+	type Config struct {
+		// 可以管理定时任务的用户
+		Uids []int64 `yaml:"uids"`
+	}
+	var cfg Config
+	err := viper.UnmarshalKey("admin", &cfg)
+	if err != nil {
+		panic(err)
+	}
+	return web.NewCronJobHandler(svc, l, cfg.Uids)
+}
+
+// InitScheduler 分布式任务调度，远程执行器从配置里面读
+func InitScheduler(svc service.CronJobService, balancer *job.LoadBalancer, l logger.LoggerV1) *job.Scheduler {
+	type ExecutorConfig struct {
+		Name     string        `yaml:"name"`
+		Endpoint string        `yaml:"endpoint"`
+		Timeout  time.Duration `yaml:"timeout"`
+	}
+	type Config struct {
+		HttpExecutors []ExecutorConfig `yaml:"httpExecutors"`
+	}
+	var cfg Config
+	err := viper.UnmarshalKey("job", &cfg)
+	if err != nil {
+		panic(err)
+	}
+	s := job.NewScheduler(svc, balancer, l)
+	s.RegisterExecutor(job.NewLocalFuncExecutor())
+	for _, ec := range cfg.HttpExecutors {
+		timeout := ec.Timeout
+		if timeout <= 0 {
+			timeout = 10 * time.Second
+		}
+		s.RegisterExecutor(job.NewHttpExecutor(ec.Name, ec.Endpoint, timeout))
+	}
+	return s
+}
@@ -2,9 +2,17 @@ package ioc
 
 import (
 	"github.com/IBM/sarama"
+	"github.com/redis/go-redis/v9"
 	"github.com/spf13/viper"
 	"github.com/wsqigo/basic-go/webook/internal/events"
 	"github.com/wsqigo/basic-go/webook/internal/events/article"
+	"github.com/wsqigo/basic-go/webook/internal/events/feed"
+	"github.com/wsqigo/basic-go/webook/internal/events/search"
+	"github.com/wsqigo/basic-go/webook/internal/service"
+	"github.com/wsqigo/basic-go/webook/pkg/logger"
+	"github.com/wsqigo/basic-go/webook/pkg/saramax"
+	"os"
+	"time"
 )
 
 func InitSaramaClient() sarama.Client {
@@ -33,6 +41,116 @@ func InitSyncProducer(c sarama.Client) sarama.SyncProducer {
 	return p
 }
 
-func InitConsumers(c1 *article.InteractiveReadEventConsumer) []events.Consumer {
-	return []events.Consumer{c1}
+// InitFailureHandler 消费失败的消息先转发到重试 topic，重试次数耗尽之后进死信 topic
+func InitFailureHandler(p sarama.SyncProducer, l logger.LoggerV1) *saramax.FailureHandler {
+	type Config struct {
+		// 最多转发到重试 topic 多少次
+		MaxAttempts int `yaml:"maxAttempts"`
+		// 第 n 次重试延迟 n * delay
+		Delay time.Duration `yaml:"delay"`
+	}
+	cfg := Config{
+		MaxAttempts: 3,
+		Delay:       10 * time.Second,
+	}
+	err := viper.UnmarshalKey("kafka.retry", &cfg)
+	if err != nil {
+		panic(err)
+	}
+	return saramax.NewFailureHandler(p, cfg.MaxAttempts, cfg.Delay, l)
+}
+
+// InitReadEventBatchConfig 阅读事件是批量消费的，吞吐量通过这里调整
+func InitReadEventBatchConfig() saramax.BatchConfig {
+	cfg := saramax.DefaultBatchConfig()
+	err := viper.UnmarshalKey("kafka.readEventBatch", &cfg)
+	if err != nil {
+		panic(err)
+	}
+	return cfg
+}
+
+// InitReadEventDeduper 记住处理过的阅读事件，过期时间要覆盖重复消费的时间窗口
+func InitReadEventDeduper(client redis.Cmdable) saramax.Deduper {
+	type Config struct {
+		Expiration time.Duration `yaml:"expiration"`
+	}
+	cfg := Config{
+		Expiration: time.Hour,
+	}
+	err := viper.UnmarshalKey("kafka.readEventDedup", &cfg)
+	if err != nil {
+		panic(err)
+	}
+	return saramax.NewRedisDeduper(client, "interactive", cfg.Expiration)
+}
+
+// InitArticlePublishEventConsumer 发表事件从 outbox 投递过来，按照幂等键去重
+func InitArticlePublishEventConsumer(svc service.FeedService, client sarama.Client,
+	failure *saramax.FailureHandler, cmd redis.Cmdable, l logger.LoggerV1) *feed.ArticlePublishEventConsumer {
+	type Config struct {
+		Expiration time.Duration `yaml:"expiration"`
+	}
+	cfg := Config{
+		Expiration: 24 * time.Hour,
+	}
+	err := viper.UnmarshalKey("kafka.publishEventDedup", &cfg)
+	if err != nil {
+		panic(err)
+	}
+	dedup := saramax.NewRedisDeduper(cmd, "feed", cfg.Expiration)
+	return feed.NewArticlePublishEventConsumer(svc, client, failure, dedup, l)
+}
+
+func InitAsyncProducer(c sarama.Client) sarama.AsyncProducer {
+	p, err := sarama.NewAsyncProducerFromClient(c)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// InitArticleProducer 文章相关的事件异步发送，阅读事件量大，超过内存上限就丢弃
+func InitArticleProducer(p sarama.AsyncProducer, l logger.LoggerV1) article.Producer {
+	type Config struct {
+		// 发送中的消息最多占用多少字节
+		MaxBytes int64 `yaml:"maxBytes"`
+	}
+	cfg := Config{
+		MaxBytes: 16 << 20,
+	}
+	err := viper.UnmarshalKey("kafka.asyncProducer", &cfg)
+	if err != nil {
+		panic(err)
+	}
+	return article.NewSaramaAsyncProducer(p, cfg.MaxBytes, nil, l)
+}
+
+// InitSearchSyncConsumer 每个实例都要有自己固定的消费者组，
+// 比如说用 StatefulSet 的 pod 名字，没有配置就用主机名
+func InitSearchSyncConsumer(svc service.SearchService,
+	client sarama.Client, l logger.LoggerV1) *search.SyncConsumer {
+	type Config struct {
+		GroupId string `yaml:"groupId"`
+	}
+	var cfg Config
+	err := viper.UnmarshalKey("search", &cfg)
+	if err != nil {
+		panic(err)
+	}
+	if cfg.GroupId == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			panic(err)
+		}
+		cfg.GroupId = "search_" + hostname
+	}
+	return search.NewSyncConsumer(svc, client, cfg.GroupId, l)
+}
+
+func InitConsumers(c1 *article.InteractiveReadEventConsumer,
+	c2 *feed.ArticlePublishEventConsumer,
+	c3 *search.SyncConsumer,
+	c4 *article.HistoryRecordConsumer) []events.Consumer {
+	return []events.Consumer{c1, c2, c3, c4}
 }
@@ -0,0 +1,80 @@
+package ioc
+
+import (
+	"context"
+	"github.com/spf13/viper"
+	"github.com/wsqigo/basic-go/webook/pkg/logger"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// Lifecycle 收到退出信号之后，按照注册的顺序依次关闭各个组件
+// 所有的步骤共用一个超时时间，超时之后剩下的步骤就放弃了
+type Lifecycle struct {
+	l       logger.LoggerV1
+	timeout time.Duration
+	hooks   []stopHook
+}
+
+type stopHook struct {
+	name string
+	fn   func(ctx context.Context) error
+}
+
+func InitLifecycle(l logger.LoggerV1) *Lifecycle {
+	type Config struct {
+		// 优雅退出最多等多久
+		Timeout time.Duration `yaml:"timeout"`
+	}
+	cfg := Config{
+		Timeout: 30 * time.Second,
+	}
+	err := viper.UnmarshalKey("shutdown", &cfg)
+	if err != nil {
+		panic(err)
+	}
+	return &Lifecycle{l: l, timeout: cfg.Timeout}
+}
+
+// OnStop 注册关闭的步骤，先注册的先关闭
+func (lc *Lifecycle) OnStop(name string, fn func(ctx context.Context) error) {
+	lc.hooks = append(lc.hooks, stopHook{name: name, fn: fn})
+}
+
+// WaitForSignal 阻塞直到收到 SIGINT 或者 SIGTERM，然后关闭所有的组件
+func (lc *Lifecycle) WaitForSignal() {
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, syscall.SIGINT, syscall.SIGTERM)
+	sig := <-ch
+	signal.Stop(ch)
+	lc.l.Info("收到退出信号，开始优雅退出", logger.String("signal", sig.String()))
+	lc.Stop()
+}
+
+func (lc *Lifecycle) Stop() {
+	ctx, cancel := context.WithTimeout(context.Background(), lc.timeout)
+	defer cancel()
+	for _, hook := range lc.hooks {
+		// 有些组件关闭的时候不看 ctx，所以单独开一个 goroutine，超时了就不等了
+		done := make(chan error, 1)
+		go func(hook stopHook) {
+			done <- hook.fn(ctx)
+		}(hook)
+		select {
+		case err := <-done:
+			if err != nil {
+				lc.l.Error("关闭失败",
+					logger.String("name", hook.name),
+					logger.Error(err))
+				continue
+			}
+			lc.l.Info("关闭成功", logger.String("name", hook.name))
+		case <-ctx.Done():
+			lc.l.Error("优雅退出超时，放弃剩下的步骤",
+				logger.String("name", hook.name))
+			return
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package ioc
+
+import (
+	"fmt"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/ecodeclub/ekit"
+	"github.com/spf13/viper"
+	"github.com/wsqigo/basic-go/webook/pkg/ossx"
+	"os"
+)
+
+// InitOSS 初始化对象存储
+// 本地开发可以用 local，直接写本地文件
+func InitOSS() ossx.Store {
+	type Config struct {
+		// s3 或者 local
+		Type     string `yaml:"type"`
+		Bucket   string `yaml:"bucket"`
+		Region   string `yaml:"region"`
+		Endpoint string `yaml:"endpoint"`
+		// local 的时候存放的目录
+		Dir string `yaml:"dir"`
+	}
+	cfg := Config{
+		Type: "local",
+		// 原来写死的就是 weibook，改了之后已经上传的内容就读不到了
+		Bucket: "weibook",
+		Dir:    "./tmp/oss",
+	}
+	err := viper.UnmarshalKey("oss", &cfg)
+	if err != nil {
+		panic(fmt.Errorf("初始化配置失败 %v", err))
+	}
+	switch cfg.Type {
+	case "local":
+		store, err := ossx.NewLocalStore(cfg.Dir)
+		if err != nil {
+			panic(err)
+		}
+		return store
+	case "s3":
+		// 密钥这种东西不要放在配置文件里面
+		appId, ok := os.LookupEnv("OSS_APP_ID")
+		if !ok {
+			panic("没有找到环境变量 OSS_APP_ID")
+		}
+		appSecret, ok := os.LookupEnv("OSS_APP_SECRET")
+		if !ok {
+			panic("没有找到环境变量 OSS_APP_SECRET")
+		}
+		sess, err := session.NewSession(&aws.Config{
+			Credentials: credentials.NewStaticCredentials(appId, appSecret, ""),
+			Region:      ekit.ToPtr[string](cfg.Region),
+			Endpoint:    ekit.ToPtr[string](cfg.Endpoint),
+			// 强制使用 /bucket/key 的形态
+			S3ForcePathStyle: ekit.ToPtr[bool](true),
+		})
+		if err != nil {
+			panic(err)
+		}
+		return ossx.NewS3Store(s3.New(sess), cfg.Bucket)
+	default:
+		panic(fmt.Errorf("未知的对象存储 %s", cfg.Type))
+	}
+}
@@ -2,24 +2,145 @@ package ioc
 
 import (
 	"github.com/aliyun/alibaba-cloud-sdk-go/services/dysmsapi"
+	"github.com/redis/go-redis/v9"
+	"github.com/spf13/viper"
 	"github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common"
 	"github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/profile"
 	tencentSMS "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/sms/v20210111"
+	"github.com/wsqigo/basic-go/webook/internal/job"
+	"github.com/wsqigo/basic-go/webook/internal/repository"
+	"github.com/wsqigo/basic-go/webook/internal/service"
 	"github.com/wsqigo/basic-go/webook/internal/service/sms"
 	"github.com/wsqigo/basic-go/webook/internal/service/sms/aliyun"
+	"github.com/wsqigo/basic-go/webook/internal/service/sms/async"
 	"github.com/wsqigo/basic-go/webook/internal/service/sms/localsms"
+	"github.com/wsqigo/basic-go/webook/internal/service/sms/router"
+	"github.com/wsqigo/basic-go/webook/internal/service/sms/template"
 	"github.com/wsqigo/basic-go/webook/internal/service/sms/tencent"
+	"github.com/wsqigo/basic-go/webook/pkg/limiter"
+	"github.com/wsqigo/basic-go/webook/pkg/logger"
 	"os"
+	"time"
 )
 
-func InitSMSService() sms.Service {
-	return localsms.NewService()
-	// 如果有需要，就可以用这个
-	//return initTencentSMSService()
-	//return initAliyunSMSService()
+func InitSMSService(l logger.LoggerV1) sms.Service {
+	type ProviderConfig struct {
+		// tencent, aliyun 或者 local
+		Name   string `yaml:"name"`
+		Weight int    `yaml:"weight"`
+	}
+	type Config struct {
+		Providers []ProviderConfig     `yaml:"providers"`
+		Breaker   router.BreakerConfig `yaml:"breaker"`
+	}
+	cfg := Config{
+		Breaker: router.DefaultBreakerConfig(),
+	}
+	err := viper.UnmarshalKey("sms.router", &cfg)
+	if err != nil {
+		panic(err)
+	}
+	// 没有配置服务商，就用本地的，只打印验证码
+	if len(cfg.Providers) == 0 {
+		return localsms.NewService()
+	}
+	tpls := initSMSTemplates()
+	providers := make([]router.Provider, 0, len(cfg.Providers))
+	for _, pc := range cfg.Providers {
+		var svc sms.Service
+		switch pc.Name {
+		case tencent.ProviderName:
+			svc = initTencentSMSService(tpls)
+		case aliyun.ProviderName:
+			svc = initAliyunSMSService(tpls)
+		case localsms.ProviderName:
+			svc = localsms.NewService()
+		default:
+			panic("未知的短信服务商 " + pc.Name)
+		}
+		// 没有配置权重的话，路由的时候会跳过，所有短信都发不出去
+		// 不想用的服务商直接从列表里面删掉
+		if pc.Weight <= 0 {
+			pc.Weight = 1
+		}
+		providers = append(providers, router.Provider{
+			Name:   pc.Name,
+			Svc:    svc,
+			Weight: pc.Weight,
+		})
+	}
+	return router.NewRouter(providers, tpls, cfg.Breaker, l)
+}
+
+// initSMSTemplates 逻辑上的模板，以及各个服务商对应的模板 ID
+func initSMSTemplates() *template.Registry {
+	var templates []template.Template
+	err := viper.UnmarshalKey("sms.router.templates", &templates)
+	if err != nil {
+		panic(err)
+	}
+	tpls, err := template.NewRegistry(templates)
+	if err != nil {
+		panic(err)
+	}
+	return tpls
+}
+
+// InitCodeSendGuard 发送验证码之前按照手机号码，IP 和设备限流
+func InitCodeSendGuard(cmd redis.Cmdable) service.CodeSendGuard {
+	type Config struct {
+		PhonePerDay  int `yaml:"phonePerDay"`
+		IPPerHour    int `yaml:"ipPerHour"`
+		DevicePerDay int `yaml:"devicePerDay"`
+		// 不允许发送验证码的号段
+		BlockedPrefixes []string `yaml:"blockedPrefixes"`
+	}
+	cfg := Config{
+		PhonePerDay:  10,
+		IPPerHour:    20,
+		DevicePerDay: 10,
+	}
+	err := viper.UnmarshalKey("sms.guard", &cfg)
+	if err != nil {
+		panic(err)
+	}
+	day := 24 * time.Hour
+	return service.NewCodeSendGuard(
+		limiter.NewRedisSlidingWindowLimiter(cmd, day, cfg.PhonePerDay),
+		limiter.NewRedisSlidingWindowLimiter(cmd, time.Hour, cfg.IPPerHour),
+		limiter.NewRedisSlidingWindowLimiter(cmd, day, cfg.DevicePerDay),
+		// 接入人机校验的厂商之后换掉
+		service.NopHumanVerifier{},
+		cfg.BlockedPrefixes)
+}
+
+// InitAsyncSMSService 服务商不健康的时候转异步，由后台的 goroutine 发送
+func InitAsyncSMSService(repo repository.AsyncSmsRepository, l logger.LoggerV1) *async.Service {
+	cfg := async.DefaultConfig()
+	err := viper.UnmarshalKey("sms.async", &cfg)
+	if err != nil {
+		panic(err)
+	}
+	return async.NewService(InitSMSService(l), repo, cfg, l)
+}
+
+// InitAsyncSmsCleanJob 删除已经结束的异步短信
+func InitAsyncSmsCleanJob(svc *async.Service, l logger.LoggerV1) *job.AsyncSmsCleanJob {
+	type Config struct {
+		// 结束之后保留多久
+		Retention time.Duration `yaml:"retention"`
+	}
+	cfg := Config{
+		Retention: 7 * 24 * time.Hour,
+	}
+	err := viper.UnmarshalKey("sms.async", &cfg)
+	if err != nil {
+		panic(err)
+	}
+	return job.NewAsyncSmsCleanJob(svc, l, cfg.Retention)
 }
 
-func initTencentSMSService() sms.Service {
+func initTencentSMSService(tpls *template.Registry) sms.Service {
 	secretId, ok := os.LookupEnv("SMS_SECRET_ID")
 	if !ok {
 		panic("找不到腾讯 SMS 的 secret id")
@@ -36,10 +157,10 @@ func initTencentSMSService() sms.Service {
 	if err != nil {
 		panic(err)
 	}
-	return tencent.NewService(c, "1400842696", "妙影科技")
+	return tencent.NewService(c, "1400842696", "妙影科技", tpls)
 }
 
-func initAliyunSMSService() sms.Service {
+func initAliyunSMSService(tpls *template.Registry) sms.Service {
 	secretId, ok := os.LookupEnv("SMS_SECRET_ID")
 	if !ok {
 		panic("找不到阿里云 SMS 的 secret id")
@@ -52,5 +173,5 @@ func initAliyunSMSService() sms.Service {
 	if err != nil {
 		panic(err)
 	}
-	return aliyun.NewService(c, "量链科技")
+	return aliyun.NewService(c, "量链科技", tpls)
 }
@@ -0,0 +1,122 @@
+package ioc
+
+import (
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"github.com/spf13/viper"
+	"github.com/wsqigo/basic-go/webook/internal/service/sms"
+	"github.com/wsqigo/basic-go/webook/internal/service/sms/auth"
+	"github.com/wsqigo/basic-go/webook/internal/web"
+	"github.com/wsqigo/basic-go/webook/pkg/logger"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// InternalSMSServer 内部的短信接口，和对外的 Web 服务分开端口，只在内网暴露
+type InternalSMSServer struct {
+	*http.Server
+}
+
+// InitSMSTokenManager 签发和校验内部调用方的 token
+// 内部短信接口是可选的，没有配置密钥或者密钥不对就返回 nil，不启动内部短信接口，
+// 其它功能照常启动
+func InitSMSTokenManager(l logger.LoggerV1) *auth.TokenManager {
+	tokens, err := LoadSMSTokenManager()
+	if err != nil {
+		l.Warn("内部短信接口没有启用", logger.Error(err))
+		return nil
+	}
+	return tokens
+}
+
+// LoadSMSTokenManager 从配置里面读取密钥
+// 密钥不能写在配置文件里面，只配置从哪个环境变量或者文件里面读
+func LoadSMSTokenManager() (*auth.TokenManager, error) {
+	type KeyConfig struct {
+		Id string `yaml:"id"`
+		// 二选一，比如说 k8s 的 secret 挂载成文件
+		SecretEnv  string `yaml:"secretEnv"`
+		SecretFile string `yaml:"secretFile"`
+	}
+	type Config struct {
+		// 签发新 token 用的密钥
+		ActiveKey string      `yaml:"activeKey"`
+		Keys      []KeyConfig `yaml:"keys"`
+	}
+	var cfg Config
+	err := viper.UnmarshalKey("sms.api", &cfg)
+	if err != nil {
+		return nil, err
+	}
+	if len(cfg.Keys) == 0 {
+		return nil, errors.New("没有配置 sms.api.keys")
+	}
+	keys := make([]auth.Key, 0, len(cfg.Keys))
+	for _, kc := range cfg.Keys {
+		var secret string
+		switch {
+		case kc.SecretEnv != "":
+			secret = os.Getenv(kc.SecretEnv)
+		case kc.SecretFile != "":
+			data, err := os.ReadFile(kc.SecretFile)
+			if err != nil {
+				return nil, err
+			}
+			secret = strings.TrimSpace(string(data))
+		}
+		if secret == "" {
+			return nil, fmt.Errorf("密钥 %s 没有设置", kc.Id)
+		}
+		keys = append(keys, auth.Key{Id: kc.Id, Secret: secret})
+	}
+	// 密钥太短也会返回 error
+	return auth.NewTokenManager(keys, cfg.ActiveKey)
+}
+
+// InitSMSAPIService 每个调用方有自己允许使用的模板和每天的配额
+func InitSMSAPIService(svc sms.Service, tokens *auth.TokenManager,
+	cmd redis.Cmdable, l logger.LoggerV1) auth.Service {
+	type CallerConfig struct {
+		Name        string   `yaml:"name"`
+		Templates   []string `yaml:"templates"`
+		QuotaPerDay int      `yaml:"quotaPerDay"`
+	}
+	var cfg []CallerConfig
+	err := viper.UnmarshalKey("sms.api.callers", &cfg)
+	if err != nil {
+		panic(err)
+	}
+	callers := make(map[string]auth.Caller, len(cfg))
+	for _, c := range cfg {
+		callers[c.Name] = auth.Caller{
+			Templates: c.Templates,
+			Quota:     auth.NewRedisDailyQuota(cmd, c.QuotaPerDay),
+		}
+	}
+	return auth.NewSMSService(svc, tokens, initSMSTemplates(), callers, l)
+}
+
+// InitInternalSMSServer 没有启用内部短信接口的时候返回 nil
+func InitInternalSMSServer(tokens *auth.TokenManager, hdl *web.SMSHandler) *InternalSMSServer {
+	if tokens == nil {
+		return nil
+	}
+	type Config struct {
+		Addr string `yaml:"addr"`
+	}
+	cfg := Config{
+		Addr: ":8082",
+	}
+	err := viper.UnmarshalKey("sms.api", &cfg)
+	if err != nil {
+		panic(err)
+	}
+	server := gin.Default()
+	hdl.RegisterRoutes(server)
+	return &InternalSMSServer{
+		Server: &http.Server{Addr: cfg.Addr, Handler: server},
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"github.com/gin-gonic/gin"
 	prometheus2 "github.com/prometheus/client_golang/prometheus"
 	"github.com/redis/go-redis/v9"
+	"github.com/spf13/viper"
 	"github.com/wsqigo/basic-go/webook/internal/web"
 	jwt2 "github.com/wsqigo/basic-go/webook/internal/web/jwt"
 	"github.com/wsqigo/basic-go/webook/internal/web/middleware"
@@ -19,15 +20,49 @@ import (
 func InitWebServer(mdls []gin.HandlerFunc,
 	userHdl *web.UserHandler,
 	artHdl *web.ArticleHandler,
-	dingdingHdl *web.OAuth2DingDingHandler) *gin.Engine {
+	dingdingHdl *web.OAuth2DingDingHandler,
+	commentHdl *web.CommentHandler,
+	collectionHdl *web.CollectionHandler,
+	followHdl *web.FollowHandler,
+	feedHdl *web.FeedHandler,
+	searchHdl *web.SearchHandler,
+	historyHdl *web.HistoryHandler,
+	jobHdl *web.CronJobHandler) *gin.Engine {
 	server := gin.Default()
+	initTrustedProxies(server)
 	server.Use(mdls...)
 	userHdl.RegisterRoutes(server)
 	artHdl.RegisterRoutes(server)
 	dingdingHdl.RegisterRoutes(server)
+	commentHdl.RegisterRoutes(server)
+	collectionHdl.RegisterRoutes(server)
+	followHdl.RegisterRoutes(server)
+	feedHdl.RegisterRoutes(server)
+	searchHdl.RegisterRoutes(server)
+	historyHdl.RegisterRoutes(server)
+	jobHdl.RegisterRoutes(server)
 	return server
 }
 
+// initTrustedProxies 只有经过这些代理的请求，才会从 X-Forwarded-For 里面取客户端 IP。
+// gin 默认信任所有的代理，任何人都能伪造这个头部绕过按照 IP 的限流
+func initTrustedProxies(server *gin.Engine) {
+	type Config struct {
+		// 比如说 ingress 或者 nginx 的地址，支持 CIDR
+		TrustedProxies []string `yaml:"trustedProxies"`
+	}
+	var cfg Config
+	err := viper.UnmarshalKey("web", &cfg)
+	if err != nil {
+		panic(err)
+	}
+	// 没有配置就一个都不信任，ClientIP 就是对端的 IP
+	err = server.SetTrustedProxies(cfg.TrustedProxies)
+	if err != nil {
+		panic(err)
+	}
+}
+
 func InitGinMiddlewares(redisClient redis.Cmdable,
 	hdl jwt2.Handler, l logger.LoggerV1) []gin.HandlerFunc {
 	pb := &prometheus.Builder{
@@ -80,6 +115,7 @@ func InitGinMiddlewares(redisClient redis.Cmdable,
 			IgnorePaths("/oauth2/wechat/authurl").
 			IgnorePaths("/oauth2/wechat/authurl").
 			IgnorePaths("/oauth2/dingding/authurl").
-			IgnorePaths("/oauth2/dingding/callback").CheckLogin(),
+			IgnorePaths("/oauth2/dingding/callback").
+			IgnorePaths("/search").CheckLogin(),
 	}
 }
@@ -38,17 +38,85 @@ func main() {
 		}
 	}
 	app.cron.Start()
-	defer func() {
-		// 等待定时任务退出
-		<-app.cron.Stop().Done()
+	app.asyncSms.Start()
+	scheduleCtx, cancelSchedule := context.WithCancel(context.Background())
+	scheduleDone := make(chan struct{})
+	// 先上报负载，调度和热榜都要依赖负载判断
+	go app.balancer.Start(scheduleCtx)
+	go func() {
+		defer close(scheduleDone)
+		err := app.scheduler.Schedule(scheduleCtx)
+		if err != nil && err != context.Canceled {
+			zap.L().Error("任务调度退出", zap.Error(err))
+		}
 	}()
-	server := app.server
-	server.GET("/hello", func(ctx *gin.Context) {
+	app.server.GET("/hello", func(ctx *gin.Context) {
 		ctx.String(http.StatusOK, "hello，启动成功了")
 	})
 	// 作业：改成 8081
-	//server.Run(":8081")
-	server.Run(":8080")
+	server := &http.Server{Addr: ":8080", Handler: app.server}
+	go func() {
+		err := server.ListenAndServe()
+		if err != nil && err != http.ErrServerClosed {
+			panic(err)
+		}
+	}()
+	// 没有配置密钥的时候不启动内部短信接口
+	if app.smsServer != nil {
+		go func() {
+			err := app.smsServer.ListenAndServe()
+			if err != nil && err != http.ErrServerClosed {
+				panic(err)
+			}
+		}()
+	}
+	registerStopHooks(app, server, cancelSchedule, scheduleDone)
+	app.lifecycle.WaitForSignal()
+}
+
+// registerStopHooks 先停掉入口，不再接收新的请求和任务，再关闭消费者，最后释放分布式锁
+func registerStopHooks(app *App, server *http.Server,
+	cancelSchedule context.CancelFunc, scheduleDone <-chan struct{}) {
+	lc := app.lifecycle
+	// 不再接收新的请求，等正在处理的请求结束
+	lc.OnStop("web", server.Shutdown)
+	if app.smsServer != nil {
+		lc.OnStop("sms_api", app.smsServer.Shutdown)
+	}
+	lc.OnStop("producer", func(ctx context.Context) error {
+		// 把还没发出去的消息发完
+		return app.asyncProducer.Close()
+	})
+	lc.OnStop("cron", func(ctx context.Context) error {
+		// 等待正在运行的定时任务退出
+		<-app.cron.Stop().Done()
+		return nil
+	})
+	lc.OnStop("scheduler", func(ctx context.Context) error {
+		cancelSchedule()
+		<-scheduleDone
+		return nil
+	})
+	lc.OnStop("async_sms", func(ctx context.Context) error {
+		// 等正在发送的短信发送完
+		return app.asyncSms.Close()
+	})
+	lc.OnStop("consumers", func(ctx context.Context) error {
+		var err error
+		for _, c := range app.consumers {
+			// 一个关闭失败了，其它的也要继续关闭
+			if er := c.Close(); er != nil {
+				err = er
+			}
+		}
+		return err
+	})
+	lc.OnStop("ranking_lock", func(ctx context.Context) error {
+		return app.rankingJob.Close()
+	})
+	lc.OnStop("outbox_lock", func(ctx context.Context) error {
+		return app.outboxJob.Close()
+	})
 }
 
 func initPrometheus() {
@@ -0,0 +1,75 @@
+package ossx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// LocalStore 用本地文件来模拟对象存储，一个对象一个文件
+// 主要是测试和本地开发的时候用
+type LocalStore struct {
+	dir string
+}
+
+func NewLocalStore(dir string) (*LocalStore, error) {
+	err := os.MkdirAll(dir, 0o755)
+	if err != nil {
+		return nil, err
+	}
+	return &LocalStore{dir: dir}, nil
+}
+
+func (l *LocalStore) Put(ctx context.Context, key string, data []byte) error {
+	path, err := l.path(key)
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(filepath.Dir(path), 0o755)
+	if err != nil {
+		return err
+	}
+	// 先写临时文件再改名，避免读到写了一半的内容
+	tmp := path + ".tmp"
+	err = os.WriteFile(tmp, data, 0o644)
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+func (l *LocalStore) Get(ctx context.Context, key string) ([]byte, error) {
+	path, err := l.path(key)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrObjectNotFound
+	}
+	return data, err
+}
+
+func (l *LocalStore) Delete(ctx context.Context, key string) error {
+	path, err := l.path(key)
+	if err != nil {
+		return err
+	}
+	err = os.Remove(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// path 防止 key 里面带了 ../ 跑到目录外面去
+func (l *LocalStore) path(key string) (string, error) {
+	path := filepath.Join(l.dir, filepath.FromSlash(key))
+	if !strings.HasPrefix(path, filepath.Clean(l.dir)+string(filepath.Separator)) {
+		return "", fmt.Errorf("非法的 key %s", key)
+	}
+	return path, nil
+}
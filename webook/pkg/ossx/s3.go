@@ -0,0 +1,60 @@
+package ossx
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/ecodeclub/ekit"
+	"io"
+)
+
+// S3Store 兼容 S3 协议的对象存储，阿里云 OSS、腾讯云 COS 都可以用
+type S3Store struct {
+	client      *s3.S3
+	bucket      string
+	contentType string
+}
+
+func NewS3Store(client *s3.S3, bucket string) *S3Store {
+	return &S3Store{
+		client:      client,
+		bucket:      bucket,
+		contentType: "text-plain;charset=utf-8",
+	}
+}
+
+func (s *S3Store) Put(ctx context.Context, key string, data []byte) error {
+	_, err := s.client.PutObjectWithContext(ctx, &s3.PutObjectInput{
+		Bucket:      ekit.ToPtr[string](s.bucket),
+		Key:         ekit.ToPtr[string](key),
+		Body:        bytes.NewReader(data),
+		ContentType: ekit.ToPtr[string](s.contentType),
+	})
+	return err
+}
+
+func (s *S3Store) Get(ctx context.Context, key string) ([]byte, error) {
+	res, err := s.client.GetObjectWithContext(ctx, &s3.GetObjectInput{
+		Bucket: ekit.ToPtr[string](s.bucket),
+		Key:    ekit.ToPtr[string](key),
+	})
+	if err != nil {
+		var ae awserr.Error
+		if errors.As(err, &ae) && ae.Code() == s3.ErrCodeNoSuchKey {
+			return nil, ErrObjectNotFound
+		}
+		return nil, err
+	}
+	defer res.Body.Close()
+	return io.ReadAll(res.Body)
+}
+
+func (s *S3Store) Delete(ctx context.Context, key string) error {
+	_, err := s.client.DeleteObjectWithContext(ctx, &s3.DeleteObjectInput{
+		Bucket: ekit.ToPtr[string](s.bucket),
+		Key:    ekit.ToPtr[string](key),
+	})
+	return err
+}
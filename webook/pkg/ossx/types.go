@@ -0,0 +1,19 @@
+package ossx
+
+import (
+	"context"
+	"errors"
+)
+
+var ErrObjectNotFound = errors.New("对象不存在")
+
+// Store 对象存储的抽象，屏蔽 S3、OSS、本地文件之间的区别
+//
+//go:generate mockgen -destination=./mocks/store.mock.go -package=ossxmocks -source=./types.go Store
+type Store interface {
+	Put(ctx context.Context, key string, data []byte) error
+	// Get 对象不存在的时候返回 ErrObjectNotFound
+	Get(ctx context.Context, key string) ([]byte, error)
+	// Delete 对象不存在也不会返回 error
+	Delete(ctx context.Context, key string) error
+}
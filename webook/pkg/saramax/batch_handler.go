@@ -4,17 +4,96 @@ import (
 	"context"
 	"encoding/json"
 	"github.com/IBM/sarama"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/wsqigo/basic-go/webook/pkg/logger"
+	"strconv"
 	"time"
 )
 
+// BatchConfig 控制怎么凑一批，以及同一个分区最多同时处理多少批
+type BatchConfig struct {
+	// 一批最多多少条消息
+	BatchSize int `yaml:"batchSize"`
+	// 从收到一批里面的第一条消息开始，最多等多久
+	MaxWait time.Duration `yaml:"maxWait"`
+	// 一批消息体加起来最多多少字节，0 就是不限制
+	MaxBytes int `yaml:"maxBytes"`
+	// 同一个分区最多同时处理多少批，偏移量依旧按照顺序提交
+	Concurrency int `yaml:"concurrency"`
+}
+
+func DefaultBatchConfig() BatchConfig {
+	return BatchConfig{
+		BatchSize:   10,
+		MaxWait:     time.Second,
+		Concurrency: 1,
+	}
+}
+
 type BatchHandler[T any] struct {
-	fn func(msgs []*sarama.ConsumerMessage, ts []T) error
-	l  logger.LoggerV1
+	fn    func(msgs []*sarama.ConsumerMessage, ts []T) error
+	l     logger.LoggerV1
+	retry RetryConfig
+	cfg   BatchConfig
+
+	// 每一批有多少条消息
+	sizeVector *prometheus.SummaryVec
+	// 处理一批花了多少毫秒
+	durationVector *prometheus.SummaryVec
 }
 
 func NewBatchHandler[T any](l logger.LoggerV1, fn func(msgs []*sarama.ConsumerMessage, ts []T) error) *BatchHandler[T] {
-	return &BatchHandler[T]{fn: fn, l: l}
+	return &BatchHandler[T]{
+		fn:  fn,
+		l:   l,
+		cfg: DefaultBatchConfig(),
+		sizeVector: newBatchSummary("kafka_batch_size",
+			"批量消费的时候每一批的消息数量"),
+		durationVector: newBatchSummary("kafka_batch_duration",
+			"批量消费的时候处理一批消息的耗时，单位毫秒"),
+	}
+}
+
+// WithRetry 整批处理失败之后，先整批在本地重试，还是失败就逐条转发到重试 topic
+func (b *BatchHandler[T]) WithRetry(cfg RetryConfig) *BatchHandler[T] {
+	b.retry = cfg
+	return b
+}
+
+// WithBatchConfig 没有设置的字段用默认值
+func (b *BatchHandler[T]) WithBatchConfig(cfg BatchConfig) *BatchHandler[T] {
+	def := DefaultBatchConfig()
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = def.BatchSize
+	}
+	if cfg.MaxWait <= 0 {
+		cfg.MaxWait = def.MaxWait
+	}
+	if cfg.Concurrency <= 0 {
+		cfg.Concurrency = def.Concurrency
+	}
+	b.cfg = cfg
+	return b
+}
+
+func newBatchSummary(name, help string) *prometheus.SummaryVec {
+	vector := prometheus.NewSummaryVec(prometheus.SummaryOpts{
+		Namespace: "geekbang_daming",
+		Subsystem: "webook",
+		Name:      name,
+		Help:      help,
+		Objectives: map[float64]float64{
+			0.5:  0.01,
+			0.9:  0.01,
+			0.99: 0.001,
+		},
+	}, []string{"topic", "success"})
+	// 多个 BatchHandler 共用同一个指标
+	err := prometheus.Register(vector)
+	if are, ok := err.(prometheus.AlreadyRegisteredError); ok {
+		return are.ExistingCollector.(*prometheus.SummaryVec)
+	}
+	return vector
 }
 
 func (b *BatchHandler[T]) Setup(session sarama.ConsumerGroupSession) error {
@@ -25,48 +104,168 @@ func (b *BatchHandler[T]) Cleanup(session sarama.ConsumerGroupSession) error {
 	return nil
 }
 
+// pendingBatch 已经凑好，正在处理的一批消息
+type pendingBatch[T any] struct {
+	// 这一批所有的消息，处理完之后按照顺序提交
+	all []*sarama.ConsumerMessage
+	// 反序列化成功的消息
+	msgs []*sarama.ConsumerMessage
+	ts   []T
+	// 反序列化失败的消息
+	bad    []*sarama.ConsumerMessage
+	badErr []error
+	// 处理结果，非 nil 说明失败的消息也没能转发出去，不能提交
+	done chan error
+}
+
 func (b *BatchHandler[T]) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+	ctx := session.Context()
 	msgs := claim.Messages()
-	const batchSize = 10
-	for {
-		batch := make([]*sarama.ConsumerMessage, 0, batchSize)
-		ts := make([]T, 0, batchSize)
-		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
-		var done = false
-		for i := 0; i < batchSize && !done; i++ {
-			select {
-			case <-ctx.Done():
-				// 超时了
-				done = true
-			case msg, ok := <-msgs:
-				if !ok {
-					cancel()
-					return nil
+	// 按照凑批的顺序排队，提交的时候也按照这个顺序
+	pending := make(chan *pendingBatch[T], b.cfg.Concurrency)
+	// 控制同时处理的批次
+	sem := make(chan struct{}, b.cfg.Concurrency)
+	// 提交失败了，通知凑批的 goroutine 退出
+	stop := make(chan struct{})
+	go func() {
+		defer close(pending)
+		for {
+			pb, ok := b.collect(ctx, msgs)
+			if len(pb.all) > 0 {
+				select {
+				case sem <- struct{}{}:
+				case <-stop:
+					return
 				}
-				var t T
-				err := json.Unmarshal(msg.Value, &t)
-				if err != nil {
-					b.l.Error("反序列消息体失败",
-						logger.String("topic", msg.Topic),
-						logger.Int32("partition", msg.Partition),
-						logger.Int64("offset", msg.Offset),
-						logger.Error(err))
-					continue
+				go func() {
+					pb.done <- b.process(ctx, pb)
+					<-sem
+				}()
+				select {
+				case pending <- pb:
+				case <-stop:
+					return
 				}
-				batch = append(batch, msg)
-				ts = append(ts, t)
+			}
+			if !ok {
+				return
 			}
 		}
-		cancel()
-		// 凑够了一批，然后你就处理
-		err := b.fn(batch, ts)
+	}()
+
+	for pb := range pending {
+		err := <-pb.done
 		if err != nil {
-			b.l.Error("处理消息失败",
-				// 把真个 msgs 都记录下来
-				logger.Error(err))
+			// 后面的批次即便处理成功了也不能提交，不然会跳过这一批
+			close(stop)
+			return err
 		}
-		for _, msg := range batch {
+		for _, msg := range pb.all {
 			session.MarkMessage(msg, "")
 		}
 	}
+	return nil
+}
+
+// collect 凑一批，返回 false 说明不会再有消息了
+func (b *BatchHandler[T]) collect(ctx context.Context,
+	msgs <-chan *sarama.ConsumerMessage) (*pendingBatch[T], bool) {
+	pb := &pendingBatch[T]{done: make(chan error, 1)}
+	// 收到第一条消息才开始计时，避免没有消息的时候空转
+	var timeout <-chan time.Time
+	bytes := 0
+	for len(pb.all) < b.cfg.BatchSize {
+		select {
+		case <-ctx.Done():
+			return pb, false
+		case <-timeout:
+			return pb, true
+		case msg, ok := <-msgs:
+			if !ok {
+				return pb, false
+			}
+			if !waitRetryAt(ctx, msg) {
+				// 要退出了，这条消息留给下一次消费
+				return pb, false
+			}
+			if timeout == nil {
+				timer := time.NewTimer(b.cfg.MaxWait)
+				defer timer.Stop()
+				timeout = timer.C
+			}
+			pb.all = append(pb.all, msg)
+			var t T
+			err := json.Unmarshal(msg.Value, &t)
+			if err != nil {
+				b.l.Error("反序列消息体失败",
+					logger.String("topic", msg.Topic),
+					logger.Int32("partition", msg.Partition),
+					logger.Int64("offset", msg.Offset),
+					logger.Error(err))
+				pb.bad = append(pb.bad, msg)
+				pb.badErr = append(pb.badErr, err)
+				continue
+			}
+			pb.msgs = append(pb.msgs, msg)
+			pb.ts = append(pb.ts, t)
+			bytes += len(msg.Value)
+			if b.cfg.MaxBytes > 0 && bytes >= b.cfg.MaxBytes {
+				return pb, true
+			}
+		}
+	}
+	return pb, true
+}
+
+func (b *BatchHandler[T]) process(ctx context.Context, pb *pendingBatch[T]) error {
+	// 消息本身有问题，重试也没用，直接进死信
+	for i, msg := range pb.bad {
+		if b.retry.Failure == nil {
+			break
+		}
+		err := b.retry.Failure.DeadLetter(msg, pb.badErr[i])
+		if err != nil {
+			b.l.Error("转发到死信失败",
+				logger.String("topic", msg.Topic),
+				logger.Int64("offset", msg.Offset),
+				logger.Error(err))
+			return err
+		}
+	}
+	if len(pb.msgs) == 0 {
+		return nil
+	}
+	start := time.Now()
+	err := b.retry.run(ctx, func() error {
+		return b.fn(pb.msgs, pb.ts)
+	})
+	topic := pb.msgs[0].Topic
+	success := strconv.FormatBool(err == nil)
+	b.sizeVector.WithLabelValues(topic, success).Observe(float64(len(pb.msgs)))
+	b.durationVector.WithLabelValues(topic, success).
+		Observe(float64(time.Since(start).Milliseconds()))
+	if err == nil {
+		return nil
+	}
+	b.l.Error("处理消息失败",
+		logger.String("topic", topic),
+		logger.Int32("partition", pb.msgs[0].Partition),
+		logger.Int64("offset", pb.msgs[0].Offset),
+		logger.Int("cnt", len(pb.msgs)),
+		logger.Error(err))
+	if b.retry.Failure == nil {
+		return nil
+	}
+	for _, msg := range pb.msgs {
+		er := b.retry.Failure.Handle(msg, err)
+		if er != nil {
+			// 转发失败了，整批都不提交，重新消费的时候会有重复，业务要能接受
+			b.l.Error("转发处理失败的消息失败",
+				logger.String("topic", msg.Topic),
+				logger.Int64("offset", msg.Offset),
+				logger.Error(er))
+			return er
+		}
+	}
+	return nil
 }
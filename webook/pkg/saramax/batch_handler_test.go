@@ -0,0 +1,120 @@
+package saramax
+
+import (
+	"context"
+	"github.com/IBM/sarama"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wsqigo/basic-go/webook/pkg/logger"
+	"sync"
+	"testing"
+	"time"
+)
+
+type testEvent struct {
+	Id int64 `json:"id"`
+}
+
+func TestBatchHandler_ConsumeClaim(t *testing.T) {
+	testCases := []struct {
+		name string
+		cfg  BatchConfig
+		// 消息体
+		values []string
+
+		wantBatches [][]int64
+		wantMarked  []int64
+	}{
+		{
+			name: "凑够数量就处理，并发处理也按照顺序提交",
+			cfg: BatchConfig{
+				BatchSize:   2,
+				MaxWait:     time.Second,
+				Concurrency: 3,
+			},
+			values:      []string{`{"id":1}`, `{"id":2}`, `{"id":3}`, `{"id":4}`, `{"id":5}`},
+			wantBatches: [][]int64{{1, 2}, {3, 4}, {5}},
+			wantMarked:  []int64{0, 1, 2, 3, 4},
+		},
+		{
+			name: "超过字节数就处理",
+			cfg: BatchConfig{
+				BatchSize: 10,
+				MaxWait:   time.Second,
+				MaxBytes:  16,
+			},
+			values:      []string{`{"id":1}`, `{"id":2}`, `{"id":3}`},
+			wantBatches: [][]int64{{1, 2}, {3}},
+			wantMarked:  []int64{0, 1, 2},
+		},
+		{
+			name: "反序列化失败的消息也要提交",
+			cfg: BatchConfig{
+				BatchSize: 10,
+				MaxWait:   time.Second,
+			},
+			values:      []string{`{"id":1}`, `abc`, `{"id":3}`},
+			wantBatches: [][]int64{{1, 3}},
+			wantMarked:  []int64{0, 1, 2},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var lock sync.Mutex
+			var batches [][]int64
+			h := NewBatchHandler[testEvent](logger.NewNopLogger(),
+				func(msgs []*sarama.ConsumerMessage, ts []testEvent) error {
+					ids := make([]int64, 0, len(ts))
+					for _, evt := range ts {
+						ids = append(ids, evt.Id)
+					}
+					// 第一批最慢，后面的批次要等它处理完才能提交
+					if ids[0] == 1 {
+						time.Sleep(50 * time.Millisecond)
+					}
+					lock.Lock()
+					batches = append(batches, ids)
+					lock.Unlock()
+					return nil
+				}).WithBatchConfig(tc.cfg)
+			msgs := make(chan *sarama.ConsumerMessage, len(tc.values))
+			for i, val := range tc.values {
+				msgs <- &sarama.ConsumerMessage{
+					Topic:  "test_topic",
+					Offset: int64(i),
+					Value:  []byte(val),
+				}
+			}
+			close(msgs)
+			session := &fakeSession{ctx: context.Background()}
+			err := h.ConsumeClaim(session, &fakeClaim{msgs: msgs})
+			require.NoError(t, err)
+			assert.ElementsMatch(t, tc.wantBatches, batches)
+			assert.Equal(t, tc.wantMarked, session.marked)
+		})
+	}
+}
+
+type fakeSession struct {
+	sarama.ConsumerGroupSession
+	ctx    context.Context
+	marked []int64
+}
+
+func (f *fakeSession) Context() context.Context {
+	return f.ctx
+}
+
+func (f *fakeSession) MarkMessage(msg *sarama.ConsumerMessage, metadata string) {
+	f.marked = append(f.marked, msg.Offset)
+}
+
+type fakeClaim struct {
+	sarama.ConsumerGroupClaim
+	msgs chan *sarama.ConsumerMessage
+}
+
+func (f *fakeClaim) Messages() <-chan *sarama.ConsumerMessage {
+	return f.msgs
+}
@@ -0,0 +1,66 @@
+package saramax
+
+import (
+	"context"
+	"errors"
+	"github.com/IBM/sarama"
+	"github.com/wsqigo/basic-go/webook/pkg/logger"
+	"time"
+)
+
+// ConsumerGroup 在后台持续消费，直到调用 Close
+// rebalance 的时候 Consume 会返回，所以要在循环里面重新调用
+type ConsumerGroup struct {
+	cg      sarama.ConsumerGroup
+	topics  []string
+	handler sarama.ConsumerGroupHandler
+	l       logger.LoggerV1
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func StartConsumerGroup(client sarama.Client, groupId string, topics []string,
+	handler sarama.ConsumerGroupHandler, l logger.LoggerV1) (*ConsumerGroup, error) {
+	cg, err := sarama.NewConsumerGroupFromClient(groupId, client)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	c := &ConsumerGroup{
+		cg:      cg,
+		topics:  topics,
+		handler: handler,
+		l:       l,
+		cancel:  cancel,
+		done:    make(chan struct{}),
+	}
+	go c.consume(ctx)
+	return c, nil
+}
+
+func (c *ConsumerGroup) consume(ctx context.Context) {
+	defer close(c.done)
+	for ctx.Err() == nil {
+		err := c.cg.Consume(ctx, c.topics, c.handler)
+		if errors.Is(err, sarama.ErrClosedConsumerGroup) {
+			return
+		}
+		if err != nil {
+			c.l.Error("消费出错，稍后重试", logger.Error(err))
+			// 避免 Kafka 出问题的时候一直空转
+			select {
+			case <-ctx.Done():
+			case <-time.After(time.Second):
+			}
+		}
+	}
+}
+
+// Close 取消消费，等正在处理的消息处理完，然后关闭消费者组
+// 关闭的时候会提交已经标记的偏移量
+func (c *ConsumerGroup) Close() error {
+	c.cancel()
+	<-c.done
+	return c.cg.Close()
+}
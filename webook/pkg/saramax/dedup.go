@@ -0,0 +1,147 @@
+package saramax
+
+import (
+	"context"
+	"fmt"
+	"github.com/IBM/sarama"
+	"github.com/redis/go-redis/v9"
+	"time"
+)
+
+// HeaderIdempotencyKey 生产者重复发送同一个事件的时候不变，比如说 outbox 里面事件的 ID
+const HeaderIdempotencyKey = "x-idempotency-key"
+
+// Deduper 记录处理过的消息
+// 处理成功之后才记下来，处理到一半节点挂了的话，重新投递过来的消息还会再处理
+type Deduper interface {
+	// Processed 返回每个 key 是不是已经处理过了
+	Processed(ctx context.Context, keys []string) ([]bool, error)
+	// Mark 处理成功之后记下来
+	Mark(ctx context.Context, keys []string) error
+}
+
+// MessageKey 有幂等键就用幂等键，不然就用 topic，分区和偏移量
+// 偏移量只能防住 rebalance 之类的重复消费，防不住生产者重复发送
+func MessageKey(msg *sarama.ConsumerMessage) string {
+	if key, ok := header(msg, HeaderIdempotencyKey); ok {
+		return fmt.Sprintf("%s:key:%s", OriginTopic(msg), key)
+	}
+	return fmt.Sprintf("%s:%d:%d", msg.Topic, msg.Partition, msg.Offset)
+}
+
+// DedupBatch 包装 BatchHandler 的处理函数，跳过已经处理过的消息
+// Deduper 出错的时候整批返回错误，交给重试机制处理
+func DedupBatch[T any](d Deduper, timeout time.Duration,
+	fn func(msgs []*sarama.ConsumerMessage, ts []T) error) func(msgs []*sarama.ConsumerMessage, ts []T) error {
+	return func(msgs []*sarama.ConsumerMessage, ts []T) error {
+		keys := make([]string, 0, len(msgs))
+		for _, msg := range msgs {
+			keys = append(keys, MessageKey(msg))
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		processed, err := d.Processed(ctx, keys)
+		cancel()
+		if err != nil {
+			return err
+		}
+		newMsgs := make([]*sarama.ConsumerMessage, 0, len(msgs))
+		newTs := make([]T, 0, len(ts))
+		newKeys := make([]string, 0, len(keys))
+		// 生产者重复发送的消息可能在同一批里面
+		seen := make(map[string]struct{}, len(keys))
+		for i, ok := range processed {
+			if _, dup := seen[keys[i]]; ok || dup {
+				continue
+			}
+			seen[keys[i]] = struct{}{}
+			newMsgs = append(newMsgs, msgs[i])
+			newTs = append(newTs, ts[i])
+			newKeys = append(newKeys, keys[i])
+		}
+		if len(newMsgs) == 0 {
+			return nil
+		}
+		err = fn(newMsgs, newTs)
+		if err != nil {
+			return err
+		}
+		mark(d, timeout, newKeys)
+		return nil
+	}
+}
+
+// Dedup 包装 Handler 的处理函数，跳过已经处理过的消息
+func Dedup[T any](d Deduper, timeout time.Duration,
+	fn func(msg *sarama.ConsumerMessage, t T) error) func(msg *sarama.ConsumerMessage, t T) error {
+	return func(msg *sarama.ConsumerMessage, t T) error {
+		keys := []string{MessageKey(msg)}
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		processed, err := d.Processed(ctx, keys)
+		cancel()
+		if err != nil {
+			return err
+		}
+		if processed[0] {
+			return nil
+		}
+		err = fn(msg, t)
+		if err != nil {
+			return err
+		}
+		mark(d, timeout, keys)
+		return nil
+	}
+}
+
+// mark 记录失败了也不能返回 error，不然已经处理成功的消息会被重试，
+// 最坏的情况是这些消息再次投递过来的时候会被重复处理
+func mark(d Deduper, timeout time.Duration, keys []string) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	_ = d.Mark(ctx, keys)
+}
+
+// RedisDeduper 过期时间要比消息可能重复投递的时间窗口长
+type RedisDeduper struct {
+	client redis.Cmdable
+	// 区分不同的消费者组，同一条消息不同的消费者组都要处理
+	prefix     string
+	expiration time.Duration
+}
+
+func NewRedisDeduper(client redis.Cmdable, prefix string, expiration time.Duration) *RedisDeduper {
+	return &RedisDeduper{client: client, prefix: prefix, expiration: expiration}
+}
+
+func (r *RedisDeduper) Processed(ctx context.Context, keys []string) ([]bool, error) {
+	pipe := r.client.Pipeline()
+	cmds := make([]*redis.IntCmd, 0, len(keys))
+	for _, key := range keys {
+		cmds = append(cmds, pipe.Exists(ctx, r.key(key)))
+	}
+	_, err := pipe.Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]bool, 0, len(cmds))
+	for _, cmd := range cmds {
+		res = append(res, cmd.Val() > 0)
+	}
+	return res, nil
+}
+
+func (r *RedisDeduper) Mark(ctx context.Context, keys []string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	pipe := r.client.Pipeline()
+	for _, key := range keys {
+		pipe.Set(ctx, r.key(key), 1, r.expiration)
+	}
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func (r *RedisDeduper) key(key string) string {
+	return fmt.Sprintf("kafka_dedup:%s:%s", r.prefix, key)
+}
@@ -0,0 +1,216 @@
+package saramax
+
+import (
+	"context"
+	"errors"
+	"github.com/IBM/sarama"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wsqigo/basic-go/webook/pkg/logger"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestDedupBatch_Replay(t *testing.T) {
+	testCases := []struct {
+		name string
+		// 第一次处理的时候返回的错误
+		firstErr error
+
+		// 第一次和重放的时候分别处理了哪些
+		wantFirst  []int64
+		wantReplay []int64
+	}{
+		{
+			name:      "处理成功之后重放，全部跳过",
+			wantFirst: []int64{1, 2, 3},
+		},
+		{
+			name:       "处理失败之后重放，重新处理",
+			firstErr:   errors.New("mock db error"),
+			wantFirst:  []int64{1, 2, 3},
+			wantReplay: []int64{1, 2, 3},
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			d := newMemDeduper()
+			var processed []int64
+			fnErr := tc.firstErr
+			h := NewBatchHandler[testEvent](logger.NewNopLogger(),
+				DedupBatch(d, time.Second, func(msgs []*sarama.ConsumerMessage, ts []testEvent) error {
+					for _, evt := range ts {
+						processed = append(processed, evt.Id)
+					}
+					return fnErr
+				})).WithBatchConfig(BatchConfig{BatchSize: 10, MaxWait: time.Second})
+
+			consume(t, h)
+			assert.Equal(t, tc.wantFirst, processed)
+
+			// 模拟 rebalance 之后偏移量没提交上，同一批消息又来了一次
+			processed = nil
+			fnErr = nil
+			consume(t, h)
+			assert.Equal(t, tc.wantReplay, processed)
+		})
+	}
+}
+
+func TestDedupBatch_PartialReplay(t *testing.T) {
+	d := newMemDeduper()
+	err := d.Mark(context.Background(), []string{"test_topic:0:1"})
+	require.NoError(t, err)
+	var processed []int64
+	fn := DedupBatch(d, time.Second, func(msgs []*sarama.ConsumerMessage, ts []testEvent) error {
+		for _, evt := range ts {
+			processed = append(processed, evt.Id)
+		}
+		return nil
+	})
+	err = fn([]*sarama.ConsumerMessage{
+		{Topic: "test_topic", Offset: 0},
+		{Topic: "test_topic", Offset: 1},
+		{Topic: "test_topic", Offset: 2},
+	}, []testEvent{{Id: 1}, {Id: 2}, {Id: 3}})
+	require.NoError(t, err)
+	// 只跳过处理过的那一条
+	assert.Equal(t, []int64{1, 3}, processed)
+}
+
+func TestDedupBatch_DuplicateInBatch(t *testing.T) {
+	d := newMemDeduper()
+	var processed []int64
+	fn := DedupBatch(d, time.Second, func(msgs []*sarama.ConsumerMessage, ts []testEvent) error {
+		for _, evt := range ts {
+			processed = append(processed, evt.Id)
+		}
+		return nil
+	})
+	header := []*sarama.RecordHeader{{Key: []byte(HeaderIdempotencyKey), Value: []byte("outbox_1")}}
+	err := fn([]*sarama.ConsumerMessage{
+		{Topic: "test_topic", Offset: 0, Headers: header},
+		{Topic: "test_topic", Offset: 1, Headers: header},
+	}, []testEvent{{Id: 1}, {Id: 1}})
+	require.NoError(t, err)
+	// 生产者重复发送的，同一批里面也只处理一次
+	assert.Equal(t, []int64{1}, processed)
+}
+
+func TestDedup_Crash(t *testing.T) {
+	d := newMemDeduper()
+	msg := &sarama.ConsumerMessage{Topic: "test_topic", Offset: 1}
+	cnt := 0
+	fn := Dedup(d, time.Second, func(msg *sarama.ConsumerMessage, evt testEvent) error {
+		cnt++
+		return nil
+	})
+	// 上一个节点已经查过了，还没处理完就挂了，不能留下处理过的标记
+	processed, err := d.Processed(context.Background(), []string{MessageKey(msg)})
+	require.NoError(t, err)
+	assert.Equal(t, []bool{false}, processed)
+	// rebalance 之后重新投递过来，要处理
+	require.NoError(t, fn(msg, testEvent{Id: 1}))
+	// 处理成功之后再来就跳过
+	require.NoError(t, fn(msg, testEvent{Id: 1}))
+	assert.Equal(t, 1, cnt)
+}
+
+func TestDedup_DeduperError(t *testing.T) {
+	d := newMemDeduper()
+	d.err = errors.New("mock redis error")
+	called := false
+	fn := Dedup(d, time.Second, func(msg *sarama.ConsumerMessage, evt testEvent) error {
+		called = true
+		return nil
+	})
+	err := fn(&sarama.ConsumerMessage{Topic: "test_topic"}, testEvent{Id: 1})
+	// 判断不了是不是处理过，交给重试
+	assert.Equal(t, d.err, err)
+	assert.False(t, called)
+}
+
+func TestMessageKey(t *testing.T) {
+	testCases := []struct {
+		name string
+		msg  *sarama.ConsumerMessage
+		want string
+	}{
+		{
+			name: "没有幂等键",
+			msg:  &sarama.ConsumerMessage{Topic: "test_topic", Partition: 1, Offset: 2},
+			want: "test_topic:1:2",
+		},
+		{
+			name: "幂等键",
+			msg: &sarama.ConsumerMessage{Topic: "test_topic", Partition: 1, Offset: 2,
+				Headers: []*sarama.RecordHeader{
+					{Key: []byte(HeaderIdempotencyKey), Value: []byte("outbox_1")},
+				}},
+			want: "test_topic:key:outbox_1",
+		},
+		{
+			name: "重试 topic 里面的幂等键算在原本的 topic 上",
+			msg: &sarama.ConsumerMessage{Topic: "test_topic.test_group.retry", Partition: 0, Offset: 5,
+				Headers: []*sarama.RecordHeader{
+					{Key: []byte(HeaderIdempotencyKey), Value: []byte("outbox_1")},
+					{Key: []byte(HeaderOriginTopic), Value: []byte("test_topic")},
+				}},
+			want: "test_topic:key:outbox_1",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, MessageKey(tc.msg))
+		})
+	}
+}
+
+func consume(t *testing.T, h *BatchHandler[testEvent]) {
+	msgs := make(chan *sarama.ConsumerMessage, 3)
+	for i, val := range []string{`{"id":1}`, `{"id":2}`, `{"id":3}`} {
+		msgs <- &sarama.ConsumerMessage{
+			Topic:  "test_topic",
+			Offset: int64(i),
+			Value:  []byte(val),
+		}
+	}
+	close(msgs)
+	err := h.ConsumeClaim(&fakeSession{ctx: context.Background()}, &fakeClaim{msgs: msgs})
+	require.NoError(t, err)
+}
+
+type memDeduper struct {
+	lock sync.Mutex
+	keys map[string]struct{}
+	err  error
+}
+
+func newMemDeduper() *memDeduper {
+	return &memDeduper{keys: map[string]struct{}{}}
+}
+
+func (m *memDeduper) Processed(ctx context.Context, keys []string) ([]bool, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if m.err != nil {
+		return nil, m.err
+	}
+	res := make([]bool, 0, len(keys))
+	for _, key := range keys {
+		_, ok := m.keys[key]
+		res = append(res, ok)
+	}
+	return res, nil
+}
+
+func (m *memDeduper) Mark(ctx context.Context, keys []string) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	for _, key := range keys {
+		m.keys[key] = struct{}{}
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package saramax
+
+import (
+	"context"
+	"github.com/IBM/sarama"
+	"github.com/wsqigo/basic-go/webook/pkg/logger"
+	"sync/atomic"
+)
+
+// DLQReplayer 把某个消费者组的死信重新投递到这个组的重试 topic，
+// 只有这个组会再处理一遍，其它订阅了原本 topic 的组不受影响。
+// 用固定的消费者组记录进度，已经重放过的消息不会再重放
+type DLQReplayer struct {
+	addrs    []string
+	producer sarama.SyncProducer
+	l        logger.LoggerV1
+}
+
+func NewDLQReplayer(addrs []string, producer sarama.SyncProducer, l logger.LoggerV1) *DLQReplayer {
+	return &DLQReplayer{addrs: addrs, producer: producer, l: l}
+}
+
+// Replay 一直重放到 ctx 超时或者取消，返回重放了多少条
+// topic 是原本的 topic，group 是处理失败的消费者组
+func (r *DLQReplayer) Replay(ctx context.Context, topic string, group string) (int64, error) {
+	cfg := sarama.NewConfig()
+	// 第一次重放要从最早的死信开始
+	cfg.Consumer.Offsets.Initial = sarama.OffsetOldest
+	cg, err := sarama.NewConsumerGroup(r.addrs, "dlq_replay", cfg)
+	if err != nil {
+		return 0, err
+	}
+	defer cg.Close()
+	h := &replayHandler{producer: r.producer, group: group, l: r.l}
+	for ctx.Err() == nil {
+		err = cg.Consume(ctx, []string{DLQTopic(topic, group)}, h)
+		if err != nil && ctx.Err() == nil {
+			return h.cnt.Load(), err
+		}
+	}
+	return h.cnt.Load(), nil
+}
+
+type replayHandler struct {
+	producer sarama.SyncProducer
+	group    string
+	l        logger.LoggerV1
+	cnt      atomic.Int64
+}
+
+func (h *replayHandler) Setup(session sarama.ConsumerGroupSession) error {
+	return nil
+}
+
+func (h *replayHandler) Cleanup(session sarama.ConsumerGroupSession) error {
+	return nil
+}
+
+func (h *replayHandler) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+	for msg := range claim.Messages() {
+		cause, _ := header(msg, HeaderError)
+		origin := OriginTopic(msg)
+		// 重试次数清零，也不用等，重放之后失败了还会按照正常的流程重试
+		headers := append(copyHeaders(msg.Headers),
+			sarama.RecordHeader{Key: []byte(HeaderOriginTopic), Value: []byte(origin)})
+		_, _, err := h.producer.SendMessage(&sarama.ProducerMessage{
+			Topic:   RetryTopic(origin, h.group),
+			Key:     sarama.ByteEncoder(msg.Key),
+			Value:   sarama.ByteEncoder(msg.Value),
+			Headers: headers,
+		})
+		if err != nil {
+			h.l.Error("重放死信失败",
+				logger.String("topic", msg.Topic),
+				logger.Int64("offset", msg.Offset),
+				logger.Error(err))
+			return err
+		}
+		h.l.Info("重放死信",
+			logger.String("topic", msg.Topic),
+			logger.Int64("offset", msg.Offset),
+			logger.String("cause", cause))
+		h.cnt.Add(1)
+		session.MarkMessage(msg, "")
+	}
+	return nil
+}
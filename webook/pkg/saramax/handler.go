@@ -7,8 +7,9 @@ import (
 )
 
 type Handler[T any] struct {
-	l  logger.LoggerV1
-	fn func(msg *sarama.ConsumerMessage, event T) error
+	l     logger.LoggerV1
+	fn    func(msg *sarama.ConsumerMessage, event T) error
+	retry RetryConfig
 }
 
 func NewHandler[T any](l logger.LoggerV1, fn func(msg *sarama.ConsumerMessage, event T) error) *Handler[T] {
@@ -18,6 +19,12 @@ func NewHandler[T any](l logger.LoggerV1, fn func(msg *sarama.ConsumerMessage, e
 	}
 }
 
+// WithRetry 设置处理失败之后的重试策略，默认是不重试
+func (h *Handler[T]) WithRetry(cfg RetryConfig) *Handler[T] {
+	h.retry = cfg
+	return h
+}
+
 func (h *Handler[T]) Setup(session sarama.ConsumerGroupSession) error {
 	return nil
 }
@@ -29,24 +36,52 @@ func (h *Handler[T]) Cleanup(session sarama.ConsumerGroupSession) error {
 func (h *Handler[T]) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
 	msgs := claim.Messages()
 	for msg := range msgs {
-		// 在这里调用业务处理逻辑
+		if !waitRetryAt(session.Context(), msg) {
+			// 要退出了，这条消息留给下一次消费
+			return nil
+		}
 		var t T
 		err := json.Unmarshal(msg.Value, &t)
 		if err != nil {
-			// 你也可以在这里引入重试的逻辑
 			h.l.Error("反序列消息体失败",
 				logger.String("topic", msg.Topic),
 				logger.Int32("partition", msg.Partition),
 				logger.Int64("offset", msg.Offset),
 				logger.Error(err))
+			// 消息本身有问题，重试也没用，直接进死信
+			if h.retry.Failure != nil {
+				err = h.retry.Failure.DeadLetter(msg, err)
+				if err != nil {
+					h.l.Error("转发到死信失败",
+						logger.String("topic", msg.Topic),
+						logger.Int64("offset", msg.Offset),
+						logger.Error(err))
+					return err
+				}
+			}
+			session.MarkMessage(msg, "")
+			continue
 		}
-		err = h.fn(msg, t)
+		err = h.retry.run(session.Context(), func() error {
+			return h.fn(msg, t)
+		})
 		if err != nil {
 			h.l.Error("处理消息失败",
 				logger.String("topic", msg.Topic),
 				logger.Int32("partition", msg.Partition),
 				logger.Int64("offset", msg.Offset),
 				logger.Error(err))
+			if h.retry.Failure != nil {
+				err = h.retry.Failure.Handle(msg, err)
+				if err != nil {
+					// 转发也失败了，不能提交。返回之后会重新加入消费者组，从这条消息开始消费
+					h.l.Error("转发处理失败的消息失败",
+						logger.String("topic", msg.Topic),
+						logger.Int64("offset", msg.Offset),
+						logger.Error(err))
+					return err
+				}
+			}
 		}
 		session.MarkMessage(msg, "")
 	}
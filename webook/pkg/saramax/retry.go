@@ -0,0 +1,225 @@
+package saramax
+
+import (
+	"context"
+	"errors"
+	"github.com/IBM/sarama"
+	"github.com/wsqigo/basic-go/webook/pkg/logger"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// HeaderOriginTopic 消息最开始是发到哪个 topic 的
+	HeaderOriginTopic = "x-origin-topic"
+	// HeaderAttempt 已经转发到重试 topic 多少次了
+	HeaderAttempt = "x-attempt"
+	// HeaderError 最近一次处理失败的原因
+	HeaderError = "x-error"
+	// HeaderRetryAt 毫秒时间戳，到了这个时间才能重新处理
+	HeaderRetryAt = "x-retry-at"
+
+	retrySuffix = ".retry"
+	dlqSuffix   = ".dlq"
+)
+
+var errNoGroup = errors.New("FailureHandler 没有指定消费者组")
+
+// RetryTopic 消费者要同时订阅原本的 topic 和自己的重试 topic。
+// 重试 topic 按照消费者组区分，不然一个组处理失败的消息会被订阅同一个 topic 的其它组再处理一遍
+func RetryTopic(topic string, group string) string {
+	return topic + "." + group + retrySuffix
+}
+
+// DLQTopic 重试次数耗尽之后，消息进入这个消费者组的死信 topic，等人工处理或者重放
+func DLQTopic(topic string, group string) string {
+	return topic + "." + group + dlqSuffix
+}
+
+// RetryConfig 处理消息失败之后怎么办
+type RetryConfig struct {
+	// 在本地重试的次数，0 就是不重试
+	MaxRetries int
+	// 本地重试的间隔
+	Interval time.Duration
+	// 本地重试都失败之后，转发到重试 topic 或者死信 topic
+	// nil 的话就只记录日志，然后提交
+	Failure *FailureHandler
+}
+
+// run 按照配置在本地重试，ctx 取消了就不再重试
+func (c RetryConfig) run(ctx context.Context, fn func() error) error {
+	err := fn()
+	for i := 0; err != nil && i < c.MaxRetries; i++ {
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(c.Interval):
+		}
+		err = fn()
+	}
+	return err
+}
+
+// FailureHandler 把处理失败的消息转发出去
+// 转发次数没超过 maxAttempts 的，延迟一段时间之后从重试 topic 再消费一次；超过了就进死信 topic
+type FailureHandler struct {
+	producer sarama.SyncProducer
+	// 最多转发到重试 topic 多少次
+	maxAttempts int
+	// 第 n 次重试要延迟 n * delay
+	delay time.Duration
+	// 消息转发到这个消费者组自己的重试 topic 和死信 topic
+	group string
+	l     logger.LoggerV1
+}
+
+func NewFailureHandler(producer sarama.SyncProducer, maxAttempts int,
+	delay time.Duration, l logger.LoggerV1) *FailureHandler {
+	return &FailureHandler{
+		producer:    producer,
+		maxAttempts: maxAttempts,
+		delay:       delay,
+		l:           l,
+	}
+}
+
+// ForGroup 返回转发到 group 自己的重试 topic 和死信 topic 的 FailureHandler，其余配置共用
+func (f *FailureHandler) ForGroup(group string) *FailureHandler {
+	res := *f
+	res.group = group
+	return &res
+}
+
+// Handle 返回 error 说明转发也失败了，这时候消息不能提交
+func (f *FailureHandler) Handle(msg *sarama.ConsumerMessage, cause error) error {
+	if f.group == "" {
+		return errNoGroup
+	}
+	attempt := Attempt(msg) + 1
+	origin := OriginTopic(msg)
+	if attempt > f.maxAttempts {
+		return f.send(msg, DLQTopic(origin, f.group), origin, attempt, cause, time.Time{})
+	}
+	retryAt := time.Now().Add(time.Duration(attempt) * f.delay)
+	return f.send(msg, RetryTopic(origin, f.group), origin, attempt, cause, retryAt)
+}
+
+// DeadLetter 直接进死信 topic，用在重试也没用的场景，比如说消息格式不对
+func (f *FailureHandler) DeadLetter(msg *sarama.ConsumerMessage, cause error) error {
+	if f.group == "" {
+		return errNoGroup
+	}
+	origin := OriginTopic(msg)
+	return f.send(msg, DLQTopic(origin, f.group), origin, Attempt(msg), cause, time.Time{})
+}
+
+func (f *FailureHandler) send(msg *sarama.ConsumerMessage, topic, origin string,
+	attempt int, cause error, retryAt time.Time) error {
+	headers := copyHeaders(msg.Headers)
+	headers = append(headers,
+		sarama.RecordHeader{Key: []byte(HeaderOriginTopic), Value: []byte(origin)},
+		sarama.RecordHeader{Key: []byte(HeaderAttempt), Value: []byte(strconv.Itoa(attempt))},
+		sarama.RecordHeader{Key: []byte(HeaderError), Value: []byte(cause.Error())})
+	if !retryAt.IsZero() {
+		headers = append(headers, sarama.RecordHeader{
+			Key:   []byte(HeaderRetryAt),
+			Value: []byte(strconv.FormatInt(retryAt.UnixMilli(), 10)),
+		})
+	}
+	_, _, err := f.producer.SendMessage(&sarama.ProducerMessage{
+		Topic:   topic,
+		Key:     sarama.ByteEncoder(msg.Key),
+		Value:   sarama.ByteEncoder(msg.Value),
+		Headers: headers,
+	})
+	if err != nil {
+		return err
+	}
+	f.l.Warn("转发处理失败的消息",
+		logger.String("topic", msg.Topic),
+		logger.Int32("partition", msg.Partition),
+		logger.Int64("offset", msg.Offset),
+		logger.String("to", topic),
+		logger.Int("attempt", attempt),
+		logger.Error(cause))
+	return nil
+}
+
+// copyHeaders 复制原本的 header，去掉重试相关的，后面会重新设置
+func copyHeaders(headers []*sarama.RecordHeader) []sarama.RecordHeader {
+	res := make([]sarama.RecordHeader, 0, len(headers)+4)
+	for _, h := range headers {
+		switch string(h.Key) {
+		case HeaderOriginTopic, HeaderAttempt, HeaderError, HeaderRetryAt:
+			continue
+		}
+		res = append(res, *h)
+	}
+	return res
+}
+
+func header(msg *sarama.ConsumerMessage, key string) (string, bool) {
+	for _, h := range msg.Headers {
+		if string(h.Key) == key {
+			return string(h.Value), true
+		}
+	}
+	return "", false
+}
+
+// Attempt 消息已经被转发到重试 topic 多少次了，原始消息是 0
+func Attempt(msg *sarama.ConsumerMessage) int {
+	val, ok := header(msg, HeaderAttempt)
+	if !ok {
+		return 0
+	}
+	attempt, err := strconv.Atoi(val)
+	if err != nil {
+		return 0
+	}
+	return attempt
+}
+
+// OriginTopic 消息最开始的 topic
+func OriginTopic(msg *sarama.ConsumerMessage) string {
+	if val, ok := header(msg, HeaderOriginTopic); ok {
+		return val
+	}
+	// 理论上重试 topic 里面的消息都有 header，保险起见去掉 .<group>.retry 或者 .<group>.dlq
+	topic := msg.Topic
+	if !strings.HasSuffix(topic, retrySuffix) && !strings.HasSuffix(topic, dlqSuffix) {
+		return topic
+	}
+	topic = strings.TrimSuffix(strings.TrimSuffix(topic, retrySuffix), dlqSuffix)
+	if idx := strings.LastIndex(topic, "."); idx > 0 {
+		topic = topic[:idx]
+	}
+	return topic
+}
+
+// waitRetryAt 重试 topic 里面的消息要等到了时间才处理
+// 返回 false 说明 ctx 取消了，消息不要处理也不要提交
+func waitRetryAt(ctx context.Context, msg *sarama.ConsumerMessage) bool {
+	val, ok := header(msg, HeaderRetryAt)
+	if !ok {
+		return true
+	}
+	retryAt, err := strconv.ParseInt(val, 10, 64)
+	if err != nil {
+		return true
+	}
+	d := time.Until(time.UnixMilli(retryAt))
+	if d <= 0 {
+		return true
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
@@ -0,0 +1,115 @@
+package saramax
+
+import (
+	"errors"
+	"github.com/IBM/sarama"
+	"github.com/IBM/sarama/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wsqigo/basic-go/webook/pkg/logger"
+	"testing"
+	"time"
+)
+
+func TestFailureHandler_Handle(t *testing.T) {
+	testCases := []struct {
+		name string
+		msg  *sarama.ConsumerMessage
+
+		wantTopic   string
+		wantAttempt string
+		wantRetryAt bool
+	}{
+		{
+			name: "第一次失败，进重试 topic",
+			msg: &sarama.ConsumerMessage{
+				Topic: "article_read",
+				Value: []byte(`{"aid":1}`),
+			},
+			wantTopic:   "article_read.history.retry",
+			wantAttempt: "1",
+			wantRetryAt: true,
+		},
+		{
+			name: "重试 topic 里面的消息再次失败",
+			msg: &sarama.ConsumerMessage{
+				Topic: "article_read.history.retry",
+				Value: []byte(`{"aid":1}`),
+				Headers: []*sarama.RecordHeader{
+					{Key: []byte(HeaderOriginTopic), Value: []byte("article_read")},
+					{Key: []byte(HeaderAttempt), Value: []byte("1")},
+					{Key: []byte(HeaderRetryAt), Value: []byte("123")},
+				},
+			},
+			wantTopic:   "article_read.history.retry",
+			wantAttempt: "2",
+			wantRetryAt: true,
+		},
+		{
+			name: "重试次数耗尽，进死信",
+			msg: &sarama.ConsumerMessage{
+				Topic: "article_read.history.retry",
+				Value: []byte(`{"aid":1}`),
+				Headers: []*sarama.RecordHeader{
+					{Key: []byte(HeaderOriginTopic), Value: []byte("article_read")},
+					{Key: []byte(HeaderAttempt), Value: []byte("2")},
+				},
+			},
+			wantTopic:   "article_read.history.dlq",
+			wantAttempt: "3",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			producer := mocks.NewSyncProducer(t, nil)
+			var sent *sarama.ProducerMessage
+			producer.ExpectSendMessageWithMessageCheckerFunctionAndSucceed(
+				func(msg *sarama.ProducerMessage) error {
+					sent = msg
+					return nil
+				})
+			f := NewFailureHandler(producer, 2, time.Second, logger.NewNopLogger()).ForGroup("history")
+			err := f.Handle(tc.msg, errors.New("数据库错误"))
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantTopic, sent.Topic)
+			headers := map[string]string{}
+			for _, h := range sent.Headers {
+				// 重试相关的 header 不能重复
+				_, ok := headers[string(h.Key)]
+				assert.False(t, ok)
+				headers[string(h.Key)] = string(h.Value)
+			}
+			assert.Equal(t, "article_read", headers[HeaderOriginTopic])
+			assert.Equal(t, tc.wantAttempt, headers[HeaderAttempt])
+			assert.Equal(t, "数据库错误", headers[HeaderError])
+			_, ok := headers[HeaderRetryAt]
+			assert.Equal(t, tc.wantRetryAt, ok)
+		})
+	}
+}
+
+func TestFailureHandler_NoGroup(t *testing.T) {
+	producer := mocks.NewSyncProducer(t, nil)
+	f := NewFailureHandler(producer, 2, time.Second, logger.NewNopLogger())
+	err := f.Handle(&sarama.ConsumerMessage{Topic: "article_read"}, errors.New("数据库错误"))
+	assert.Equal(t, errNoGroup, err)
+}
+
+func TestOriginTopic(t *testing.T) {
+	testCases := []struct {
+		name  string
+		topic string
+
+		want string
+	}{
+		{name: "原本的 topic", topic: "article_read", want: "article_read"},
+		{name: "重试 topic", topic: "article_read.history.retry", want: "article_read"},
+		{name: "死信 topic", topic: "article_read.interactive.dlq", want: "article_read"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, OriginTopic(&sarama.ConsumerMessage{Topic: tc.topic}))
+		})
+	}
+}
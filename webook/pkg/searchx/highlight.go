@@ -0,0 +1,73 @@
+package searchx
+
+import (
+	"html"
+	"strings"
+)
+
+// highlight 把 text 里面命中 terms 的部分用 <em></em> 包起来。
+// snippetLen 大于 0 的时候，只截取第一个命中位置附近的片段
+func highlight(tokenizer Tokenizer, text string,
+	terms map[string]struct{}, snippetLen int) string {
+	runes := []rune(text)
+	// 命中的区间，分词结果本身是按照 Start 有序的
+	var ranges [][2]int
+	for _, token := range tokenizer.Tokenize(text) {
+		if _, ok := terms[token.Term]; !ok {
+			continue
+		}
+		last := len(ranges) - 1
+		if last >= 0 && token.Start <= ranges[last][1] {
+			// 重叠或者相邻的区间合并起来
+			if token.End > ranges[last][1] {
+				ranges[last][1] = token.End
+			}
+			continue
+		}
+		ranges = append(ranges, [2]int{token.Start, token.End})
+	}
+
+	start, end := 0, len(runes)
+	if snippetLen > 0 && len(runes) > snippetLen {
+		if len(ranges) > 0 {
+			// 命中的位置前面留一点上下文
+			start = ranges[0][0] - snippetLen/4
+		}
+		if start < 0 {
+			start = 0
+		}
+		end = start + snippetLen
+		if end > len(runes) {
+			end = len(runes)
+			start = end - snippetLen
+		}
+	}
+
+	var sb strings.Builder
+	if start > 0 {
+		sb.WriteString("...")
+	}
+	pos := start
+	for _, r := range ranges {
+		s, e := r[0], r[1]
+		if e <= start || s >= end {
+			continue
+		}
+		if s < start {
+			s = start
+		}
+		if e > end {
+			e = end
+		}
+		sb.WriteString(html.EscapeString(string(runes[pos:s])))
+		sb.WriteString("<em>")
+		sb.WriteString(html.EscapeString(string(runes[s:e])))
+		sb.WriteString("</em>")
+		pos = e
+	}
+	sb.WriteString(html.EscapeString(string(runes[pos:end])))
+	if end < len(runes) {
+		sb.WriteString("...")
+	}
+	return sb.String()
+}
@@ -0,0 +1,207 @@
+package searchx
+
+import (
+	"context"
+	"math"
+	"sort"
+	"sync"
+)
+
+// MemoryIndex 进程内的倒排索引。
+// 数据全部在内存里面，重启之后需要重建；多实例部署的时候，每个实例都要收到全量的变更
+type MemoryIndex struct {
+	mu        sync.RWMutex
+	tokenizer Tokenizer
+	fields    []Field
+	docs      map[int64]Document
+	// term => 文档 ID => 每个检索字段里面出现的次数
+	postings map[string]map[int64][]int
+	// 文档 ID => 文档里面出现过的 term，删除文档的时候用
+	docTerms map[int64][]string
+	// 文档 ID => 最新的版本，删掉的文档也会保留，当作墓碑
+	versions map[int64]int64
+}
+
+func NewMemoryIndex(tokenizer Tokenizer, fields ...Field) *MemoryIndex {
+	return &MemoryIndex{
+		tokenizer: tokenizer,
+		fields:    fields,
+		docs:      make(map[int64]Document),
+		postings:  make(map[string]map[int64][]int),
+		docTerms:  make(map[int64][]string),
+		versions:  make(map[int64]int64),
+	}
+}
+
+func (m *MemoryIndex) Upsert(ctx context.Context, doc Document) error {
+	// 分词比较耗时，放在锁外面
+	tfs := make(map[string][]int)
+	for i, field := range m.fields {
+		for _, token := range m.tokenizer.Tokenize(doc.Fields[field.Name]) {
+			tf, ok := tfs[token.Term]
+			if !ok {
+				tf = make([]int, len(m.fields))
+				tfs[token.Term] = tf
+			}
+			tf[i]++
+		}
+	}
+	terms := make([]string, 0, len(tfs))
+	for term := range tfs {
+		terms = append(terms, term)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.stale(doc.Id, doc.Version) {
+		return nil
+	}
+	m.delete(doc.Id)
+	m.versions[doc.Id] = doc.Version
+	m.docs[doc.Id] = doc
+	m.docTerms[doc.Id] = terms
+	for term, tf := range tfs {
+		ps, ok := m.postings[term]
+		if !ok {
+			ps = make(map[int64][]int)
+			m.postings[term] = ps
+		}
+		ps[doc.Id] = tf
+	}
+	return nil
+}
+
+func (m *MemoryIndex) Delete(ctx context.Context, id int64, version int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.stale(id, version) {
+		return nil
+	}
+	m.delete(id)
+	m.versions[id] = version
+	return nil
+}
+
+// stale 已经有更新的版本了
+func (m *MemoryIndex) stale(id int64, version int64) bool {
+	v, ok := m.versions[id]
+	return ok && version < v
+}
+
+func (m *MemoryIndex) delete(id int64) {
+	for _, term := range m.docTerms[id] {
+		ps := m.postings[term]
+		delete(ps, id)
+		if len(ps) == 0 {
+			delete(m.postings, term)
+		}
+	}
+	delete(m.docTerms, id)
+	delete(m.docs, id)
+}
+
+func (m *MemoryIndex) Search(ctx context.Context, q Query) (Result, error) {
+	terms := uniqueTerms(m.tokenizer.TokenizeQuery(q.Keywords))
+	if len(terms) == 0 {
+		return Result{}, nil
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	// 所有的词都要命中，先从最短的倒排链开始求交集
+	lists := make([]map[int64][]int, 0, len(terms))
+	for _, term := range terms {
+		ps, ok := m.postings[term]
+		if !ok {
+			return Result{}, nil
+		}
+		lists = append(lists, ps)
+	}
+	sort.Slice(lists, func(i, j int) bool {
+		return len(lists[i]) < len(lists[j])
+	})
+	n := float64(len(m.docs))
+	scores := make(map[int64]float64, len(lists[0]))
+	for id := range lists[0] {
+		scores[id] = 0
+	}
+	for _, ps := range lists {
+		df := float64(len(ps))
+		idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+		for id := range scores {
+			tf, ok := ps[id]
+			if !ok {
+				delete(scores, id)
+				continue
+			}
+			scores[id] += idf * m.fieldScore(tf)
+		}
+	}
+
+	hits := make([]Hit, 0, len(scores))
+	for id, score := range scores {
+		hits = append(hits, Hit{Id: id, Score: score})
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		// 分数一样的，新的文档排前面
+		return hits[i].Id > hits[j].Id
+	})
+	res := Result{Total: len(hits)}
+	hits = page(hits, q.Offset, q.Limit)
+	termSet := make(map[string]struct{}, len(terms))
+	for _, term := range terms {
+		termSet[term] = struct{}{}
+	}
+	for i := range hits {
+		doc := m.docs[hits[i].Id]
+		hits[i].Fields = doc.Fields
+		hits[i].Highlights = make(map[string]string, len(m.fields))
+		for _, field := range m.fields {
+			hits[i].Highlights[field.Name] = highlight(m.tokenizer,
+				doc.Fields[field.Name], termSet, field.SnippetLen)
+		}
+	}
+	res.Hits = hits
+	return res, nil
+}
+
+// fieldScore 词频做了饱和处理，避免一个词重复很多次就排到最前面
+func (m *MemoryIndex) fieldScore(tf []int) float64 {
+	const k = 1.2
+	var res float64
+	for i, cnt := range tf {
+		if cnt == 0 {
+			continue
+		}
+		c := float64(cnt)
+		res += m.fields[i].Weight * c * (k + 1) / (c + k)
+	}
+	return res
+}
+
+func uniqueTerms(tokens []Token) []string {
+	seen := make(map[string]struct{}, len(tokens))
+	res := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		if _, ok := seen[token.Term]; ok {
+			continue
+		}
+		seen[token.Term] = struct{}{}
+		res = append(res, token.Term)
+	}
+	return res
+}
+
+func page[T any](src []T, offset, limit int) []T {
+	if offset >= len(src) {
+		return []T{}
+	}
+	end := len(src)
+	if limit > 0 && offset+limit < end {
+		end = offset + limit
+	}
+	return src[offset:end]
+}
@@ -0,0 +1,141 @@
+package searchx
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestMemoryIndex_Search(t *testing.T) {
+	idx := NewMemoryIndex(NewNGramTokenizer(),
+		Field{Name: "title", Weight: 3},
+		Field{Name: "content", Weight: 1, SnippetLen: 10})
+	ctx := context.Background()
+	docs := []Document{
+		{Id: 1, Fields: map[string]string{"title": "Go 语言入门", "content": "学习 Golang 的第一步"}},
+		{Id: 2, Fields: map[string]string{"title": "机器学习", "content": "机器学习和 Go 没什么关系"}},
+		{Id: 3, Fields: map[string]string{"title": "随笔", "content": "今天学了一点机器人的知识<b>"}},
+	}
+	for _, doc := range docs {
+		require.NoError(t, idx.Upsert(ctx, doc))
+	}
+
+	testCases := []struct {
+		name  string
+		query Query
+
+		wantTotal      int
+		wantIds        []int64
+		wantHighlights map[string]string
+	}{
+		{
+			name:      "英文不区分大小写，标题权重更高",
+			query:     Query{Keywords: "go", Limit: 10},
+			wantTotal: 2,
+			wantIds:   []int64{1, 2},
+			wantHighlights: map[string]string{
+				"title":   "<em>Go</em> 语言入门",
+				"content": "学习 Golang ...",
+			},
+		},
+		{
+			name:      "中文按照相邻两个字匹配",
+			query:     Query{Keywords: "机器学习", Limit: 10},
+			wantTotal: 1,
+			wantIds:   []int64{2},
+			wantHighlights: map[string]string{
+				"title":   "<em>机器学习</em>",
+				"content": "<em>机器学习</em>和 Go 没...",
+			},
+		},
+		{
+			name:      "单个中文字",
+			query:     Query{Keywords: "机", Limit: 10},
+			wantTotal: 2,
+			wantIds:   []int64{2, 3},
+		},
+		{
+			name:      "高亮的时候转义 HTML",
+			query:     Query{Keywords: "机器人", Limit: 10},
+			wantTotal: 1,
+			wantIds:   []int64{3},
+			wantHighlights: map[string]string{
+				"title":   "随笔",
+				"content": "...一点<em>机器人</em>的知识&lt;b...",
+			},
+		},
+		{
+			name:      "分页",
+			query:     Query{Keywords: "go", Offset: 1, Limit: 1},
+			wantTotal: 2,
+			wantIds:   []int64{2},
+		},
+		{
+			name:      "没有命中",
+			query:     Query{Keywords: "rust", Limit: 10},
+			wantTotal: 0,
+			wantIds:   []int64{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := idx.Search(ctx, tc.query)
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantTotal, res.Total)
+			ids := make([]int64, 0, len(res.Hits))
+			for _, hit := range res.Hits {
+				ids = append(ids, hit.Id)
+			}
+			assert.Equal(t, tc.wantIds, ids)
+			if tc.wantHighlights != nil {
+				assert.Equal(t, tc.wantHighlights, res.Hits[0].Highlights)
+			}
+		})
+	}
+}
+
+func TestMemoryIndex_Delete(t *testing.T) {
+	idx := NewMemoryIndex(NewNGramTokenizer(), Field{Name: "title", Weight: 1})
+	ctx := context.Background()
+	require.NoError(t, idx.Upsert(ctx, Document{Id: 1, Fields: map[string]string{"title": "旧标题"}}))
+	// 更新之后旧的内容就搜不到了
+	require.NoError(t, idx.Upsert(ctx, Document{Id: 1, Fields: map[string]string{"title": "新标题"}}))
+	res, err := idx.Search(ctx, Query{Keywords: "旧标题"})
+	require.NoError(t, err)
+	assert.Equal(t, 0, res.Total)
+	res, err = idx.Search(ctx, Query{Keywords: "新标题"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, res.Total)
+
+	require.NoError(t, idx.Delete(ctx, 1, 0))
+	res, err = idx.Search(ctx, Query{Keywords: "新标题"})
+	require.NoError(t, err)
+	assert.Equal(t, 0, res.Total)
+	assert.Empty(t, idx.postings)
+}
+
+func TestMemoryIndex_Version(t *testing.T) {
+	idx := NewMemoryIndex(NewNGramTokenizer(), Field{Name: "title", Weight: 1})
+	ctx := context.Background()
+	require.NoError(t, idx.Upsert(ctx, Document{Id: 1, Version: 2, Fields: map[string]string{"title": "新标题"}}))
+	// 重建索引的时候读到的旧数据，不能覆盖增量同步写进来的新数据
+	require.NoError(t, idx.Upsert(ctx, Document{Id: 1, Version: 1, Fields: map[string]string{"title": "旧标题"}}))
+	res, err := idx.Search(ctx, Query{Keywords: "新标题"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, res.Total)
+
+	// 删除之后，旧的数据也不能写回去
+	require.NoError(t, idx.Delete(ctx, 1, 3))
+	require.NoError(t, idx.Upsert(ctx, Document{Id: 1, Version: 2, Fields: map[string]string{"title": "新标题"}}))
+	res, err = idx.Search(ctx, Query{Keywords: "新标题"})
+	require.NoError(t, err)
+	assert.Equal(t, 0, res.Total)
+
+	// 重新发表之后版本更新，可以写进来
+	require.NoError(t, idx.Upsert(ctx, Document{Id: 1, Version: 4, Fields: map[string]string{"title": "重新发表"}}))
+	res, err = idx.Search(ctx, Query{Keywords: "重新发表"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, res.Total)
+}
@@ -0,0 +1,84 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./types.go
+//
+// Generated by this command:
+//
+//	mockgen -destination=./mocks/index.mock.go -package=searchxmocks -source=./types.go Index
+//
+
+// Package searchxmocks is a generated GoMock package.
+package searchxmocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	searchx "github.com/wsqigo/basic-go/webook/pkg/searchx"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockIndex is a mock of Index interface.
+type MockIndex struct {
+	ctrl     *gomock.Controller
+	recorder *MockIndexMockRecorder
+}
+
+// MockIndexMockRecorder is the mock recorder for MockIndex.
+type MockIndexMockRecorder struct {
+	mock *MockIndex
+}
+
+// NewMockIndex creates a new mock instance.
+func NewMockIndex(ctrl *gomock.Controller) *MockIndex {
+	mock := &MockIndex{ctrl: ctrl}
+	mock.recorder = &MockIndexMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIndex) EXPECT() *MockIndexMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockIndex) Delete(ctx context.Context, id, version int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id, version)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockIndexMockRecorder) Delete(ctx, id, version any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockIndex)(nil).Delete), ctx, id, version)
+}
+
+// Search mocks base method.
+func (m *MockIndex) Search(ctx context.Context, q searchx.Query) (searchx.Result, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", ctx, q)
+	ret0, _ := ret[0].(searchx.Result)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Search indicates an expected call of Search.
+func (mr *MockIndexMockRecorder) Search(ctx, q any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockIndex)(nil).Search), ctx, q)
+}
+
+// Upsert mocks base method.
+func (m *MockIndex) Upsert(ctx context.Context, doc searchx.Document) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Upsert", ctx, doc)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Upsert indicates an expected call of Upsert.
+func (mr *MockIndexMockRecorder) Upsert(ctx, doc any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upsert", reflect.TypeOf((*MockIndex)(nil).Upsert), ctx, doc)
+}
@@ -0,0 +1,104 @@
+package searchx
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Token 分词结果，Start 和 End 是按照 rune 计算的位置，左闭右开
+type Token struct {
+	Term  string
+	Start int
+	End   int
+}
+
+// Tokenizer 分词器
+type Tokenizer interface {
+	// Tokenize 建索引的时候用
+	Tokenize(text string) []Token
+	// TokenizeQuery 搜索的时候用，可以和建索引用不同的策略
+	TokenizeQuery(text string) []Token
+}
+
+// NGramTokenizer 不依赖词典的分词器：
+// 英文和数字按照单词切分，统一转小写；
+// 中文（以及日文、韩文）按照单字 + 相邻两个字切分。
+// 搜索的时候中文只用相邻两个字，这样"机器学习"不会命中只有"机"和"学"的文章，
+// 只输入一个字的时候才用单字
+type NGramTokenizer struct{}
+
+func NewNGramTokenizer() *NGramTokenizer {
+	return &NGramTokenizer{}
+}
+
+func (n *NGramTokenizer) Tokenize(text string) []Token {
+	return n.tokenize(text, true)
+}
+
+func (n *NGramTokenizer) TokenizeQuery(text string) []Token {
+	return n.tokenize(text, false)
+}
+
+func (n *NGramTokenizer) tokenize(text string, withUnigram bool) []Token {
+	runes := []rune(text)
+	var res []Token
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case isCJK(r):
+			j := i
+			for j < len(runes) && isCJK(runes[j]) {
+				j++
+			}
+			res = append(res, n.cjkTokens(runes[i:j], i, withUnigram)...)
+			i = j
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			j := i
+			for j < len(runes) && !isCJK(runes[j]) &&
+				(unicode.IsLetter(runes[j]) || unicode.IsDigit(runes[j])) {
+				j++
+			}
+			res = append(res, Token{
+				Term:  strings.ToLower(string(runes[i:j])),
+				Start: i,
+				End:   j,
+			})
+			i = j
+		default:
+			// 标点符号、空白之类的直接跳过
+			i++
+		}
+	}
+	return res
+}
+
+func (n *NGramTokenizer) cjkTokens(runes []rune, offset int, withUnigram bool) []Token {
+	if len(runes) == 1 {
+		return []Token{{Term: string(runes), Start: offset, End: offset + 1}}
+	}
+	res := make([]Token, 0, 2*len(runes))
+	for i := range runes {
+		if withUnigram {
+			res = append(res, Token{
+				Term:  string(runes[i]),
+				Start: offset + i,
+				End:   offset + i + 1,
+			})
+		}
+		if i+1 < len(runes) {
+			res = append(res, Token{
+				Term:  string(runes[i : i+2]),
+				Start: offset + i,
+				End:   offset + i + 2,
+			})
+		}
+	}
+	return res
+}
+
+func isCJK(r rune) bool {
+	return unicode.Is(unicode.Han, r) ||
+		unicode.Is(unicode.Hiragana, r) ||
+		unicode.Is(unicode.Katakana, r) ||
+		unicode.Is(unicode.Hangul, r)
+}
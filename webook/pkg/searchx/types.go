@@ -0,0 +1,55 @@
+package searchx
+
+import "context"
+
+// Index 全文索引的抽象。
+// 现在只有进程内的实现，后面数据量上来了可以换成 Elasticsearch
+//
+//go:generate mockgen -destination=./mocks/index.mock.go -package=searchxmocks -source=./types.go Index
+type Index interface {
+	// Upsert 同一个 Id 的文档会被整个替换掉，比索引里面的版本旧的文档会被忽略
+	Upsert(ctx context.Context, doc Document) error
+	// Delete 文档不存在也不会返回 error。
+	// 删除之后，版本不比 version 新的文档都写不进来，避免重建索引的时候把删掉的文档又写回去
+	Delete(ctx context.Context, id int64, version int64) error
+	Search(ctx context.Context, q Query) (Result, error)
+}
+
+type Document struct {
+	Id int64
+	// 版本号，一般用更新时间。重建索引和增量同步同时进行的时候，用来丢弃旧的数据
+	Version int64
+	// 只有建索引的时候声明过的字段才会被检索，
+	// 其余字段只是存起来，搜索的时候原样返回
+	Fields map[string]string
+}
+
+type Query struct {
+	Keywords string
+	Offset   int
+	Limit    int
+}
+
+type Result struct {
+	// 一共命中了多少文档，用来分页
+	Total int
+	Hits  []Hit
+}
+
+type Hit struct {
+	Id     int64
+	Score  float64
+	Fields map[string]string
+	// 检索字段高亮之后的结果，命中的词用 <em></em> 包起来，
+	// 其余部分做了 HTML 转义
+	Highlights map[string]string
+}
+
+// Field 检索字段
+type Field struct {
+	Name string
+	// 命中这个字段的得分权重，比如说标题应该比内容重要
+	Weight float64
+	// 高亮的时候截取的片段长度（按字符算），0 代表整个字段都返回
+	SnippetLen int
+}
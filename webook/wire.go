@@ -5,10 +5,14 @@ package main
 import (
 	"github.com/google/wire"
 	"github.com/wsqigo/basic-go/webook/internal/events/article"
+	"github.com/wsqigo/basic-go/webook/internal/events/follow"
+	"github.com/wsqigo/basic-go/webook/internal/events/user"
 	"github.com/wsqigo/basic-go/webook/internal/repository"
 	"github.com/wsqigo/basic-go/webook/internal/repository/cache"
 	"github.com/wsqigo/basic-go/webook/internal/repository/dao"
 	"github.com/wsqigo/basic-go/webook/internal/service"
+	"github.com/wsqigo/basic-go/webook/internal/service/sms"
+	"github.com/wsqigo/basic-go/webook/internal/service/sms/async"
 	"github.com/wsqigo/basic-go/webook/internal/web"
 	jwt2 "github.com/wsqigo/basic-go/webook/internal/web/jwt"
 	"github.com/wsqigo/basic-go/webook/ioc"
@@ -19,11 +23,55 @@ var interactiveSvcSet = wire.NewSet(dao.NewGORMInteractiveDAO,
 	repository.NewCachedInteractiveRepository,
 	service.NewInteractiveService)
 
+var commentSvcSet = wire.NewSet(dao.NewGORMCommentDAO,
+	repository.NewCachedCommentRepository,
+	service.NewCommentService)
+
+var collectionSvcSet = wire.NewSet(dao.NewGORMCollectionDAO,
+	repository.NewCachedCollectionRepository,
+	service.NewCollectionService)
+
+var followSvcSet = wire.NewSet(dao.NewGORMFollowRelationDAO,
+	cache.NewFollowRedisCache,
+	repository.NewCachedFollowRepository,
+	follow.NewSaramaSyncProducer,
+	service.NewFollowService)
+
+var feedSvcSet = wire.NewSet(cache.NewFeedRedisCache,
+	repository.NewCachedFeedRepository,
+	service.NewFeedService)
+
+var searchSvcSet = wire.NewSet(repository.NewMemorySearchRepository,
+	service.NewSearchService)
+
+var historySvcSet = wire.NewSet(dao.NewGORMHistoryRecordDAO,
+	repository.NewDBHistoryRecordRepository,
+	service.NewHistoryRecordService)
+
+var jobSvcSet = wire.NewSet(dao.NewGORMJobDAO,
+	dao.NewGORMJobExecutionDAO,
+	repository.NewPreemptJobRepository,
+	repository.NewDBJobExecutionRepository,
+	service.NewCronJobService)
+
 var rankingSvcSet = wire.NewSet(
 	cache.NewRankingRedisCache,
 	repository.NewCachedRankingRepository,
 	service.NewBatchRankingService)
 
+// 服务商不健康的时候，验证码之类的短信转异步发送
+var asyncSmsSvcSet = wire.NewSet(dao.NewGORMAsyncSmsDAO,
+	repository.NewDBAsyncSmsRepository,
+	ioc.InitAsyncSMSService,
+	wire.Bind(new(sms.Service), new(*async.Service)),
+	ioc.InitAsyncSmsCleanJob)
+
+// 内部的短信接口，其它服务通过 webook 的服务商链路发送短信
+var smsAPISvcSet = wire.NewSet(ioc.InitSMSTokenManager,
+	ioc.InitSMSAPIService,
+	web.NewSMSHandler,
+	ioc.InitInternalSMSServer)
+
 func InitWebServer() *App {
 	wire.Build(
 		// 第三方依赖
@@ -34,15 +82,40 @@ func InitWebServer() *App {
 		ioc.InitRlockClient,
 		// DAO 部分
 		dao.NewUserDao,
-		dao.NewArticleGORMDAO,
+		ioc.InitArticleDAO,
 
 		interactiveSvcSet,
+		commentSvcSet,
+		collectionSvcSet,
+		followSvcSet,
+		feedSvcSet,
+		searchSvcSet,
+		historySvcSet,
+		jobSvcSet,
 		rankingSvcSet,
 		ioc.InitJobs,
 		ioc.InitRankingJob,
+		ioc.InitHistoryCleanJob,
+		dao.NewGORMOutboxDAO,
+		repository.NewDBOutboxRepository,
+		article.NewOutboxRelay,
+		ioc.InitOutboxRelayJob,
+		ioc.InitOutboxCleanJob,
+		ioc.InitScheduler,
+		cache.NewNodeLoadRedisCache,
+		ioc.InitLoadBalancer,
+		ioc.InitLifecycle,
 
-		article.NewSaramaSyncProducer,
+		ioc.InitAsyncProducer,
+		ioc.InitArticleProducer,
 		article.NewInteractiveReadEventConsumer,
+		user.NewSaramaSyncProducer,
+		ioc.InitArticlePublishEventConsumer,
+		ioc.InitSearchSyncConsumer,
+		article.NewHistoryRecordConsumer,
+		ioc.InitFailureHandler,
+		ioc.InitReadEventBatchConfig,
+		ioc.InitReadEventDeduper,
 		ioc.InitConsumers,
 
 		// cache 部分
@@ -55,16 +128,25 @@ func InitWebServer() *App {
 		repository.NewCachedArticleRepository,
 
 		// Service 部分
-		ioc.InitSMSService,
+		asyncSmsSvcSet,
+		smsAPISvcSet,
 		ioc.InitDingDingService,
 		service.NewUserService,
 		service.NewCodeService,
+		ioc.InitCodeSendGuard,
 		service.NewArticleService,
 
 		// handler 部分
 		web.NewUserHandler,
 		web.NewArticleHandler,
 		web.NewOAuth2DingDingHandler,
+		web.NewCommentHandler,
+		web.NewCollectionHandler,
+		web.NewFollowHandler,
+		web.NewFeedHandler,
+		web.NewSearchHandler,
+		web.NewHistoryHandler,
+		ioc.InitCronJobHandler,
 		jwt2.NewRedisJWTHandler,
 
 		ioc.InitGinMiddlewares,
@@ -9,10 +9,14 @@ package main
 import (
 	"github.com/google/wire"
 	"github.com/wsqigo/basic-go/webook/internal/events/article"
+	"github.com/wsqigo/basic-go/webook/internal/events/follow"
+	"github.com/wsqigo/basic-go/webook/internal/events/user"
 	"github.com/wsqigo/basic-go/webook/internal/repository"
 	"github.com/wsqigo/basic-go/webook/internal/repository/cache"
 	"github.com/wsqigo/basic-go/webook/internal/repository/dao"
 	"github.com/wsqigo/basic-go/webook/internal/service"
+	"github.com/wsqigo/basic-go/webook/internal/service/sms"
+	"github.com/wsqigo/basic-go/webook/internal/service/sms/async"
 	"github.com/wsqigo/basic-go/webook/internal/web"
 	"github.com/wsqigo/basic-go/webook/internal/web/jwt"
 	"github.com/wsqigo/basic-go/webook/ioc"
@@ -33,19 +37,24 @@ func InitWebServer() *App {
 	userDAO := dao.NewUserDao(db)
 	userCache := cache.NewUserCache(cmdable)
 	userRepository := repository.NewUserRepository(userDAO, userCache)
-	userService := service.NewUserService(userRepository)
+	client := ioc.InitSaramaClient()
+	syncProducer := ioc.InitSyncProducer(client)
+	producer := user.NewSaramaSyncProducer(syncProducer)
+	userService := service.NewUserService(userRepository, producer)
 	codeCache := cache.NewCodeCache(cmdable)
 	codeRepository := repository.NewCodeRepository(codeCache)
-	smsService := ioc.InitSMSService()
-	codeService := service.NewCodeService(codeRepository, smsService)
-	userHandler := web.NewUserHandler(userService, handler, codeService)
-	articleDAO := dao.NewArticleGORMDAO(db)
+	asyncSmsDAO := dao.NewGORMAsyncSmsDAO(db)
+	asyncSmsRepository := repository.NewDBAsyncSmsRepository(asyncSmsDAO)
+	asyncService := ioc.InitAsyncSMSService(asyncSmsRepository, loggerV1)
+	codeService := service.NewCodeService(codeRepository, asyncService)
+	codeSendGuard := ioc.InitCodeSendGuard(cmdable)
+	userHandler := web.NewUserHandler(userService, handler, codeService, codeSendGuard)
+	articleDAO := ioc.InitArticleDAO(db, loggerV1)
 	articleCache := cache.NewArticleRedisCache(cmdable)
 	articleRepository := repository.NewCachedArticleRepository(articleDAO, userRepository, articleCache, loggerV1)
-	client := ioc.InitSaramaClient()
-	syncProducer := ioc.InitSyncProducer(client)
-	producer := article.NewSaramaSyncProducer(syncProducer)
-	articleService := service.NewArticleService(articleRepository, producer)
+	asyncProducer := ioc.InitAsyncProducer(client)
+	articleProducer := ioc.InitArticleProducer(asyncProducer, loggerV1)
+	articleService := service.NewArticleService(articleRepository, articleProducer, loggerV1)
 	interactiveDAO := dao.NewGORMInteractiveDAO(db)
 	interactiveCache := cache.NewInteractiveRedisCache(cmdable)
 	interactiveRepository := repository.NewCachedInteractiveRepository(interactiveDAO, loggerV1, interactiveCache)
@@ -53,17 +62,77 @@ func InitWebServer() *App {
 	articleHandler := web.NewArticleHandler(loggerV1, articleService, interactiveService)
 	dingdingService := ioc.InitDingDingService(loggerV1)
 	oAuth2DingDingHandler := web.NewOAuth2DingDingHandler(dingdingService, handler, userService)
-	engine := ioc.InitWebServer(v, userHandler, articleHandler, oAuth2DingDingHandler)
-	interactiveReadEventConsumer := article.NewInteractiveReadEventConsumer(interactiveRepository, client, loggerV1)
-	v2 := ioc.InitConsumers(interactiveReadEventConsumer)
+	commentDAO := dao.NewGORMCommentDAO(db)
+	commentRepository := repository.NewCachedCommentRepository(commentDAO, interactiveCache, loggerV1)
+	commentService := service.NewCommentService(commentRepository, articleRepository)
+	commentHandler := web.NewCommentHandler(commentService, loggerV1)
+	collectionDAO := dao.NewGORMCollectionDAO(db)
+	collectionRepository := repository.NewCachedCollectionRepository(collectionDAO, interactiveCache, loggerV1)
+	collectionService := service.NewCollectionService(collectionRepository)
+	collectionHandler := web.NewCollectionHandler(collectionService, loggerV1)
+	followRelationDAO := dao.NewGORMFollowRelationDAO(db)
+	followCache := cache.NewFollowRedisCache(cmdable)
+	followRepository := repository.NewCachedFollowRepository(followRelationDAO, followCache, loggerV1)
+	followProducer := follow.NewSaramaSyncProducer(syncProducer)
+	followService := service.NewFollowService(followRepository, followProducer, loggerV1)
+	followHandler := web.NewFollowHandler(followService, loggerV1)
+	feedCache := cache.NewFeedRedisCache(cmdable)
+	feedRepository := repository.NewCachedFeedRepository(feedCache)
+	feedService := service.NewFeedService(feedRepository, followRepository, articleRepository)
+	feedHandler := web.NewFeedHandler(feedService, loggerV1)
+	searchRepository := repository.NewMemorySearchRepository()
+	searchService := service.NewSearchService(searchRepository, articleRepository, userRepository)
+	searchHandler := web.NewSearchHandler(searchService, loggerV1)
+	historyRecordDAO := dao.NewGORMHistoryRecordDAO(db)
+	historyRecordRepository := repository.NewDBHistoryRecordRepository(historyRecordDAO)
+	historyRecordService := service.NewHistoryRecordService(historyRecordRepository)
+	historyHandler := web.NewHistoryHandler(historyRecordService, loggerV1)
+	jobDAO := dao.NewGORMJobDAO(db)
+	cronJobRepository := repository.NewPreemptJobRepository(jobDAO)
+	jobExecutionDAO := dao.NewGORMJobExecutionDAO(db)
+	jobExecutionRepository := repository.NewDBJobExecutionRepository(jobExecutionDAO)
+	cronJobService := service.NewCronJobService(cronJobRepository, jobExecutionRepository, loggerV1)
+	cronJobHandler := ioc.InitCronJobHandler(cronJobService, loggerV1)
+	engine := ioc.InitWebServer(v, userHandler, articleHandler, oAuth2DingDingHandler, commentHandler, collectionHandler, followHandler, feedHandler, searchHandler, historyHandler, cronJobHandler)
+	failureHandler := ioc.InitFailureHandler(syncProducer, loggerV1)
+	batchConfig := ioc.InitReadEventBatchConfig()
+	deduper := ioc.InitReadEventDeduper(cmdable)
+	interactiveReadEventConsumer := article.NewInteractiveReadEventConsumer(interactiveRepository, client, failureHandler, batchConfig, deduper, loggerV1)
+	articlePublishEventConsumer := ioc.InitArticlePublishEventConsumer(feedService, client, failureHandler, cmdable, loggerV1)
+	syncConsumer := ioc.InitSearchSyncConsumer(searchService, client, loggerV1)
+	historyRecordConsumer := article.NewHistoryRecordConsumer(historyRecordRepository, client, failureHandler, loggerV1)
+	v2 := ioc.InitConsumers(interactiveReadEventConsumer, articlePublishEventConsumer, syncConsumer, historyRecordConsumer)
 	rankingService := service.NewBatchRankingService(interactiveService, articleService)
 	rlockClient := ioc.InitRlockClient(cmdable)
-	rankingJob := ioc.InitRankingJob(rankingService, rlockClient, loggerV1)
-	cron := ioc.InitJobs(loggerV1, rankingJob)
+	nodeLoadCache := cache.NewNodeLoadRedisCache(cmdable)
+	loadBalancer := ioc.InitLoadBalancer(nodeLoadCache, loggerV1)
+	rankingJob := ioc.InitRankingJob(rankingService, rlockClient, loadBalancer, loggerV1)
+	historyCleanJob := ioc.InitHistoryCleanJob(historyRecordService, loggerV1)
+	outboxDAO := dao.NewGORMOutboxDAO(db)
+	outboxRepository := repository.NewDBOutboxRepository(outboxDAO)
+	outboxRelay := article.NewOutboxRelay(outboxRepository, syncProducer, loggerV1)
+	outboxRelayJob := ioc.InitOutboxRelayJob(outboxRelay, rlockClient, loggerV1)
+	outboxCleanJob := ioc.InitOutboxCleanJob(outboxRelay, loggerV1)
+	asyncSmsCleanJob := ioc.InitAsyncSmsCleanJob(asyncService, loggerV1)
+	cron := ioc.InitJobs(loggerV1, rankingJob, historyCleanJob, outboxRelayJob, outboxCleanJob, asyncSmsCleanJob)
+	scheduler := ioc.InitScheduler(cronJobService, loadBalancer, loggerV1)
+	lifecycle := ioc.InitLifecycle(loggerV1)
+	tokenManager := ioc.InitSMSTokenManager(loggerV1)
+	authService := ioc.InitSMSAPIService(asyncService, tokenManager, cmdable, loggerV1)
+	smsHandler := web.NewSMSHandler(authService)
+	internalSMSServer := ioc.InitInternalSMSServer(tokenManager, smsHandler)
 	app := &App{
-		server:    engine,
-		consumers: v2,
-		cron:      cron,
+		server:        engine,
+		consumers:     v2,
+		cron:          cron,
+		scheduler:     scheduler,
+		balancer:      loadBalancer,
+		rankingJob:    rankingJob,
+		outboxJob:     outboxRelayJob,
+		lifecycle:     lifecycle,
+		asyncProducer: asyncProducer,
+		asyncSms:      asyncService,
+		smsServer:     internalSMSServer,
 	}
 	return app
 }
@@ -72,4 +141,24 @@ func InitWebServer() *App {
 
 var interactiveSvcSet = wire.NewSet(dao.NewGORMInteractiveDAO, cache.NewInteractiveRedisCache, repository.NewCachedInteractiveRepository, service.NewInteractiveService)
 
+var commentSvcSet = wire.NewSet(dao.NewGORMCommentDAO, repository.NewCachedCommentRepository, service.NewCommentService)
+
+var collectionSvcSet = wire.NewSet(dao.NewGORMCollectionDAO, repository.NewCachedCollectionRepository, service.NewCollectionService)
+
+var followSvcSet = wire.NewSet(dao.NewGORMFollowRelationDAO, cache.NewFollowRedisCache, repository.NewCachedFollowRepository, follow.NewSaramaSyncProducer, service.NewFollowService)
+
+var feedSvcSet = wire.NewSet(cache.NewFeedRedisCache, repository.NewCachedFeedRepository, service.NewFeedService)
+
+var searchSvcSet = wire.NewSet(repository.NewMemorySearchRepository, service.NewSearchService)
+
+var historySvcSet = wire.NewSet(dao.NewGORMHistoryRecordDAO, repository.NewDBHistoryRecordRepository, service.NewHistoryRecordService)
+
+var jobSvcSet = wire.NewSet(dao.NewGORMJobDAO, dao.NewGORMJobExecutionDAO, repository.NewPreemptJobRepository, repository.NewDBJobExecutionRepository, service.NewCronJobService)
+
 var rankingSvcSet = wire.NewSet(cache.NewRankingRedisCache, repository.NewCachedRankingRepository, service.NewBatchRankingService)
+
+// 服务商不健康的时候，验证码之类的短信转异步发送
+var asyncSmsSvcSet = wire.NewSet(dao.NewGORMAsyncSmsDAO, repository.NewDBAsyncSmsRepository, ioc.InitAsyncSMSService, wire.Bind(new(sms.Service), new(*async.Service)), ioc.InitAsyncSmsCleanJob)
+
+// 内部的短信接口，其它服务通过 webook 的服务商链路发送短信
+var smsAPISvcSet = wire.NewSet(ioc.InitSMSTokenManager, ioc.InitSMSAPIService, web.NewSMSHandler, ioc.InitInternalSMSServer)